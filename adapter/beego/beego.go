@@ -5,7 +5,6 @@
 package beego
 
 import (
-	"bytes"
 	"errors"
 	"github.com/GoAdminGroup/go-admin/adapter"
 	gctx "github.com/GoAdminGroup/go-admin/context"
@@ -82,11 +81,7 @@ func (bee *Beego) AddHandler(method, path string, handlers gctx.Handlers) {
 			c.ResponseWriter.Header().Add(key, head[0])
 		}
 		c.ResponseWriter.WriteHeader(ctx.Response.StatusCode)
-		if ctx.Response.Body != nil {
-			buf := new(bytes.Buffer)
-			_, _ = buf.ReadFrom(ctx.Response.Body)
-			c.WriteString(buf.String())
-		}
+		ctx.WriteBody(c.ResponseWriter)
 	})
 }
 
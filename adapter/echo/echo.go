@@ -5,7 +5,6 @@
 package echo
 
 import (
-	"bytes"
 	"errors"
 	"github.com/GoAdminGroup/go-admin/adapter"
 	"github.com/GoAdminGroup/go-admin/context"
@@ -84,9 +83,8 @@ func (e *Echo) AddHandler(method, path string, handlers context.Handlers) {
 			c.Response().Header().Set(key, head[0])
 		}
 		if ctx.Response.Body != nil {
-			buf := new(bytes.Buffer)
-			_, _ = buf.ReadFrom(ctx.Response.Body)
-			_ = c.String(ctx.Response.StatusCode, buf.String())
+			c.Response().WriteHeader(ctx.Response.StatusCode)
+			ctx.WriteBody(c.Response())
 		}
 		return nil
 	})
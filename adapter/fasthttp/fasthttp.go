@@ -5,7 +5,6 @@
 package fasthttp
 
 import (
-	"bytes"
 	"errors"
 	"github.com/GoAdminGroup/go-admin/adapter"
 	"github.com/GoAdminGroup/go-admin/context"
@@ -94,9 +93,8 @@ func (fast *Fasthttp) AddHandler(method, path string, handlers context.Handlers)
 			c.Response.Header.Set(key, head[0])
 		}
 		if ctx.Response.Body != nil {
-			buf := new(bytes.Buffer)
-			_, _ = buf.ReadFrom(ctx.Response.Body)
-			_, _ = c.WriteString(buf.String())
+			// the stream is read and closed by fasthttp after the handler.
+			c.Response.SetBodyStream(ctx.Response.Body, -1)
 		}
 		c.Response.SetStatusCode(ctx.Response.StatusCode)
 	})
@@ -5,7 +5,6 @@
 package gf
 
 import (
-	"bytes"
 	"errors"
 	"github.com/GoAdminGroup/go-admin/adapter"
 	"github.com/GoAdminGroup/go-admin/context"
@@ -96,13 +95,8 @@ func (gf *Gf) AddHandler(method, path string, handlers context.Handlers) {
 			c.Response.Header().Add(key, head[0])
 		}
 
-		if ctx.Response.Body != nil {
-			buf := new(bytes.Buffer)
-			_, _ = buf.ReadFrom(ctx.Response.Body)
-			c.Response.WriteStatus(ctx.Response.StatusCode, buf.Bytes())
-		} else {
-			c.Response.WriteStatus(ctx.Response.StatusCode)
-		}
+		c.Response.WriteStatus(ctx.Response.StatusCode)
+		ctx.WriteBody(c.Response.Writer)
 	})
 }
 
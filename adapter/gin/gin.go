@@ -5,7 +5,6 @@
 package gin
 
 import (
-	"bytes"
 	"errors"
 	"github.com/GoAdminGroup/go-admin/adapter"
 	"github.com/GoAdminGroup/go-admin/context"
@@ -82,13 +81,8 @@ func (gins *Gin) AddHandler(method, path string, handlers context.Handlers) {
 		for key, head := range ctx.Response.Header {
 			c.Header(key, head[0])
 		}
-		if ctx.Response.Body != nil {
-			buf := new(bytes.Buffer)
-			_, _ = buf.ReadFrom(ctx.Response.Body)
-			c.String(ctx.Response.StatusCode, buf.String())
-		} else {
-			c.Status(ctx.Response.StatusCode)
-		}
+		c.Status(ctx.Response.StatusCode)
+		ctx.WriteBody(c.Writer)
 	})
 }
 
@@ -5,7 +5,6 @@
 package gorilla
 
 import (
-	"bytes"
 	"errors"
 	"github.com/GoAdminGroup/go-admin/adapter"
 	"github.com/GoAdminGroup/go-admin/context"
@@ -98,21 +97,8 @@ func (g *Gorilla) AddHandler(method, path string, handlers context.Handlers) {
 			w.Header().Add(key, head[0])
 		}
 
-		if ctx.Response.Body == nil {
-			w.WriteHeader(ctx.Response.StatusCode)
-			return
-		}
-
 		w.WriteHeader(ctx.Response.StatusCode)
-
-		buf := new(bytes.Buffer)
-		_, _ = buf.ReadFrom(ctx.Response.Body)
-
-		_, err := w.Write(buf.Bytes())
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
+		ctx.WriteBody(w)
 	}).Methods(strings.ToUpper(method))
 }
 
@@ -5,7 +5,6 @@
 package iris
 
 import (
-	"bytes"
 	"errors"
 	"github.com/GoAdminGroup/go-admin/adapter"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
@@ -89,11 +88,7 @@ func (is *Iris) AddHandler(method, path string, handlers context.Handlers) {
 			c.Header(key, head[0])
 		}
 		c.StatusCode(ctx.Response.StatusCode)
-		if ctx.Response.Body != nil {
-			buf := new(bytes.Buffer)
-			_, _ = buf.ReadFrom(ctx.Response.Body)
-			_, _ = c.WriteString(buf.String())
-		}
+		ctx.WriteBody(c.ResponseWriter())
 	})
 }
 
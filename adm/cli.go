@@ -127,5 +127,38 @@ func main() {
 		}
 	})
 
+	app.Command("migrate", "apply or roll back the schema migrations of the admin tables", func(cmd *cli.Cmd) {
+		cmd.Command("up", "apply all the pending migrations", func(cmd *cli.Cmd) {
+			var (
+				config = cmd.StringOpt("c config", "", "config ini path")
+			)
+
+			cmd.Action = func() {
+				migrateUp(*config)
+			}
+		})
+
+		cmd.Command("down", "roll back the last applied migrations", func(cmd *cli.Cmd) {
+			var (
+				config = cmd.StringOpt("c config", "", "config ini path")
+				steps  = cmd.IntOpt("n steps", 1, "the number of the migrations to roll back")
+			)
+
+			cmd.Action = func() {
+				migrateDown(*config, *steps)
+			}
+		})
+
+		cmd.Command("status", "show the applied and the pending migrations", func(cmd *cli.Cmd) {
+			var (
+				config = cmd.StringOpt("c config", "", "config ini path")
+			)
+
+			cmd.Action = func() {
+				migrateStatus(*config)
+			}
+		})
+	})
+
 	_ = app.Run(os.Args)
 }
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/go-ini/ini"
+)
+
+// dbConfig is the database section of the cli config file.
+type dbConfig struct {
+	driver, host, port, file, user, password, database string
+}
+
+func readDBConfig(section *ini.Section) dbConfig {
+	return dbConfig{
+		driver:   section.Key("driver").Value(),
+		host:     section.Key("host").Value(),
+		user:     section.Key("username").Value(),
+		port:     section.Key("port").Value(),
+		file:     section.Key("file").Value(),
+		password: section.Key("password").Value(),
+		database: section.Key("database").Value(),
+	}
+}
+
+// connectDB prompt for the database config which is not set, and return
+// the initialized connection with the completed config.
+func connectDB(dbCfg dbConfig) (db.Connection, dbConfig) {
+
+	if dbCfg.driver == "" {
+		var qs = []*survey.Question{
+			{
+				Name: "driver",
+				Prompt: &survey.Select{
+					Message: "choose a driver",
+					Options: []string{"mysql", "postgresql", "sqlite", "mssql"},
+					Default: "mysql",
+				},
+			},
+		}
+
+		var result = make(map[string]interface{})
+
+		err := survey.Ask(qs, &result)
+		checkError(err)
+		dbCfg.driver = result["driver"].(core.OptionAnswer).Value
+	}
+
+	var (
+		cfg  map[string]config.Database
+		conn = db.GetConnectionByDriver(dbCfg.driver)
+	)
+
+	if dbCfg.driver != "sqlite" {
+
+		defaultPort := "3306"
+		defaultUser := "root"
+
+		if dbCfg.driver == "postgresql" {
+			defaultPort = "5432"
+			defaultUser = "postgres"
+		}
+
+		if dbCfg.driver == "mssql" {
+			defaultPort = "1433"
+			defaultUser = "sa"
+		}
+
+		if dbCfg.host == "" {
+			dbCfg.host = promptWithDefault("sql address", "127.0.0.1")
+		}
+
+		if dbCfg.port == "" {
+			dbCfg.port = promptWithDefault("sql port", defaultPort)
+		}
+
+		if dbCfg.user == "" {
+			dbCfg.user = promptWithDefault("sql username", defaultUser)
+		}
+
+		if dbCfg.password == "" {
+			dbCfg.password = promptPassword()
+		}
+
+		if dbCfg.database == "" {
+			dbCfg.database = prompt("sql database name")
+		}
+
+		if conn == nil {
+			exitWithError("invalid db connection")
+			panic("invalid db connection")
+		}
+		cfg = map[string]config.Database{
+			"default": {
+				Host:       dbCfg.host,
+				Port:       dbCfg.port,
+				User:       dbCfg.user,
+				Pwd:        dbCfg.password,
+				Name:       dbCfg.database,
+				MaxIdleCon: 50,
+				MaxOpenCon: 150,
+				Driver:     dbCfg.driver,
+				File:       "",
+			},
+		}
+	} else {
+
+		if dbCfg.file == "" {
+			dbCfg.file = prompt("sql file")
+		}
+
+		if dbCfg.database == "" {
+			dbCfg.database = prompt("sql database name")
+		}
+
+		if conn == nil {
+			exitWithError("invalid db connection")
+			panic("invalid db connection")
+		}
+		cfg = map[string]config.Database{
+			"default": {
+				Driver: dbCfg.driver,
+				File:   dbCfg.file,
+			},
+		}
+	}
+
+	conn.InitDB(cfg)
+
+	return conn, dbCfg
+}
@@ -12,8 +12,6 @@ import (
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
-	"github.com/AlecAivazis/survey/v2/core"
-	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"github.com/GoAdminGroup/go-admin/template/types/form"
@@ -33,6 +31,10 @@ var systemGoAdminTables = []string{
 	"goadmin_role_permissions",
 	"goadmin_role_users",
 	"goadmin_user_permissions",
+	"goadmin_user_tokens",
+	"goadmin_user_two_factors",
+	"goadmin_user_lockouts",
+	"goadmin_migrations",
 }
 
 func generating(cfgFile string) {
@@ -41,8 +43,9 @@ func generating(cfgFile string) {
 	cliInfo()
 
 	var (
-		driverName, host, port, dbFile, user, password, connection, packageName, outputPath, database string
-		chooseTables                                                                                  = make([]string, 0)
+		dbCfg                               dbConfig
+		connection, packageName, outputPath string
+		chooseTables                        = make([]string, 0)
 	)
 
 	if cfgFile != "" {
@@ -55,13 +58,7 @@ func generating(cfgFile string) {
 		dbCfgModel, exist := cfgModel.GetSection("database")
 
 		if exist == nil {
-			driverName = dbCfgModel.Key("driver").Value()
-			host = dbCfgModel.Key("host").Value()
-			user = dbCfgModel.Key("username").Value()
-			port = dbCfgModel.Key("port").Value()
-			dbFile = dbCfgModel.Key("file").Value()
-			password = dbCfgModel.Key("password").Value()
-			database = dbCfgModel.Key("database").Value()
+			dbCfg = readDBConfig(dbCfgModel)
 			t := dbCfgModel.Key("tables").Value()
 			if t != "" {
 				chooseTables = strings.Split(t, ",")
@@ -81,107 +78,14 @@ func generating(cfgFile string) {
 	survey.SelectQuestionTemplate = strings.Replace(survey.SelectQuestionTemplate, "type to filter", "type to filter, enter to select", -1)
 	survey.MultiSelectQuestionTemplate = strings.Replace(survey.MultiSelectQuestionTemplate, "enter to select", "space to select", -1)
 
-	if driverName == "" {
-		var qs = []*survey.Question{
-			{
-				Name: "driver",
-				Prompt: &survey.Select{
-					Message: "choose a driver",
-					Options: []string{"mysql", "postgresql", "sqlite", "mssql"},
-					Default: "mysql",
-				},
-			},
-		}
-
-		var result = make(map[string]interface{})
-
-		err := survey.Ask(qs, &result)
-		checkError(err)
-		driverName = result["driver"].(core.OptionAnswer).Value
-	}
+	// step 1. test connection
+	conn, dbCfg := connectDB(dbCfg)
 
 	var (
-		cfg  map[string]config.Database
-		conn = db.GetConnectionByDriver(driverName)
+		driverName = dbCfg.driver
+		database   = dbCfg.database
 	)
 
-	if driverName != "sqlite" {
-
-		defaultPort := "3306"
-		defaultUser := "root"
-
-		if driverName == "postgresql" {
-			defaultPort = "5432"
-			defaultUser = "postgres"
-		}
-
-		if driverName == "mssql" {
-			defaultPort = "1433"
-			defaultUser = "sa"
-		}
-
-		if host == "" {
-			host = promptWithDefault("sql address", "127.0.0.1")
-		}
-
-		if port == "" {
-			port = promptWithDefault("sql port", defaultPort)
-		}
-
-		if user == "" {
-			user = promptWithDefault("sql username", defaultUser)
-		}
-
-		if password == "" {
-			password = promptPassword()
-		}
-
-		if database == "" {
-			database = prompt("sql database name")
-		}
-
-		if conn == nil {
-			exitWithError("invalid db connection")
-			panic("invalid db connection")
-		}
-		cfg = map[string]config.Database{
-			"default": {
-				Host:       host,
-				Port:       port,
-				User:       user,
-				Pwd:        password,
-				Name:       database,
-				MaxIdleCon: 50,
-				MaxOpenCon: 150,
-				Driver:     driverName,
-				File:       "",
-			},
-		}
-	} else {
-
-		if dbFile == "" {
-			dbFile = prompt("sql file")
-		}
-
-		if database == "" {
-			database = prompt("sql database name")
-		}
-
-		if conn == nil {
-			exitWithError("invalid db connection")
-			panic("invalid db connection")
-		}
-		cfg = map[string]config.Database{
-			"default": {
-				Driver: driverName,
-				File:   dbFile,
-			},
-		}
-	}
-
-	// step 1. test connection
-	conn.InitDB(cfg)
-
 	// step 2. show tables
 	if len(chooseTables) == 0 {
 		tableModels, _ := db.WithDriver(conn).ShowTables()
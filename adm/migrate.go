@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/GoAdminGroup/go-admin/modules/db/migration"
+	"github.com/go-ini/ini"
+	"github.com/mgutz/ansi"
+)
+
+func getMigrator(cfgFile string) *migration.Migrator {
+
+	var dbCfg dbConfig
+
+	if cfgFile != "" {
+		cfgModel, err := ini.Load(cfgFile)
+
+		if err != nil {
+			panic("wrong config file path")
+		}
+
+		if dbCfgModel, err := cfgModel.GetSection("database"); err == nil {
+			dbCfg = readDBConfig(dbCfgModel)
+		}
+	}
+
+	conn, _ := connectDB(dbCfg)
+
+	return migration.NewMigrator(conn)
+}
+
+func migrateUp(cfgFile string) {
+	applied, err := getMigrator(cfgFile).Up()
+	printMigrations(applied, "applied")
+	checkError(err)
+	if len(applied) == 0 {
+		fmt.Println("nothing to migrate")
+	}
+}
+
+func migrateDown(cfgFile string, steps int) {
+	rolledBack, err := getMigrator(cfgFile).Down(steps)
+	printMigrations(rolledBack, "rolled back")
+	checkError(err)
+	if len(rolledBack) == 0 {
+		fmt.Println("nothing to roll back")
+	}
+}
+
+func migrateStatus(cfgFile string) {
+	status, err := getMigrator(cfgFile).Status()
+	checkError(err)
+
+	fmt.Println()
+	for _, s := range status {
+		if s.Applied {
+			fmt.Println(ansi.Color("✔", "green") + " " + migrationName(s.Migration) + " applied at " + s.AppliedAt)
+		} else {
+			fmt.Println(ansi.Color("✘", "yellow") + " " + migrationName(s.Migration) + " pending")
+		}
+	}
+	fmt.Println()
+}
+
+func printMigrations(list []migration.Migration, action string) {
+	for _, m := range list {
+		fmt.Println(ansi.Color("✔", "green") + " " + migrationName(m) + " " + action)
+	}
+}
+
+func migrationName(m migration.Migration) string {
+	return strconv.FormatInt(m.Version, 10) + " " + m.Name
+}
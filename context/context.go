@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/GoAdminGroup/go-admin/modules/constant"
+	"io"
 	"io/ioutil"
 	"math"
 	"net"
@@ -173,6 +174,27 @@ func (ctx *Context) Data(code int, contentType string, data []byte) {
 	ctx.Response.Body = ioutil.NopCloser(bytes.NewBuffer(data))
 }
 
+// DataWithReader sets the reader as the body stream and updates the HTTP
+// code, the body is read by the adapter after the handlers return.
+func (ctx *Context) DataWithReader(code int, contentType string, reader io.ReadCloser) {
+	ctx.Response.StatusCode = code
+	ctx.AddHeader("Content-Type", contentType)
+	ctx.Response.Body = reader
+}
+
+// WriteBody copies the body stream to the writer of the adapter and closes
+// it, so that a body of a reader, such as an exported file, is streamed
+// rather than held in the memory.
+func (ctx *Context) WriteBody(w io.Writer) {
+	if ctx.Response.Body == nil {
+		return
+	}
+	defer func() {
+		_ = ctx.Response.Body.Close()
+	}()
+	_, _ = io.Copy(w, ctx.Response.Body)
+}
+
 // Redirect add redirect url to header.
 func (ctx *Context) Redirect(path string) {
 	ctx.Response.StatusCode = http.StatusFound
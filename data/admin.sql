@@ -68,10 +68,15 @@ CREATE TABLE `goadmin_operation_log` (
   `method` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL,
   `ip` varchar(15) COLLATE utf8mb4_unicode_ci NOT NULL,
   `input` text COLLATE utf8mb4_unicode_ci NOT NULL,
+  `prefix` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `pk` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `op` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `diff` text COLLATE utf8mb4_unicode_ci,
   `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
   `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
-  KEY `admin_operation_log_user_id_index` (`user_id`)
+  KEY `admin_operation_log_user_id_index` (`user_id`),
+  KEY `admin_operation_log_prefix_pk_index` (`prefix`,`pk`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 
@@ -193,6 +198,7 @@ CREATE TABLE `goadmin_roles` (
   `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
   `name` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
   `slug` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `two_factor_required` tinyint(4) unsigned NOT NULL DEFAULT '0',
   `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
   `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
   PRIMARY KEY (`id`),
@@ -211,6 +217,26 @@ VALUES
 UNLOCK TABLES;
 
 
+# Dump of table goadmin_saved_views
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_saved_views`;
+
+CREATE TABLE `goadmin_saved_views` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) unsigned NOT NULL,
+  `role_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `prefix` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `params` varchar(3000) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `admin_saved_views_prefix_index` (`prefix`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
 # Dump of table goadmin_session
 # ------------------------------------------------------------
 
@@ -227,6 +253,24 @@ CREATE TABLE `goadmin_session` (
 
 
 
+# Dump of table goadmin_user_lockouts
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_user_lockouts`;
+
+CREATE TABLE `goadmin_user_lockouts` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) unsigned NOT NULL,
+  `failures` int(11) unsigned NOT NULL DEFAULT '0',
+  `locked_until` bigint(20) NOT NULL DEFAULT '0',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `admin_user_lockouts_user_id_unique` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
 # Dump of table goadmin_user_permissions
 # ------------------------------------------------------------
 
@@ -252,6 +296,45 @@ VALUES
 UNLOCK TABLES;
 
 
+# Dump of table goadmin_user_tokens
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_user_tokens`;
+
+CREATE TABLE `goadmin_user_tokens` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) unsigned NOT NULL,
+  `name` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `token` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `last_used_at` timestamp NULL DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `admin_user_tokens_token_unique` (`token`),
+  KEY `admin_user_tokens_user_id_index` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
+# Dump of table goadmin_user_two_factors
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_user_two_factors`;
+
+CREATE TABLE `goadmin_user_two_factors` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) unsigned NOT NULL,
+  `secret` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `recovery_codes` varchar(1000) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `last_counter` bigint(20) NOT NULL DEFAULT '0',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `admin_user_two_factors_user_id_unique` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
 # Dump of table goadmin_users
 # ------------------------------------------------------------
 
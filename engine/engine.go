@@ -13,6 +13,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/migration"
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/logger"
 	"github.com/GoAdminGroup/go-admin/modules/menu"
@@ -109,6 +110,22 @@ func (eng *Engine) InitDatabase() *Engine {
 	defaultConnection := db.GetConnection(eng.Services)
 	defaultAdapter.SetConnection(defaultConnection)
 	eng.Adapter.SetConnection(defaultConnection)
+	if eng.config.AutoMigrate {
+		return eng.Migrate()
+	}
+	return eng
+}
+
+// Migrate apply the pending schema migrations to the default connection,
+// it panics if a migration fails.
+func (eng *Engine) Migrate() *Engine {
+	applied, err := migration.NewMigrator(db.GetConnection(eng.Services)).Up()
+	for _, m := range applied {
+		logger.Info(fmt.Sprintf("migration %d %s applied", m.Version, m.Name))
+	}
+	if err != nil {
+		panic(err)
+	}
 	return eng
 }
 
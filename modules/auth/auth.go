@@ -7,11 +7,8 @@ package auth
 import (
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/db"
-	"github.com/GoAdminGroup/go-admin/modules/service"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
-	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"golang.org/x/crypto/bcrypt"
-	"sync"
 )
 
 // Auth get the user model from Context.
@@ -64,58 +61,7 @@ func DelCookie(ctx *context.Context, conn db.Connection) bool {
 	return true
 }
 
-type TokenService struct {
-	tokens CSRFToken
-	lock   sync.Mutex
-}
-
-func (s *TokenService) Name() string {
-	return "token_csrf_helper"
-}
-
-func init() {
-	service.Register("token_csrf_helper", func() (service.Service, error) {
-		return &TokenService{
-			tokens: make(CSRFToken, 0),
-		}, nil
-	})
-}
-
-const (
-	TokenServiceKey = "token_csrf_helper"
-	ServiceKey      = "auth"
-)
-
-func GetTokenService(s interface{}) *TokenService {
-	if srv, ok := s.(*TokenService); ok {
-		return srv
-	}
-	panic("wrong service")
-}
-
-// AddToken add the token to the CSRFToken.
-func (s *TokenService) AddToken() string {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-	tokenStr := modules.Uuid()
-	s.tokens = append(s.tokens, tokenStr)
-	return tokenStr
-}
-
-// CheckToken check the given token with tokens in the CSRFToken, if exist
-// return true.
-func (s *TokenService) CheckToken(toCheckToken string) bool {
-	for i := 0; i < len(s.tokens); i++ {
-		if (s.tokens)[i] == toCheckToken {
-			s.tokens = append((s.tokens)[:i], (s.tokens)[i+1:]...)
-			return true
-		}
-	}
-	return false
-}
-
-// CSRFToken is type of a csrf token list.
-type CSRFToken []string
+const ServiceKey = "auth"
 
 type Processor func(ctx *context.Context) (model models.UserModel, exist bool)
 
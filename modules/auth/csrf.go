@@ -0,0 +1,279 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TokenServiceKey = "token_csrf_helper"
+
+	// maxSessionTokens is the max number of the tokens of a session, the
+	// oldest ones of the session are dropped when it is exceeded.
+	maxSessionTokens = 64
+
+	defaultTokenLifeTime = 2 * time.Hour
+)
+
+// csrfToken is a token bound to a session.
+type csrfToken struct {
+	sid     string
+	expires time.Time
+}
+
+// TokenService keeps the csrf tokens. A token is bound to the session it is
+// issued for, and it is valid until it expires, which is the life time of
+// the session, so that a page can post more than once with the token.
+//
+// The tokens of the pages shown before login are not kept but signed with
+// the secret of the service, see AddAnonymousToken.
+type TokenService struct {
+	tokens map[string]csrfToken
+	// sessions are the tokens of every session, in the order issued.
+	sessions map[string][]string
+	// queue is all the tokens in the order issued, which is also the order
+	// of expiry.
+	queue  []string
+	secret []byte
+	lock   sync.Mutex
+}
+
+func (s *TokenService) Name() string {
+	return TokenServiceKey
+}
+
+func init() {
+	service.Register(TokenServiceKey, func() (service.Service, error) {
+		return NewTokenService(), nil
+	})
+}
+
+// NewTokenService return an empty TokenService.
+func NewTokenService() *TokenService {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic("generate the csrf secret error: " + err.Error())
+	}
+	return &TokenService{
+		tokens:   make(map[string]csrfToken),
+		sessions: make(map[string][]string),
+		queue:    make([]string, 0),
+		secret:   secret,
+	}
+}
+
+func GetTokenService(s interface{}) *TokenService {
+	if srv, ok := s.(*TokenService); ok {
+		return srv
+	}
+	panic("wrong service")
+}
+
+// AddToken issue a token bound to the session of the Context.
+func (s *TokenService) AddToken(ctx *context.Context) string {
+	return s.add(sessionID(ctx), time.Now())
+}
+
+// CheckToken check the given token is issued for the session of the
+// Context, or is the anonymous token of the Context, and not expired.
+func (s *TokenService) CheckToken(ctx *context.Context, token string) bool {
+	now := time.Now()
+	if s.check(sessionID(ctx), token, now) {
+		return true
+	}
+	cookie, err := ctx.Request.Cookie(constant.CSRFLoginCookieKey)
+	return err == nil && hmac.Equal([]byte(cookie.Value), []byte(token)) && s.verify(token, now)
+}
+
+// AddAnonymousToken return the token of the pages shown before login, such
+// as the login page, which have no session to bind the tokens to. The token
+// is signed rather than kept, so that the anonymous requests take no room
+// of the service, and it is set to a http only cookie, so that only the
+// page which shows the token can post with it.
+func (s *TokenService) AddAnonymousToken(ctx *context.Context) string {
+	now := time.Now()
+	if cookie, err := ctx.Request.Cookie(constant.CSRFLoginCookieKey); err == nil && s.verify(cookie.Value, now) {
+		return cookie.Value
+	}
+	token := s.sign(now.Add(tokenLifeTime()))
+	cookie := http.Cookie{
+		Name:     constant.CSRFLoginCookieKey,
+		Value:    token,
+		MaxAge:   int(tokenLifeTime() / time.Second),
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	}
+	if config.Get().Domain != "" {
+		cookie.Domain = config.Get().Domain
+	}
+	ctx.SetCookie(&cookie)
+	return token
+}
+
+// sign return a token expiring at given time signed with the secret.
+func (s *TokenService) sign(expires time.Time) string {
+	payload := strconv.FormatInt(expires.Unix(), 10) + "." + modules.Uuid()
+	return payload + "." + s.signature(payload)
+}
+
+// verify check the token is signed with the secret and not expired.
+func (s *TokenService) verify(token string, now time.Time) bool {
+	i := strings.LastIndex(token, ".")
+	if i < 0 || !hmac.Equal([]byte(token[i+1:]), []byte(s.signature(token[:i]))) {
+		return false
+	}
+	expires, err := strconv.ParseInt(token[:strings.Index(token, ".")], 10, 64)
+	return err == nil && now.Unix() < expires
+}
+
+func (s *TokenService) signature(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	_, _ = mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// DeleteTokens delete all the tokens of the session of the Context, which
+// should be called when the session ends.
+func (s *TokenService) DeleteTokens(ctx *context.Context) {
+	sid := sessionID(ctx)
+	if sid == "" {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, token := range s.sessions[sid] {
+		delete(s.tokens, token)
+	}
+	delete(s.sessions, sid)
+}
+
+// SetTokenCookie make sure the csrf cookie holds a valid token of the
+// session, so that the scripts of the page can post with it.
+func (s *TokenService) SetTokenCookie(ctx *context.Context) {
+	if cookie, err := ctx.Request.Cookie(constant.CSRFCookieKey); err == nil && s.CheckToken(ctx, cookie.Value) {
+		return
+	}
+	cookie := http.Cookie{
+		Name:     constant.CSRFCookieKey,
+		Value:    s.AddToken(ctx),
+		MaxAge:   int(tokenLifeTime() / time.Second),
+		Path:     "/",
+		SameSite: http.SameSiteStrictMode,
+	}
+	if config.Get().Domain != "" {
+		cookie.Domain = config.Get().Domain
+	}
+	ctx.SetCookie(&cookie)
+}
+
+func (s *TokenService) add(sid string, now time.Time) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.clean(now)
+
+	token := modules.Uuid()
+	s.tokens[token] = csrfToken{sid: sid, expires: now.Add(tokenLifeTime())}
+	s.queue = append(s.queue, token)
+	s.sessions[sid] = append(s.sessions[sid], token)
+
+	// the limit is of every session, so that the requests of a session never
+	// drop the tokens of the others.
+	if len(s.sessions[sid]) > maxSessionTokens {
+		delete(s.tokens, s.sessions[sid][0])
+		s.sessions[sid] = s.sessions[sid][1:]
+	}
+
+	// the queue holds the tokens dropped of the sessions until they expire,
+	// so it is compacted when it grows too long.
+	if len(s.queue) > 2*len(s.tokens)+maxSessionTokens {
+		queue := make([]string, 0, len(s.tokens))
+		for _, t := range s.queue {
+			if _, ok := s.tokens[t]; ok {
+				queue = append(queue, t)
+			}
+		}
+		s.queue = queue
+	}
+
+	return token
+}
+
+func (s *TokenService) check(sid, token string, now time.Time) bool {
+	if sid == "" || token == "" {
+		return false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	t, ok := s.tokens[token]
+	return ok && t.sid == sid && now.Before(t.expires)
+}
+
+// clean drop the expired tokens.
+func (s *TokenService) clean(now time.Time) {
+	for len(s.queue) > 0 {
+		t, ok := s.tokens[s.queue[0]]
+		if ok && now.Before(t.expires) {
+			return
+		}
+		s.pop()
+	}
+}
+
+// pop drop the oldest token.
+func (s *TokenService) pop() {
+	token := s.queue[0]
+	s.queue = s.queue[1:]
+
+	t, ok := s.tokens[token]
+	if !ok {
+		return
+	}
+	delete(s.tokens, token)
+
+	tokens := s.sessions[t.sid]
+	for i := range tokens {
+		if tokens[i] == token {
+			tokens = append(tokens[:i], tokens[i+1:]...)
+			break
+		}
+	}
+	if len(tokens) == 0 {
+		delete(s.sessions, t.sid)
+	} else {
+		s.sessions[t.sid] = tokens
+	}
+}
+
+func tokenLifeTime() time.Duration {
+	if config.Get().SessionLifeTime > 0 {
+		return time.Duration(config.Get().SessionLifeTime) * time.Second
+	}
+	return defaultTokenLifeTime
+}
+
+// sessionID return the id of the session of the Context, which is empty if
+// there is no session.
+func sessionID(ctx *context.Context) string {
+	if cookie, err := ctx.Request.Cookie(DefaultCookieKey); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
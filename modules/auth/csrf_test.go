@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/constant"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenService(t *testing.T) {
+	s := NewTokenService()
+	now := time.Now()
+
+	token := s.add("sid1", now)
+
+	assert.True(t, s.check("sid1", token, now))
+	assert.True(t, s.check("sid1", token, now.Add(time.Minute)), "a token can be used more than once")
+	assert.False(t, s.check("sid2", token, now), "a token is bound to the session")
+	assert.False(t, s.check("", token, now))
+	assert.False(t, s.check("sid1", "", now))
+	assert.False(t, s.check("sid1", token, now.Add(defaultTokenLifeTime)), "a token expires")
+
+	// the expired tokens are dropped when a new one is issued.
+	s.add("sid2", now.Add(defaultTokenLifeTime))
+	assert.Equal(t, 1, len(s.tokens))
+	assert.Equal(t, 1, len(s.sessions))
+}
+
+func TestTokenServiceBounded(t *testing.T) {
+	s := NewTokenService()
+	now := time.Now()
+
+	first := s.add("sid1", now)
+	for i := 0; i < maxSessionTokens; i++ {
+		s.add("sid1", now)
+	}
+	assert.False(t, s.check("sid1", first, now), "the oldest token of the session is dropped")
+	assert.Equal(t, maxSessionTokens, len(s.sessions["sid1"]))
+
+	// a session issuing many tokens never drops the ones of the others.
+	other := s.add("sid2", now)
+	for i := 0; i < 100*maxSessionTokens; i++ {
+		s.add("sid1", now)
+	}
+	assert.True(t, s.check("sid2", other, now))
+	assert.Equal(t, maxSessionTokens+1, len(s.tokens))
+	assert.True(t, len(s.queue) <= 2*len(s.tokens)+maxSessionTokens+1)
+
+	for i := 0; i < 100; i++ {
+		s.add("sid"+strconv.Itoa(i+3), now)
+	}
+	assert.True(t, s.check("sid2", other, now))
+	assert.Equal(t, maxSessionTokens+101, len(s.tokens))
+}
+
+func TestTokenServiceAnonymous(t *testing.T) {
+	s := NewTokenService()
+	now := time.Now()
+
+	token := s.sign(now.Add(time.Hour))
+	assert.True(t, s.verify(token, now))
+	assert.False(t, s.verify(token, now.Add(time.Hour)), "a token expires")
+	assert.False(t, s.verify(token+"a", now))
+	assert.False(t, s.verify("", now))
+	assert.False(t, s.verify(strings.Replace(token, ".", "0.", 1), now), "the expiry can not be changed")
+	assert.False(t, NewTokenService().verify(token, now), "a token is signed by the service")
+
+	// the token is checked against the cookie set by the page.
+	ctx := context.NewContext(httptest.NewRequest(http.MethodGet, "/admin/login", nil))
+	token = s.AddAnonymousToken(ctx)
+	cookie := ctx.Response.Header.Get("Set-Cookie")
+	assert.Contains(t, cookie, constant.CSRFLoginCookieKey+"="+token)
+	assert.Contains(t, cookie, "HttpOnly")
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/signin", nil)
+	ctx = context.NewContext(req)
+	assert.False(t, s.CheckToken(ctx, token), "the token must be posted with the cookie")
+	req.AddCookie(&http.Cookie{Name: constant.CSRFLoginCookieKey, Value: token})
+	assert.True(t, s.CheckToken(ctx, token))
+	assert.False(t, s.CheckToken(ctx, s.sign(now.Add(time.Hour))), "the token must be the one of the cookie")
+	assert.False(t, s.CheckToken(ctx, ""))
+
+	// the valid token of the cookie is kept.
+	assert.Equal(t, token, s.AddAnonymousToken(ctx))
+	assert.Equal(t, 0, len(s.tokens))
+}
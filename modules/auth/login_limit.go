@@ -0,0 +1,189 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// loginLimiterMaxKeys is the max number of the counted keys. The expired
+// failures are pruned when a new key is counted at the max, and then the
+// keys of the oldest failures if there are still too many of them.
+const loginLimiterMaxKeys = 10000
+
+// LoginLimiter counts the failed logins by the client ip and by the username
+// in memory, see config.LoginLimit.
+type LoginLimiter struct {
+	limit    config.LoginLimit
+	lock     sync.Mutex
+	failures map[string][]time.Time
+}
+
+var (
+	loginLimiter     *LoginLimiter
+	loginLimiterOnce sync.Once
+)
+
+// NewLoginLimiter return a new LoginLimiter with given limit.
+func NewLoginLimiter(limit config.LoginLimit) *LoginLimiter {
+	return &LoginLimiter{
+		limit:    limit,
+		failures: make(map[string][]time.Time),
+	}
+}
+
+// GetLoginLimiter return the LoginLimiter of the global config.
+func GetLoginLimiter() *LoginLimiter {
+	loginLimiterOnce.Do(func() {
+		loginLimiter = NewLoginLimiter(config.Get().LoginLimit)
+	})
+	return loginLimiter
+}
+
+// Allow check the login of given client ip and username is allowed or not.
+func (l *LoginLimiter) Allow(ip, username string) bool {
+	now := time.Now()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return !reachLimit(len(l.recent(ipLimitKey(ip), now)), l.limit.IPMaxFailures) &&
+		!reachLimit(len(l.recent(usernameLimitKey(username), now)), l.limit.UsernameMaxFailures)
+}
+
+// Fail record a failed login of given client ip and username.
+func (l *LoginLimiter) Fail(ip, username string) {
+	now := time.Now()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for _, key := range []string{ipLimitKey(ip), usernameLimitKey(username)} {
+		if _, ok := l.failures[key]; !ok && len(l.failures) >= loginLimiterMaxKeys {
+			l.prune(now)
+		}
+		l.failures[key] = append(l.recent(key, now), now)
+	}
+}
+
+// prune drop the expired failures, and then the keys of the oldest failures
+// until a half of the max keys are left, so that the keys are not pruned
+// again for many failures.
+func (l *LoginLimiter) prune(now time.Time) {
+	for key := range l.failures {
+		l.recent(key, now)
+	}
+
+	if len(l.failures) < loginLimiterMaxKeys/2 {
+		return
+	}
+
+	keys := make([]string, 0, len(l.failures))
+	for key := range l.failures {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := l.failures[keys[i]], l.failures[keys[j]]
+		return a[len(a)-1].Before(b[len(b)-1])
+	})
+	for _, key := range keys[:len(keys)-loginLimiterMaxKeys/2] {
+		delete(l.failures, key)
+	}
+}
+
+// Reset clear the failures of given username, which is called after a
+// successful login or when the user is unlocked.
+func (l *LoginLimiter) Reset(username string) {
+	l.lock.Lock()
+	delete(l.failures, usernameLimitKey(username))
+	l.lock.Unlock()
+}
+
+// recent return the failures of the key within the window, and the key
+// is removed when all of its failures are expired.
+func (l *LoginLimiter) recent(key string, now time.Time) []time.Time {
+	failures := l.failures[key]
+	start := now.Add(-time.Duration(l.limit.Window) * time.Second)
+	i := 0
+	for i < len(failures) && !failures[i].After(start) {
+		i++
+	}
+	if i == len(failures) {
+		delete(l.failures, key)
+		return nil
+	}
+	if i > 0 {
+		failures = append(failures[:0], failures[i:]...)
+		l.failures[key] = failures
+	}
+	return failures
+}
+
+// ClientIP return the ip of the client of the request which the failed
+// logins are counted by, see config.LoginLimit.
+func ClientIP(ctx *context.Context) string {
+	return clientIP(ctx.Request, config.Get().LoginLimit.TrustedProxies)
+}
+
+// clientIP return the remote address of the request, or the forwarded ip
+// if it is sent by the trusted proxies. The forwarded ips are walked from
+// the nearest proxy, and the first one not trusted is the client.
+func clientIP(req *http.Request, proxies []string) string {
+	ip, _, err := net.SplitHostPort(strings.TrimSpace(req.RemoteAddr))
+	if err != nil {
+		ip = strings.TrimSpace(req.RemoteAddr)
+	}
+
+	if !trustedProxy(ip, proxies) {
+		return ip
+	}
+
+	forwarded := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		if forwardedIP := strings.TrimSpace(forwarded[i]); forwardedIP != "" && !trustedProxy(forwardedIP, proxies) {
+			return forwardedIP
+		}
+	}
+
+	if realIP := strings.TrimSpace(req.Header.Get("X-Real-Ip")); realIP != "" {
+		return realIP
+	}
+
+	return ip
+}
+
+func trustedProxy(ip string, proxies []string) bool {
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if proxy == ip {
+				return true
+			}
+			continue
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil && network.Contains(net.ParseIP(ip)) {
+			return true
+		}
+	}
+	return false
+}
+
+func reachLimit(failures, max int) bool {
+	return max > 0 && failures >= max
+}
+
+func ipLimitKey(ip string) string {
+	return "ip:" + ip
+}
+
+func usernameLimitKey(username string) string {
+	return "username:" + strings.ToLower(username)
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLoginLimiter(t *testing.T) {
+	l := NewLoginLimiter(config.LoginLimit{
+		Window:              60,
+		IPMaxFailures:       3,
+		UsernameMaxFailures: 2,
+	})
+
+	assert.Equal(t, true, l.Allow("127.0.0.1", "admin"))
+
+	l.Fail("127.0.0.1", "admin")
+	assert.Equal(t, true, l.Allow("127.0.0.1", "admin"))
+
+	l.Fail("127.0.0.1", "Admin")
+	assert.Equal(t, false, l.Allow("127.0.0.2", "admin"))
+	assert.Equal(t, true, l.Allow("127.0.0.1", "operator"))
+
+	l.Reset("admin")
+	assert.Equal(t, true, l.Allow("127.0.0.1", "admin"))
+
+	l.Fail("127.0.0.1", "operator")
+	assert.Equal(t, false, l.Allow("127.0.0.1", "admin"))
+	assert.Equal(t, true, l.Allow("127.0.0.2", "admin"))
+
+	// the failures out of the window are not counted.
+	l.failures[ipLimitKey("127.0.0.1")] = []time.Time{time.Now().Add(-time.Hour)}
+	assert.Equal(t, true, l.Allow("127.0.0.1", "admin"))
+	_, ok := l.failures[ipLimitKey("127.0.0.1")]
+	assert.Equal(t, false, ok)
+
+	off := NewLoginLimiter(config.LoginLimit{Window: 60, IPMaxFailures: -1, UsernameMaxFailures: -1})
+	for i := 0; i < 5; i++ {
+		off.Fail("127.0.0.1", "admin")
+	}
+	assert.Equal(t, true, off.Allow("127.0.0.1", "admin"))
+}
+
+func TestLoginLimiterBounded(t *testing.T) {
+	l := NewLoginLimiter(config.LoginLimit{Window: 60, IPMaxFailures: 3, UsernameMaxFailures: 3})
+
+	l.Fail("127.0.0.1", "admin")
+	l.Fail("127.0.0.1", "admin")
+	for i := 0; len(l.failures) < loginLimiterMaxKeys; i++ {
+		l.Fail("10.0.0."+strconv.Itoa(i), "user"+strconv.Itoa(i))
+	}
+	l.Fail("127.0.0.1", "admin")
+	assert.Equal(t, false, l.Allow("127.0.0.1", "admin"))
+
+	// the keys of the oldest failures are dropped when there are too many.
+	l.Fail("10.0.1.1", "operator")
+	assert.Equal(t, loginLimiterMaxKeys/2+2, len(l.failures))
+	assert.Equal(t, false, l.Allow("127.0.0.1", "admin"))
+	_, ok := l.failures[ipLimitKey("10.0.0.0")]
+	assert.Equal(t, false, ok)
+}
+
+func TestClientIP(t *testing.T) {
+	req := httptest.NewRequest("POST", "/signin", nil)
+	req.RemoteAddr = "10.0.0.1:5000"
+	req.Header.Set("X-Forwarded-For", "1.1.1.1, 2.2.2.2")
+	req.Header.Set("X-Real-Ip", "3.3.3.3")
+
+	// the headers sent by the clients are not trusted.
+	assert.Equal(t, "10.0.0.1", clientIP(req, nil))
+	assert.Equal(t, "10.0.0.1", clientIP(req, []string{"10.0.0.2"}))
+
+	assert.Equal(t, "2.2.2.2", clientIP(req, []string{"10.0.0.1"}))
+	assert.Equal(t, "1.1.1.1", clientIP(req, []string{"10.0.0.0/8", "2.2.2.2"}))
+
+	req.Header.Del("X-Forwarded-For")
+	assert.Equal(t, "3.3.3.3", clientIP(req, []string{"10.0.0.0/8"}))
+}
@@ -6,6 +6,7 @@ package auth
 
 import (
 	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/cache"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/language"
@@ -14,7 +15,10 @@ import (
 	template2 "github.com/GoAdminGroup/go-admin/template"
 	"github.com/GoAdminGroup/go-admin/template/types"
 	"html/template"
+	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
 // Invoker contains the callback functions which are used
@@ -23,6 +27,7 @@ type Invoker struct {
 	prefix                 string
 	authFailCallback       MiddlewareCallback
 	permissionDenyCallback MiddlewareCallback
+	permissionURL          func(ctx *context.Context) string
 	conn                   db.Connection
 }
 
@@ -57,6 +62,29 @@ func DefaultInvoker(conn db.Connection) *Invoker {
 	}
 }
 
+// ApiMiddleware is the auth middleware of the json api. An api route is
+// checked with the permission of the page url it mirrors, and the failures
+// are responded in json instead of a redirection or an alert page.
+func ApiMiddleware(conn db.Connection) context.Handler {
+	return DefaultInvoker(conn).
+		SetAuthFailCallback(func(ctx *context.Context) {
+			ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"code": http.StatusUnauthorized,
+				"msg":  language.Get("unauthorized"),
+			})
+		}).
+		SetPermissionDenyCallback(func(ctx *context.Context) {
+			ctx.JSON(http.StatusForbidden, map[string]interface{}{
+				"code": http.StatusForbidden,
+				"msg":  language.Get("permission denied"),
+			})
+		}).
+		SetPermissionURL(func(ctx *context.Context) string {
+			return config.Get().URLRemoveApiPrefix(ctx.Request.URL.String())
+		}).
+		Middleware()
+}
+
 // SetPrefix return the default Invoker with the given prefix.
 func SetPrefix(prefix string, conn db.Connection) *Invoker {
 	i := DefaultInvoker(conn)
@@ -76,13 +104,29 @@ func (invoker *Invoker) SetPermissionDenyCallback(callback MiddlewareCallback) *
 	return invoker
 }
 
+// SetPermissionURL set the function which returns the url used in the
+// permission check of Invoker. Default is the request url.
+func (invoker *Invoker) SetPermissionURL(fn func(ctx *context.Context) string) *Invoker {
+	invoker.permissionURL = fn
+	return invoker
+}
+
 // MiddlewareCallback is type of callback function.
 type MiddlewareCallback func(ctx *context.Context)
 
 // Middleware get the auth middleware from Invoker.
 func (invoker *Invoker) Middleware() context.Handler {
 	return func(ctx *context.Context) {
-		user, authOk, permissionOk := Filter(ctx, invoker.conn)
+		var (
+			user                 models.UserModel
+			authOk, permissionOk bool
+		)
+
+		if invoker.permissionURL != nil {
+			user, authOk, permissionOk = FilterWithURL(ctx, invoker.permissionURL(ctx), invoker.conn)
+		} else {
+			user, authOk, permissionOk = Filter(ctx, invoker.conn)
+		}
 
 		if authOk && permissionOk {
 			ctx.SetUserValue("user", user)
@@ -108,13 +152,28 @@ func (invoker *Invoker) Middleware() context.Handler {
 // Filter retrieve the user model from Context and check the permission
 // at the same time.
 func Filter(ctx *context.Context, conn db.Connection) (models.UserModel, bool, bool) {
+	return FilterWithURL(ctx, ctx.Request.URL.String(), conn)
+}
+
+// FilterWithURL is the same as Filter but checks the permission with the
+// given url instead of the request url.
+func FilterWithURL(ctx *context.Context, u string, conn db.Connection) (models.UserModel, bool, bool) {
 	var (
 		id   float64
 		ok   bool
 		user = models.User()
 	)
 
-	if id, ok = InitSession(ctx, conn).Get("user_id").(float64); !ok {
+	if token := BearerToken(ctx); token != "" {
+		userToken := models.UserToken().SetConn(conn).FindByToken(token)
+
+		if userToken.IsEmpty() {
+			return user, false, false
+		}
+
+		userToken.Used()
+		id = float64(userToken.UserId)
+	} else if id, ok = InitSession(ctx, conn).Get("user_id").(float64); !ok {
 		return user, false, false
 	}
 
@@ -124,7 +183,16 @@ func Filter(ctx *context.Context, conn db.Connection) (models.UserModel, bool, b
 		return user, false, false
 	}
 
-	return user, true, CheckPermissions(user, ctx.Request.URL.String(), ctx.Method(), ctx.PostForm())
+	return user, true, CheckPermissions(user, u, ctx.Method(), ctx.PostForm())
+}
+
+// BearerToken return the personal api token of the Authorization header.
+func BearerToken(ctx *context.Context) string {
+	header := ctx.Headers("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
 }
 
 const defaultUserIDSesKey = "user_id"
@@ -154,9 +222,27 @@ func GetCurUser(sesKey string, conn db.Connection) (user models.UserModel, ok bo
 	return GetCurUserByID(id, conn)
 }
 
-// GetCurUserByID return the user model of given user id.
+// userTables are the tables which the user model with the roles,
+// permissions and menus is read from.
+func userTables() []string {
+	return []string{config.Get().AuthUserTable, "goadmin_role_users", "goadmin_roles",
+		"goadmin_role_permissions", "goadmin_user_permissions", "goadmin_permissions",
+		"goadmin_role_menu", "goadmin_menu"}
+}
+
+// GetCurUserByID return the user model of given user id, which is cached
+// until it expires or the tables of it are written.
 func GetCurUserByID(id int64, conn db.Connection) (user models.UserModel, ok bool) {
 
+	key := "user:" + strconv.FormatInt(id, 10)
+
+	if cached, found := cache.Default().Get(key); found {
+		user = cached.(models.UserModel)
+		return user, true
+	}
+
+	generation := cache.Default().Generation(userTables()...)
+
 	user = models.User().SetConn(conn).Find(id)
 
 	if user.IsEmpty() {
@@ -174,6 +260,10 @@ func GetCurUserByID(id int64, conn db.Connection) (user models.UserModel, ok boo
 
 	ok = user.HasMenu()
 
+	if ok {
+		cache.Default().SetWithGeneration(key, user, generation, userTables()...)
+	}
+
 	return
 }
 
@@ -1,9 +1,11 @@
 package auth
 
 import (
+	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/stretchr/testify/assert"
+	"net/http"
 	"net/url"
 	"testing"
 )
@@ -74,3 +76,19 @@ func TestCheckPermissions(t *testing.T) {
 	assert.Equal(t, CheckPermissions(user, "/admin/info/user_list?user_type=20", "get", param), true)
 	assert.Equal(t, CheckPermissions(user, "/admin/info/user_list?__goadmin_edit_pk=3&user_type=20", "get", param), true)
 }
+
+func TestBearerToken(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/admin/api/info/manager", nil)
+	ctx := context.NewContext(req)
+
+	assert.Equal(t, BearerToken(ctx), "")
+
+	req.Header.Set("Authorization", "Bearer abc123")
+	assert.Equal(t, BearerToken(ctx), "abc123")
+
+	req.Header.Set("Authorization", "bearer  abc123 ")
+	assert.Equal(t, BearerToken(ctx), "abc123")
+
+	req.Header.Set("Authorization", "Basic abc123")
+	assert.Equal(t, BearerToken(ctx), "")
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
-	"github.com/GoAdminGroup/go-admin/modules/logger"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"net/http"
 	"strconv"
@@ -35,7 +34,7 @@ type PersistenceDriver interface {
 
 // GetSessionByKey get the session value by key.
 func GetSessionByKey(sesKey, key string, conn db.Connection) interface{} {
-	return newDBDriver(conn).Load(sesKey)[key]
+	return GetSessionDriver(config.Get().SessionDriver.Name, conn).Load(sesKey)[key]
 }
 
 // Session contains info of session.
@@ -83,6 +82,12 @@ func (ses *Session) Add(key string, value interface{}) {
 	ses.Context.SetCookie(&cookie)
 }
 
+// Delete delete the session value of key.
+func (ses *Session) Delete(key string) {
+	delete(ses.Values, key)
+	ses.Driver.Update(ses.Sid, ses.Values)
+}
+
 // Clear clear a Session.
 func (ses *Session) Clear() {
 	ses.Values = map[string]interface{}{}
@@ -118,7 +123,7 @@ func InitSession(ctx *context.Context, conn db.Connection) *Session {
 		Cookie:  DefaultCookieKey,
 	})
 
-	sessions.UseDriver(newDBDriver(conn))
+	sessions.UseDriver(GetSessionDriver(config.Get().SessionDriver.Name, conn))
 	sessions.Values = make(map[string]interface{})
 
 	return sessions.StartCtx(ctx)
@@ -143,14 +148,8 @@ func (driver *DBDriver) Load(sid string) map[string]interface{} {
 	return values
 }
 
-func (driver *DBDriver) deleteOverdueSession() {
-
-	defer func() {
-		if err := recover(); err != nil {
-			logger.Error(err)
-			panic(err)
-		}
-	}()
+// DeleteOverdueSession implements the OverdueSessionCleaner.DeleteOverdueSession.
+func (driver *DBDriver) DeleteOverdueSession() {
 
 	var (
 		duration   = strconv.Itoa(config.Get().SessionLifeTime + 1000)
@@ -175,9 +174,6 @@ func (driver *DBDriver) deleteOverdueSession() {
 
 // Update implements the PersistenceDriver.Update.
 func (driver *DBDriver) Update(sid string, values map[string]interface{}) {
-
-	go driver.deleteOverdueSession()
-
 	if sid != "" {
 		if len(values) == 0 {
 			_ = driver.table().Where("sid", "=", sid).Delete()
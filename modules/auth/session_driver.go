@@ -0,0 +1,258 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"encoding/json"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// SessionDriverGenerator is a function return a PersistenceDriver.
+type SessionDriverGenerator func(conn db.Connection) PersistenceDriver
+
+var sessionDriverList = map[string]SessionDriverGenerator{
+	"database": func(conn db.Connection) PersistenceDriver {
+		return newDBDriver(conn)
+	},
+	"memory": func(conn db.Connection) PersistenceDriver {
+		return defaultMemoryDriver
+	},
+	"file": func(conn db.Connection) PersistenceDriver {
+		return newFileDriver(config.Get().SessionDriver.Config)
+	},
+}
+
+var sessionDriverMu sync.Mutex
+
+// AddSessionDriver makes a session driver generator available by the provided name.
+// If Add is called twice with the same name or if generator is nil,
+// it panics.
+func AddSessionDriver(name string, gen SessionDriverGenerator) {
+	sessionDriverMu.Lock()
+	defer sessionDriverMu.Unlock()
+	if gen == nil {
+		panic("session driver generator is nil")
+	}
+	if _, dup := sessionDriverList[name]; dup {
+		panic("add session driver generator twice " + name)
+	}
+	sessionDriverList[name] = gen
+}
+
+// GetSessionDriver return the PersistenceDriver of given name. The first
+// driver which implements OverdueSessionCleaner starts the janitor.
+func GetSessionDriver(name string, conn db.Connection) PersistenceDriver {
+	if gen, ok := sessionDriverList[name]; ok {
+		driver := gen(conn)
+		if cleaner, ok := driver.(OverdueSessionCleaner); ok {
+			janitorOnce.Do(func() {
+				go runJanitor(cleaner, SessionJanitorInterval)
+			})
+		}
+		return driver
+	}
+	panic("wrong session driver name")
+}
+
+// OverdueSessionCleaner is a PersistenceDriver which needs the overdue
+// sessions to be deleted by the background janitor.
+type OverdueSessionCleaner interface {
+	DeleteOverdueSession()
+}
+
+// SessionJanitorInterval is the interval of the janitor deleting the
+// overdue sessions.
+var SessionJanitorInterval = time.Minute
+
+var janitorOnce sync.Once
+
+func runJanitor(cleaner OverdueSessionCleaner, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		cleanOverdueSession(cleaner)
+	}
+}
+
+func cleanOverdueSession(cleaner OverdueSessionCleaner) {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Error("delete overdue session error: ", err)
+		}
+	}()
+	cleaner.DeleteOverdueSession()
+}
+
+func sessionLifeTime() time.Duration {
+	return time.Second * time.Duration(config.Get().SessionLifeTime)
+}
+
+// MemoryDriver is a driver which keeps the sessions in the process memory.
+// The sessions are lost when the process restarts.
+type MemoryDriver struct {
+	lock     sync.RWMutex
+	sessions map[string]memorySession
+}
+
+type memorySession struct {
+	values    []byte
+	expiredAt time.Time
+}
+
+var defaultMemoryDriver = NewMemoryDriver()
+
+// NewMemoryDriver return an empty MemoryDriver.
+func NewMemoryDriver() *MemoryDriver {
+	return &MemoryDriver{
+		sessions: make(map[string]memorySession),
+	}
+}
+
+// Load implements the PersistenceDriver.Load.
+func (driver *MemoryDriver) Load(sid string) map[string]interface{} {
+	driver.lock.RLock()
+	ses, ok := driver.sessions[sid]
+	driver.lock.RUnlock()
+
+	if !ok || time.Now().After(ses.expiredAt) {
+		return map[string]interface{}{}
+	}
+
+	// Values are stored in json to keep the same types as the database driver.
+	var values map[string]interface{}
+	_ = json.Unmarshal(ses.values, &values)
+	return values
+}
+
+// Update implements the PersistenceDriver.Update.
+func (driver *MemoryDriver) Update(sid string, values map[string]interface{}) {
+	if sid == "" {
+		return
+	}
+
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+
+	if len(values) == 0 {
+		delete(driver.sessions, sid)
+		return
+	}
+
+	valuesByte, _ := json.Marshal(values)
+	driver.sessions[sid] = memorySession{
+		values:    valuesByte,
+		expiredAt: time.Now().Add(sessionLifeTime()),
+	}
+}
+
+// DeleteOverdueSession implements the OverdueSessionCleaner.DeleteOverdueSession.
+func (driver *MemoryDriver) DeleteOverdueSession() {
+	now := time.Now()
+
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+
+	for sid, ses := range driver.sessions {
+		if now.After(ses.expiredAt) {
+			delete(driver.sessions, sid)
+		}
+	}
+}
+
+// FileDriver is a driver which stores every session in a json file of the
+// local directory. The directory is set by the "path" of the driver config,
+// default is "goadmin_session" in the temporary directory.
+type FileDriver struct {
+	path string
+}
+
+var sidReg = regexp.MustCompile(`^[a-zA-Z0-9\-_]+$`)
+
+func newFileDriver(cfg map[string]interface{}) *FileDriver {
+	path, _ := cfg["path"].(string)
+	if path == "" {
+		path = filepath.Join(os.TempDir(), "goadmin_session")
+	}
+	return NewFileDriver(path)
+}
+
+// NewFileDriver return a FileDriver of the given directory.
+func NewFileDriver(path string) *FileDriver {
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		logger.Error("create session directory error: ", err)
+	}
+	return &FileDriver{path: path}
+}
+
+func (driver *FileDriver) file(sid string) (string, bool) {
+	// the sid comes from the cookie, make sure it is not a path.
+	if !sidReg.MatchString(sid) {
+		return "", false
+	}
+	return filepath.Join(driver.path, sid+".json"), true
+}
+
+// Load implements the PersistenceDriver.Load.
+func (driver *FileDriver) Load(sid string) map[string]interface{} {
+	file, ok := driver.file(sid)
+	if !ok {
+		return map[string]interface{}{}
+	}
+
+	info, err := os.Stat(file)
+	if err != nil || time.Since(info.ModTime()) > sessionLifeTime() {
+		return map[string]interface{}{}
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+
+	var values map[string]interface{}
+	_ = json.Unmarshal(content, &values)
+	return values
+}
+
+// Update implements the PersistenceDriver.Update.
+func (driver *FileDriver) Update(sid string, values map[string]interface{}) {
+	file, ok := driver.file(sid)
+	if !ok {
+		return
+	}
+
+	if len(values) == 0 {
+		_ = os.Remove(file)
+		return
+	}
+
+	valuesByte, _ := json.Marshal(values)
+	if err := ioutil.WriteFile(file, valuesByte, 0600); err != nil {
+		logger.Error("write session file error: ", err)
+	}
+}
+
+// DeleteOverdueSession implements the OverdueSessionCleaner.DeleteOverdueSession.
+func (driver *FileDriver) DeleteOverdueSession() {
+	files, err := ioutil.ReadDir(driver.path)
+	if err != nil {
+		return
+	}
+
+	for _, info := range files {
+		if !info.IsDir() && filepath.Ext(info.Name()) == ".json" &&
+			time.Since(info.ModTime()) > sessionLifeTime() {
+			_ = os.Remove(filepath.Join(driver.path, info.Name()))
+		}
+	}
+}
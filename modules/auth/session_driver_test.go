@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func setSessionConfig() {
+	if config.Get().SessionLifeTime == 0 {
+		config.Set(config.Config{})
+	}
+}
+
+func TestMemoryDriver(t *testing.T) {
+	setSessionConfig()
+
+	driver := NewMemoryDriver()
+	driver.Update("abc", map[string]interface{}{"user_id": 1})
+	assert.Equal(t, driver.Load("abc")["user_id"], float64(1))
+
+	driver.DeleteOverdueSession()
+	assert.Equal(t, driver.Load("abc")["user_id"], float64(1))
+
+	driver.Update("abc", map[string]interface{}{})
+	assert.Equal(t, len(driver.Load("abc")), 0)
+}
+
+func TestFileDriver(t *testing.T) {
+	setSessionConfig()
+
+	dir, err := ioutil.TempDir("", "goadmin_session_test")
+	assert.Equal(t, err, nil)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	driver := NewFileDriver(dir)
+	driver.Update("abc-123", map[string]interface{}{"user_id": 1})
+	assert.Equal(t, driver.Load("abc-123")["user_id"], float64(1))
+
+	driver.Update("../abc", map[string]interface{}{"user_id": 1})
+	assert.Equal(t, len(driver.Load("../abc")), 0)
+
+	driver.Update("abc-123", map[string]interface{}{})
+	assert.Equal(t, len(driver.Load("abc-123")), 0)
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TwoFactorPeriod is the seconds of a time step of the one-time passwords.
+const TwoFactorPeriod = 30
+
+const twoFactorDigits = 6
+
+var twoFactorEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTwoFactorSecret return a new random secret of the time-based one-time
+// passwords, which is base32 encoded.
+func NewTwoFactorSecret() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	return twoFactorEncoding.EncodeToString(b)
+}
+
+// TwoFactorCounter return the counter of the time step of given time.
+func TwoFactorCounter(t time.Time) int64 {
+	return t.Unix() / TwoFactorPeriod
+}
+
+// TwoFactorCode return the one-time password of the secret at given counter,
+// see RFC 4226 and RFC 6238.
+func TwoFactorCode(secret string, counter int64) string {
+	key, err := twoFactorEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return ""
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", twoFactorDigits, code%1000000)
+}
+
+// CheckTwoFactorCode check the code with the one-time passwords of the current
+// time step and the steps next to it. The steps not after lastCounter are
+// refused so that a password can not be used twice. It returns the counter
+// of the matched step.
+func CheckTwoFactorCode(secret, code string, lastCounter int64) (int64, bool) {
+	code = strings.Replace(code, " ", "", -1)
+	if len(code) != twoFactorDigits {
+		return 0, false
+	}
+
+	now := TwoFactorCounter(time.Now())
+	for counter := now - 1; counter <= now+1; counter++ {
+		if counter > lastCounter && hmac.Equal([]byte(TwoFactorCode(secret, counter)), []byte(code)) {
+			return counter, true
+		}
+	}
+
+	return 0, false
+}
+
+// TwoFactorURI return the otpauth uri of the secret which is scanned by the
+// authenticator apps.
+func TwoFactorURI(issuer, account, secret string) string {
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + url.Values{
+		"secret": []string{secret},
+		"issuer": []string{issuer},
+	}.Encode()
+}
+
+// VerifyTwoFactor check the one-time password or the recovery code of the
+// user, both of them can only be used once.
+func VerifyTwoFactor(twoFactor models.UserTwoFactorModel, code string) bool {
+	if counter, ok := CheckTwoFactorCode(twoFactor.Secret, code, twoFactor.LastCounter); ok {
+		return twoFactor.UpdateLastCounter(counter)
+	}
+	return twoFactor.UseRecoveryCode(code)
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTwoFactorCode(t *testing.T) {
+	// the secret and the codes of the RFC 6238 test vectors.
+	secret := twoFactorEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	assert.Equal(t, TwoFactorCode(secret, TwoFactorCounter(time.Unix(59, 0))), "287082")
+	assert.Equal(t, TwoFactorCode(secret, TwoFactorCounter(time.Unix(1111111109, 0))), "081804")
+	assert.Equal(t, TwoFactorCode(secret, TwoFactorCounter(time.Unix(2000000000, 0))), "279037")
+}
+
+func TestCheckTwoFactorCode(t *testing.T) {
+	secret := NewTwoFactorSecret()
+	now := TwoFactorCounter(time.Now())
+
+	counter, ok := CheckTwoFactorCode(secret, TwoFactorCode(secret, now), 0)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, counter, now)
+
+	_, ok = CheckTwoFactorCode(secret, TwoFactorCode(secret, now), now)
+	assert.Equal(t, ok, false)
+
+	_, ok = CheckTwoFactorCode(secret, TwoFactorCode(secret, now-5), 0)
+	assert.Equal(t, ok, false)
+}
@@ -0,0 +1,213 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package cache caches the data read from the admin tables, such as the
+// users with their roles, permissions and menus, for a while. Every entry
+// is tagged with the tables it is read from, and the writings of a table
+// invalidate the entries of it. The tables have generations which are
+// increased by the writings, so that the values read before a writing but
+// set after it are dropped.
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/service"
+)
+
+const (
+	ServiceKey = "cache"
+
+	defaultLifeTime = time.Minute
+	maxItems        = 10000
+)
+
+type item struct {
+	value   interface{}
+	tables  []string
+	expires time.Time
+}
+
+// Cache is a memory cache whose entries expire after the CacheLifeTime of
+// the config.
+type Cache struct {
+	items       map[string]item
+	generations map[string]uint64
+	flushes     uint64
+	lock        sync.RWMutex
+}
+
+func (c *Cache) Name() string {
+	return ServiceKey
+}
+
+// defaultCache is shared by the process, so that the functions which only
+// take the database connection, like auth.GetCurUserByID, use the same
+// cache as the one of the services.
+var defaultCache = New()
+
+func init() {
+	service.Register(ServiceKey, func() (service.Service, error) {
+		return defaultCache, nil
+	})
+}
+
+// New return an empty Cache.
+func New() *Cache {
+	return &Cache{items: make(map[string]item), generations: make(map[string]uint64)}
+}
+
+// Default return the cache shared by the process.
+func Default() *Cache {
+	return defaultCache
+}
+
+func GetCache(s interface{}) *Cache {
+	if srv, ok := s.(*Cache); ok {
+		return srv
+	}
+	panic("wrong service")
+}
+
+// Get return the value of the key if it is not expired.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	it, ok := c.items[key]
+	if !ok || time.Now().After(it.expires) {
+		return nil, false
+	}
+	return it.value, true
+}
+
+// Set cache the value of the key, which is read from given tables. Nothing
+// is cached when the cache is turned off.
+func (c *Cache) Set(key string, value interface{}, tables ...string) {
+	lifeTime := lifeTime()
+	if lifeTime <= 0 {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.set(key, value, tables, lifeTime)
+}
+
+// Generation return the generation of given tables, which should be taken
+// before the value is read from the tables and passed to SetWithGeneration.
+func (c *Cache) Generation(tables ...string) uint64 {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.generation(tables)
+}
+
+// SetWithGeneration cache the value of the key like Set, unless any of the
+// tables is written after the generation is taken, as the value may be read
+// before the writing.
+func (c *Cache) SetWithGeneration(key string, value interface{}, generation uint64, tables ...string) {
+	lifeTime := lifeTime()
+	if lifeTime <= 0 {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.generation(tables) == generation {
+		c.set(key, value, tables, lifeTime)
+	}
+}
+
+func (c *Cache) set(key string, value interface{}, tables []string, lifeTime time.Duration) {
+	now := time.Now()
+	if len(c.items) >= maxItems {
+		c.clean(now)
+	}
+	c.items[key] = item{value: value, tables: tables, expires: now.Add(lifeTime)}
+}
+
+// Delete drop the values of the keys.
+func (c *Cache) Delete(keys ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+}
+
+// Invalidate drop the values read from any of given tables, it should be
+// called after the tables are written.
+func (c *Cache) Invalidate(tables ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, table := range tables {
+		c.generations[table]++
+	}
+	for key, it := range c.items {
+		if readFrom(it.tables, tables) {
+			delete(c.items, key)
+		}
+	}
+}
+
+// Flush drop all the values.
+func (c *Cache) Flush() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.items = make(map[string]item)
+	c.flushes++
+}
+
+// generation is the sum of the generations of the tables and the times of
+// the flushes, which only increase, so it changes whenever any of them does.
+func (c *Cache) generation(tables []string) uint64 {
+	generation := c.flushes
+	for _, table := range tables {
+		generation += c.generations[table]
+	}
+	return generation
+}
+
+// clean drop the expired values, and all of them if the cache is still
+// full, which only happens when too many keys are cached in a life time.
+func (c *Cache) clean(now time.Time) {
+	for key, it := range c.items {
+		if now.After(it.expires) {
+			delete(c.items, key)
+		}
+	}
+	if len(c.items) >= maxItems {
+		c.items = make(map[string]item)
+	}
+}
+
+// Invalidate drop the values of the shared cache read from any of given
+// tables.
+func Invalidate(tables ...string) {
+	defaultCache.Invalidate(tables...)
+}
+
+func readFrom(tables, written []string) bool {
+	for _, table := range tables {
+		for _, w := range written {
+			if table == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func lifeTime() time.Duration {
+	switch t := config.Get().CacheLifeTime; {
+	case t < 0:
+		return 0
+	case t == 0:
+		return defaultLifeTime
+	default:
+		return time.Duration(t) * time.Second
+	}
+}
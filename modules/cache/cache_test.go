@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	c := New()
+
+	c.Set("user:1", "jack", "goadmin_users", "goadmin_roles")
+	c.Set("menu", "menus", "goadmin_menu")
+
+	v, ok := c.Get("user:1")
+	assert.True(t, ok)
+	assert.Equal(t, "jack", v)
+
+	c.Invalidate("goadmin_roles", "goadmin_posts")
+	_, ok = c.Get("user:1")
+	assert.False(t, ok, "the values read from a written table are dropped")
+	_, ok = c.Get("menu")
+	assert.True(t, ok)
+
+	c.Delete("menu")
+	_, ok = c.Get("menu")
+	assert.False(t, ok)
+
+	c.items["user:2"] = item{value: "rose", expires: time.Now().Add(-time.Second)}
+	_, ok = c.Get("user:2")
+	assert.False(t, ok, "a value expires")
+
+	c.Set("user:3", "tom")
+	c.Flush()
+	assert.Equal(t, 0, len(c.items))
+}
+
+func TestCacheGeneration(t *testing.T) {
+	c := New()
+
+	generation := c.Generation("goadmin_users", "goadmin_roles")
+	c.SetWithGeneration("user:1", "jack", generation, "goadmin_users", "goadmin_roles")
+	_, ok := c.Get("user:1")
+	assert.True(t, ok)
+
+	// the values read before the tables are written are not cached.
+	generation = c.Generation("goadmin_users", "goadmin_roles")
+	c.Invalidate("goadmin_roles")
+	c.SetWithGeneration("user:1", "jack", generation, "goadmin_users", "goadmin_roles")
+	_, ok = c.Get("user:1")
+	assert.False(t, ok)
+
+	generation = c.Generation("goadmin_menu")
+	c.Invalidate("goadmin_roles")
+	c.SetWithGeneration("menu", "menus", generation, "goadmin_menu")
+	_, ok = c.Get("menu")
+	assert.True(t, ok, "the writings of other tables do not matter")
+
+	generation = c.Generation("goadmin_menu")
+	c.Flush()
+	c.SetWithGeneration("menu", "menus", generation, "goadmin_menu")
+	_, ok = c.Get("menu")
+	assert.False(t, ok)
+}
+
+func TestCacheBounded(t *testing.T) {
+	c := New()
+	for i := 0; i < maxItems; i++ {
+		c.items[strconv.Itoa(i)] = item{expires: time.Now().Add(-time.Second)}
+	}
+	c.Set("menu", "menus")
+	assert.Equal(t, 1, len(c.items), "the expired values are dropped when the cache is full")
+}
@@ -28,17 +28,26 @@ import (
 // If the Dsn is configured, when driver is mysql/postgresql/
 // mssql, the other configurations will be ignored, except for
 // MaxIdleCon and MaxOpenCon.
+//
+// QueryTimeout is the default timeout in seconds of the statements
+// executed by the connection, zero means no timeout.
+//
+// Replicas are the names of the connections of the same driver which
+// serve the reads of the tables on this connection, such as the list and
+// the detail queries. The writes always go to this connection.
 type Database struct {
-	Host       string `json:"host",yaml:"host",ini:"host"`
-	Port       string `json:"port",yaml:"port",ini:"port"`
-	User       string `json:"user",yaml:"user",ini:"user"`
-	Pwd        string `json:"pwd",yaml:"pwd",ini:"pwd"`
-	Name       string `json:"name",yaml:"name",ini:"name"`
-	MaxIdleCon int    `json:"max_idle_con",yaml:"max_idle_con",ini:"max_idle_con"`
-	MaxOpenCon int    `json:"max_open_con",yaml:"max_open_con",ini:"max_open_con"`
-	Driver     string `json:"driver",yaml:"driver",ini:"driver"`
-	File       string `json:"file",yaml:"file",ini:"file"`
-	Dsn        string `json:"dsn",yaml:"dsn",ini:"dsn"`
+	Host         string   `json:"host",yaml:"host",ini:"host"`
+	Port         string   `json:"port",yaml:"port",ini:"port"`
+	User         string   `json:"user",yaml:"user",ini:"user"`
+	Pwd          string   `json:"pwd",yaml:"pwd",ini:"pwd"`
+	Name         string   `json:"name",yaml:"name",ini:"name"`
+	MaxIdleCon   int      `json:"max_idle_con",yaml:"max_idle_con",ini:"max_idle_con"`
+	MaxOpenCon   int      `json:"max_open_con",yaml:"max_open_con",ini:"max_open_con"`
+	Driver       string   `json:"driver",yaml:"driver",ini:"driver"`
+	File         string   `json:"file",yaml:"file",ini:"file"`
+	Dsn          string   `json:"dsn",yaml:"dsn",ini:"dsn"`
+	QueryTimeout int      `json:"query_timeout",yaml:"query_timeout",ini:"query_timeout"`
+	Replicas     []string `json:"replicas",yaml:"replicas",ini:"replicas"`
 }
 
 // DatabaseList is a map of Database.
@@ -131,6 +140,10 @@ type Config struct {
 	// The global url prefix.
 	UrlPrefix string `json:"prefix",yaml:"prefix",ini:"prefix"`
 
+	// The url prefix of the json api, which is appended to the global
+	// url prefix. Default "api".
+	ApiUrlPrefix string `json:"api_prefix",yaml:"api_prefix",ini:"api_prefix"`
+
 	// The theme name of template.
 	Theme string `json:"theme",yaml:"theme",ini:"theme"`
 
@@ -177,6 +190,23 @@ type Config struct {
 	// Session valid time duration,units are seconds. Default 7200.
 	SessionLifeTime int `json:"session_life_time",yaml:"session_life_time",ini:"session_life_time"`
 
+	// Valid time duration of the cached users, roles, permissions and
+	// menus, units are seconds. Default 60, a negative value turns the
+	// cache off.
+	CacheLifeTime int `json:"cache_life_time",yaml:"cache_life_time",ini:"cache_life_time"`
+
+	// Session persistence driver, default "database". The built-in drivers
+	// are "database", "memory" and "file".
+	SessionDriver SessionDriver `json:"session_driver",yaml:"session_driver",ini:"session_driver"`
+
+	// Apply the pending schema migrations of the admin tables when the
+	// database is initialized. Default false, the migrations can be applied
+	// by Engine.Migrate or the command "adm migrate up" instead.
+	AutoMigrate bool `json:"auto_migrate",yaml:"auto_migrate",ini:"auto_migrate"`
+
+	// Failed login limits, see LoginLimit.
+	LoginLimit LoginLimit `json:"login_limit",yaml:"login_limit",ini:"login_limit"`
+
 	// Assets visit link.
 	AssetUrl string `json:"asset_url",yaml:"asset_url",ini:"asset_url"`
 
@@ -220,6 +250,31 @@ type FileUploadEngine struct {
 	Config map[string]interface{}
 }
 
+// LoginLimit limits the failed logins. The failures are counted by the
+// client ip and by the username within Window seconds, and the logins are
+// refused once one of the max failures is reached. A user is locked for
+// LockoutDuration seconds after LockoutFailures failures in a row, until
+// the lockout expires or the user is unlocked in the manager table.
+// A negative value turns the limit off.
+//
+// The client ip is the remote address of the request. The forwarded ip of
+// the X-Forwarded-For or X-Real-Ip header is only used when the remote
+// address is one of the TrustedProxies, which are ips or cidrs.
+type LoginLimit struct {
+	Window              int      `json:"window",yaml:"window",ini:"window"`
+	IPMaxFailures       int      `json:"ip_max_failures",yaml:"ip_max_failures",ini:"ip_max_failures"`
+	UsernameMaxFailures int      `json:"username_max_failures",yaml:"username_max_failures",ini:"username_max_failures"`
+	LockoutFailures     int      `json:"lockout_failures",yaml:"lockout_failures",ini:"lockout_failures"`
+	LockoutDuration     int      `json:"lockout_duration",yaml:"lockout_duration",ini:"lockout_duration"`
+	TrustedProxies      []string `json:"trusted_proxies",yaml:"trusted_proxies",ini:"trusted_proxies"`
+}
+
+// SessionDriver is a session persistence driver.
+type SessionDriver struct {
+	Name   string
+	Config map[string]interface{}
+}
+
 // GetIndexURL get the index url with prefix.
 func (c Config) GetIndexURL() string {
 	index := c.Index()
@@ -241,6 +296,28 @@ func (c Config) Url(suffix string) string {
 	return c.prefix + suffix
 }
 
+// ApiUrl get the json api url with the given suffix.
+func (c Config) ApiUrl(suffix string) string {
+	return c.Url(c.ApiPrefix() + suffix)
+}
+
+// ApiPrefix return the json api prefix without the global prefix.
+func (c Config) ApiPrefix() string {
+	if c.ApiUrlPrefix == "" {
+		return "/api"
+	}
+	if c.ApiUrlPrefix[0] != '/' {
+		return "/" + c.ApiUrlPrefix
+	}
+	return c.ApiUrlPrefix
+}
+
+// URLRemoveApiPrefix turn the given json api url into the url of the
+// page it mirrors.
+func (c Config) URLRemoveApiPrefix(url string) string {
+	return strings.Replace(url, c.ApiUrl(""), c.AssertPrefix(), 1)
+}
+
 // IsTestEnvironment check the environment if it is test.
 func (c Config) IsTestEnvironment() bool {
 	return c.Env == EnvTest
@@ -389,11 +466,33 @@ func Set(cfg Config) Config {
 	cfg.AuthUserTable = setDefault(cfg.AuthUserTable, "", "goadmin_users")
 	cfg.ColorScheme = setDefault(cfg.ColorScheme, "", "skin-black")
 	cfg.FileUploadEngine.Name = setDefault(cfg.FileUploadEngine.Name, "", "local")
+	cfg.SessionDriver.Name = setDefault(cfg.SessionDriver.Name, "", "database")
 	cfg.Env = setDefault(cfg.Env, "", EnvProd)
+	cfg.ApiUrlPrefix = setDefault(cfg.ApiUrlPrefix, "", "api")
 	if cfg.SessionLifeTime == 0 {
 		// default two hours
 		cfg.SessionLifeTime = 7200
 	}
+	if cfg.CacheLifeTime == 0 {
+		// default one minute
+		cfg.CacheLifeTime = 60
+	}
+	if cfg.LoginLimit.Window == 0 {
+		// default fifteen minutes
+		cfg.LoginLimit.Window = 900
+	}
+	if cfg.LoginLimit.IPMaxFailures == 0 {
+		cfg.LoginLimit.IPMaxFailures = 20
+	}
+	if cfg.LoginLimit.UsernameMaxFailures == 0 {
+		cfg.LoginLimit.UsernameMaxFailures = 10
+	}
+	if cfg.LoginLimit.LockoutFailures == 0 {
+		cfg.LoginLimit.LockoutFailures = 5
+	}
+	if cfg.LoginLimit.LockoutDuration == 0 {
+		cfg.LoginLimit.LockoutDuration = 900
+	}
 
 	if cfg.UrlPrefix == "" {
 		cfg.prefix = "/"
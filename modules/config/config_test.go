@@ -119,3 +119,22 @@ func TestSet(t *testing.T) {
 	Set(Config{Theme: "bcd"})
 	assert.Equal(t, Get().Theme, "bcd")
 }
+
+func TestConfig_URLRemoveApiPrefix(t *testing.T) {
+	count = 0
+	Set(Config{
+		UrlPrefix: "admin",
+	})
+
+	assert.Equal(t, Get().ApiUrl("/info/manager"), "/admin/api/info/manager")
+	assert.Equal(t, Get().URLRemoveApiPrefix("/admin/api/info/manager?id=1"), "/admin/info/manager?id=1")
+
+	count = 0
+	Set(Config{
+		UrlPrefix:    "/",
+		ApiUrlPrefix: "/json",
+	})
+
+	assert.Equal(t, Get().ApiUrl("/info/manager"), "/json/info/manager")
+	assert.Equal(t, Get().URLRemoveApiPrefix("/json/info/manager"), "/info/manager")
+}
@@ -11,4 +11,14 @@ const (
 	Title = "GoAdmin"
 
 	ContextNodeNeedAuth = "need_auth"
+
+	// CSRFCookieKey is the cookie key of the csrf token of the session.
+	CSRFCookieKey = "go_admin_csrf"
+
+	// CSRFLoginCookieKey is the cookie key of the csrf token of the pages
+	// shown before login.
+	CSRFLoginCookieKey = "go_admin_csrf_login"
+
+	// CSRFHeader is the http header key of the csrf token of the ajax requests.
+	CSRFHeader = "X-CSRF-Token"
 )
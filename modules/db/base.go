@@ -1,14 +1,21 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
 )
 
 // Base is a common Connection.
 type Base struct {
-	DbList map[string]*sql.DB
-	Once   sync.Once
+	DbList  map[string]*sql.DB
+	Once    sync.Once
+	Configs config.DatabaseList
+
+	replicas replicaRouter
 }
 
 // Close implements the method Connection.Close.
@@ -24,3 +31,19 @@ func (db *Base) Close() []error {
 func (db *Base) GetDB(key string) *sql.DB {
 	return db.DbList[key]
 }
+
+// withTimeout return a context of the default query timeout of the
+// connection. The deadline of the given context is kept if it is earlier.
+func (db *Base) withTimeout(ctx context.Context, conn string) (context.Context, context.CancelFunc) {
+	if cfg, ok := db.Configs[conn]; ok && cfg.QueryTimeout > 0 {
+		return context.WithTimeout(ctx, time.Duration(cfg.QueryTimeout)*time.Second)
+	}
+	return context.WithCancel(ctx)
+}
+
+// withTxTimeout return a context of the default query timeout for the
+// statements within a transaction. A transaction does not know its
+// connection, the timeout of the default connection is applied.
+func (db *Base) withTxTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return db.withTimeout(ctx, "default")
+}
@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/GoAdminGroup/go-admin/modules/config"
@@ -52,6 +53,39 @@ type Connection interface {
 	BeginTxAndConnection(conn string) *sql.Tx
 	BeginTxWithLevelAndConnection(conn string, level sql.IsolationLevel) *sql.Tx
 
+	// QueryWithContext is the query method of sql with the context.
+	QueryWithContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error)
+
+	// ExecWithContext is the exec method of sql with the context.
+	ExecWithContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+
+	// QueryWithConnectionAndContext is the query method with given connection and context of sql,
+	// the default query timeout of the connection is applied.
+	QueryWithConnectionAndContext(ctx context.Context, conn, query string, args ...interface{}) ([]map[string]interface{}, error)
+
+	// ExecWithConnectionAndContext is the exec method with given connection and context of sql,
+	// the default query timeout of the connection is applied.
+	ExecWithConnectionAndContext(ctx context.Context, conn, query string, args ...interface{}) (sql.Result, error)
+
+	// QueryWithTxAndContext is the query method within the transaction with given context,
+	// the default query timeout of the default connection is applied.
+	QueryWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error)
+
+	// ExecWithTxAndContext is the exec method within the transaction with given context,
+	// the default query timeout of the default connection is applied.
+	ExecWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error)
+
+	BeginTxWithContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error)
+
+	// ReadConnection return the connection which the reads of the given
+	// connection are routed to. The healthy replicas are picked in turn,
+	// the Replicas of the connection config are used if none is given, and
+	// the given connection is returned if no replica is available.
+	ReadConnection(conn string, replicas ...string) string
+
+	// CheckConnection ping the given connection and record its health.
+	CheckConnection(conn string) bool
+
 	// InitDB initialize the database connections.
 	InitDB(cfg map[string]config.Database) Connection
 
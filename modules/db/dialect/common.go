@@ -4,10 +4,11 @@ import "fmt"
 
 type commonDialect struct {
 	delimiter string
+	schema    schemaSyntax
 }
 
 func (c commonDialect) Insert(comp *SQLComponent) string {
-	comp.prepareInsert(c.delimiter)
+	comp.prepareInsert(c.delimiter, "")
 	return comp.Statement
 }
 
@@ -47,3 +48,8 @@ func (c commonDialect) ShowTables() string {
 func (c commonDialect) GetDelimiter() string {
 	return c.delimiter
 }
+
+func (c commonDialect) EstimateCount(table string) (string, []interface{}) {
+	return "select table_rows as estimate from information_schema.tables where table_schema = database() and table_name = ?",
+		[]interface{}{table}
+}
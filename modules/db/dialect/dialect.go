@@ -22,6 +22,10 @@ type Dialect interface {
 	// ShowTables show tables of database
 	ShowTables() string
 
+	// EstimateCount return the statement querying the estimated row count
+	// of the table as the column estimate, and the args of it.
+	EstimateCount(table string) (string, []interface{})
+
 	// Insert
 	Insert(comp *SQLComponent) string
 
@@ -36,6 +40,24 @@ type Dialect interface {
 
 	// GetDelimiter return the delimiter of Dialect.
 	GetDelimiter() string
+
+	// CreateTable return the statements creating the table and its indexes.
+	CreateTable(table Table) []string
+
+	// DropTable
+	DropTable(table string) string
+
+	// AddColumn
+	AddColumn(table string, column Column) string
+
+	// DropColumn
+	DropColumn(table, column string) string
+
+	// CreateIndex
+	CreateIndex(table string, index Index) string
+
+	// DropIndex
+	DropIndex(table, index string) string
 }
 
 // GetDialect return the default Dialect.
@@ -48,22 +70,22 @@ func GetDialectByDriver(driver string) Dialect {
 	switch driver {
 	case "mysql":
 		return mysql{
-			commonDialect: commonDialect{delimiter: "`"},
+			commonDialect: commonDialect{delimiter: "`", schema: mysqlSchemaSyntax},
 		}
 	case "mssql":
 		return mssql{
-			commonDialect: commonDialect{delimiter: "["},
+			commonDialect: commonDialect{delimiter: "[", schema: mssqlSchemaSyntax},
 		}
 	case "postgresql":
 		return postgresql{
-			commonDialect: commonDialect{delimiter: `"`},
+			commonDialect: commonDialect{delimiter: `"`, schema: postgresqlSchemaSyntax},
 		}
 	case "sqlite":
 		return sqlite{
-			commonDialect: commonDialect{delimiter: "`"},
+			commonDialect: commonDialect{delimiter: "`", schema: sqliteSchemaSyntax},
 		}
 	default:
-		return commonDialect{delimiter: "`"}
+		return commonDialect{delimiter: "`", schema: mysqlSchemaSyntax}
 	}
 }
 
@@ -86,6 +108,7 @@ type SQLComponent struct {
 	Group      string
 	Statement  string
 	Values     H
+	Returning  []string
 }
 
 // Where contains the operation and field.
@@ -188,6 +211,15 @@ func wrap(delimiter, field string) string {
 	return delimiter + field + delimiter
 }
 
+// quote wrap the identifier with the delimiter, the delimiters in it are
+// escaped by doubling.
+func quote(delimiter, name string) string {
+	if delimiter == "[" {
+		return "[" + strings.Replace(name, "]", "]]", -1) + "]"
+	}
+	return delimiter + strings.Replace(name, delimiter, delimiter+delimiter, -1) + delimiter
+}
+
 func (sql *SQLComponent) getWheres(delimiter string) string {
 	if len(sql.Wheres) == 0 {
 		if sql.WhereRaws != "" {
@@ -256,7 +288,17 @@ func (sql *SQLComponent) prepareUpdate(delimiter string) {
 	sql.Statement = "update " + sql.TableName + " set " + fields + sql.getWheres(delimiter)
 }
 
-func (sql *SQLComponent) prepareInsert(delimiter string) {
+func (sql *SQLComponent) getReturning(delimiter string) string {
+	fields := make([]string, len(sql.Returning))
+	for i, field := range sql.Returning {
+		fields[i] = wrap(delimiter, field)
+	}
+	return strings.Join(fields, ", ")
+}
+
+// prepareInsert make the insert statement of the values, the output is
+// put between the columns and the values.
+func (sql *SQLComponent) prepareInsert(delimiter, output string) {
 	fields := " ("
 	quesMark := "("
 
@@ -268,5 +310,5 @@ func (sql *SQLComponent) prepareInsert(delimiter string) {
 	fields = fields[:len(fields)-1] + ")"
 	quesMark = quesMark[:len(quesMark)-1] + ")"
 
-	sql.Statement = "insert into " + sql.TableName + fields + " values " + quesMark
+	sql.Statement = "insert into " + sql.TableName + fields + output + " values " + quesMark
 }
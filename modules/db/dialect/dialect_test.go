@@ -0,0 +1,52 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertReturning(t *testing.T) {
+	comp := &SQLComponent{TableName: "goadmin_users", Values: H{"name": "jane"}, Returning: []string{"id"}}
+	assert.Equal(t, GetDialectByDriver("postgresql").Insert(comp),
+		`insert into goadmin_users ("name") values (?) returning "id"`)
+
+	comp = &SQLComponent{TableName: "goadmin_users", Values: H{"name": "jane"}, Returning: []string{"*"}}
+	assert.Equal(t, GetDialectByDriver("postgresql").Insert(comp),
+		`insert into goadmin_users ("name") values (?) returning *`)
+
+	// the output clause of mssql without into fails on the tables with
+	// triggers, so the keys are output into a table variable.
+	comp = &SQLComponent{TableName: "goadmin_users", Values: H{"name": "jane"}, Returning: []string{"id", "code"}}
+	assert.Equal(t, GetDialectByDriver("mssql").Insert(comp),
+		`declare @inserted table ([id] sql_variant, [code] sql_variant); `+
+			`insert into goadmin_users ([name]) output inserted.[id], inserted.[code] into @inserted values (?); `+
+			`select * from @inserted`)
+
+	comp = &SQLComponent{TableName: "goadmin_users", Values: H{"name": "jane"}}
+	assert.Equal(t, GetDialectByDriver("mssql").Insert(comp),
+		`insert into goadmin_users ([name]) values (?)`)
+
+	comp = &SQLComponent{TableName: "goadmin_users", Values: H{"name": "jane"}, Returning: []string{"id"}}
+	assert.Equal(t, GetDialectByDriver("mysql").Insert(comp),
+		"insert into goadmin_users (`name`) values (?)")
+}
+
+func TestEstimateCount(t *testing.T) {
+	statement, args := GetDialectByDriver("postgresql").EstimateCount("users")
+	assert.Equal(t, "select reltuples::bigint as estimate from pg_class where relname = ?", statement)
+	assert.Equal(t, []interface{}{"users"}, args)
+
+	statement, args = GetDialectByDriver("mysql").EstimateCount("users'")
+	assert.Contains(t, statement, "table_name = ?")
+	assert.Equal(t, []interface{}{"users'"}, args)
+
+	statement, args = GetDialectByDriver("mssql").EstimateCount("users")
+	assert.Contains(t, statement, "object_id(?)")
+	assert.Equal(t, []interface{}{"users"}, args)
+
+	// the table of sqlite can not be bound, so it is quoted.
+	statement, args = GetDialectByDriver("sqlite").EstimateCount("users` where 1")
+	assert.Equal(t, "select max(rowid) as estimate from `users`` where 1`", statement)
+	assert.Nil(t, args)
+}
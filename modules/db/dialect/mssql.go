@@ -4,7 +4,10 @@
 
 package dialect
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type mssql struct {
 	commonDialect
@@ -21,3 +24,30 @@ func (mssql) ShowColumns(table string) string {
 func (mssql) ShowTables() string {
 	return "select * from information_schema.TABLES"
 }
+
+func (mssql) EstimateCount(table string) (string, []interface{}) {
+	return "select sum(rows) as estimate from sys.partitions where object_id = object_id(?) and index_id < 2",
+		[]interface{}{table}
+}
+
+// Insert output the columns of Returning of the inserted row into a table
+// variable, which is selected after the insertion, as the output clause
+// without into fails on the tables with triggers.
+func (m mssql) Insert(comp *SQLComponent) string {
+	if len(comp.Returning) == 0 {
+		comp.prepareInsert(m.delimiter, "")
+		return comp.Statement
+	}
+
+	columns := make([]string, len(comp.Returning))
+	inserted := make([]string, len(comp.Returning))
+	for i, field := range comp.Returning {
+		columns[i] = wrap(m.delimiter, field) + " sql_variant"
+		inserted[i] = "inserted." + wrap(m.delimiter, field)
+	}
+
+	comp.prepareInsert(m.delimiter, " output "+strings.Join(inserted, ", ")+" into @inserted")
+	comp.Statement = "declare @inserted table (" + strings.Join(columns, ", ") + "); " +
+		comp.Statement + "; select * from @inserted"
+	return comp.Statement
+}
@@ -15,3 +15,16 @@ func (postgresql) GetName() string {
 func (postgresql) ShowTables() string {
 	return "SELECT tablename FROM pg_catalog.pg_tables WHERE schemaname != 'pg_catalog' AND schemaname != 'information_schema';"
 }
+
+// Insert return the columns of Returning of the inserted row.
+func (p postgresql) Insert(comp *SQLComponent) string {
+	comp.prepareInsert(p.delimiter, "")
+	if len(comp.Returning) > 0 {
+		comp.Statement += " returning " + comp.getReturning(p.delimiter)
+	}
+	return comp.Statement
+}
+
+func (postgresql) EstimateCount(table string) (string, []interface{}) {
+	return "select reltuples::bigint as estimate from pg_class where relname = ?", []interface{}{table}
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package dialect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColumnType is the type of a column which is mapped to the column type
+// of each dialect.
+type ColumnType uint8
+
+const (
+	// Increments is an auto increment integer primary key.
+	Increments ColumnType = iota
+	TinyInt
+	Int
+	BigInt
+	Varchar
+	Text
+	Timestamp
+)
+
+// CurrentTimestamp is the default value of a Timestamp column which is
+// the time when the row is inserted.
+const CurrentTimestamp = "CURRENT_TIMESTAMP"
+
+// Column is a column definition of a table. Size is the length of a
+// Varchar column. Default is a sql literal, such as 0 or an empty string
+// quoted by the single quotes.
+type Column struct {
+	Name     string
+	Type     ColumnType
+	Size     int
+	Nullable bool
+	Default  string
+	Unique   bool
+}
+
+// Index is an index definition of a table.
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// Table is a table definition.
+type Table struct {
+	Name    string
+	Columns []Column
+	Indexes []Index
+}
+
+// Timestamps return the created_at and the updated_at columns.
+func Timestamps() []Column {
+	return []Column{
+		{Name: "created_at", Type: Timestamp, Nullable: true, Default: CurrentTimestamp},
+		{Name: "updated_at", Type: Timestamp, Nullable: true, Default: CurrentTimestamp},
+	}
+}
+
+// schemaSyntax is the data definition syntax of a dialect.
+type schemaSyntax struct {
+	types            map[ColumnType]string
+	increments       string
+	currentTimestamp string
+	tableOptions     string
+	dropIndex        string
+}
+
+var (
+	mysqlSchemaSyntax = schemaSyntax{
+		types: map[ColumnType]string{
+			TinyInt:   "tinyint(4)",
+			Int:       "int(11)",
+			BigInt:    "bigint(20)",
+			Varchar:   "varchar(%d)",
+			Text:      "text",
+			Timestamp: "timestamp",
+		},
+		increments:       "int(10) unsigned NOT NULL AUTO_INCREMENT PRIMARY KEY",
+		currentTimestamp: "CURRENT_TIMESTAMP",
+		tableOptions:     " ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci",
+		dropIndex:        "drop index %[2]s on %[1]s",
+	}
+	postgresqlSchemaSyntax = schemaSyntax{
+		types: map[ColumnType]string{
+			TinyInt:   "smallint",
+			Int:       "integer",
+			BigInt:    "bigint",
+			Varchar:   "character varying(%d)",
+			Text:      "text",
+			Timestamp: "timestamp without time zone",
+		},
+		increments:       "serial PRIMARY KEY",
+		currentTimestamp: "now()",
+		dropIndex:        "drop index %[2]s",
+	}
+	sqliteSchemaSyntax = schemaSyntax{
+		types: map[ColumnType]string{
+			TinyInt:   "INT",
+			Int:       "INT",
+			BigInt:    "INTEGER",
+			Varchar:   "CHAR(%d)",
+			Text:      "TEXT",
+			Timestamp: "TIMESTAMP",
+		},
+		increments:       "integer PRIMARY KEY autoincrement",
+		currentTimestamp: "CURRENT_TIMESTAMP",
+		dropIndex:        "drop index %[2]s",
+	}
+	mssqlSchemaSyntax = schemaSyntax{
+		types: map[ColumnType]string{
+			TinyInt:   "tinyint",
+			Int:       "int",
+			BigInt:    "bigint",
+			Varchar:   "varchar(%d)",
+			Text:      "text",
+			Timestamp: "datetime",
+		},
+		increments:       "int identity(1,1) PRIMARY KEY",
+		currentTimestamp: "GETDATE()",
+		dropIndex:        "drop index %[1]s.%[2]s",
+	}
+)
+
+func (c commonDialect) CreateTable(table Table) []string {
+	columns := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		columns[i] = c.column(column)
+	}
+	stmts := []string{"create table " + wrap(c.delimiter, table.Name) + " (\n  " +
+		strings.Join(columns, ",\n  ") + "\n)" + c.schema.tableOptions}
+	for _, index := range table.Indexes {
+		stmts = append(stmts, c.CreateIndex(table.Name, index))
+	}
+	return stmts
+}
+
+func (c commonDialect) DropTable(table string) string {
+	return "drop table " + wrap(c.delimiter, table)
+}
+
+func (c commonDialect) AddColumn(table string, column Column) string {
+	return "alter table " + wrap(c.delimiter, table) + " add " + c.column(column)
+}
+
+// DropColumn is not supported by the sqlite before 3.35.0.
+func (c commonDialect) DropColumn(table, column string) string {
+	return "alter table " + wrap(c.delimiter, table) + " drop column " + wrap(c.delimiter, column)
+}
+
+func (c commonDialect) CreateIndex(table string, index Index) string {
+	columns := make([]string, len(index.Columns))
+	for i, column := range index.Columns {
+		columns[i] = wrap(c.delimiter, column)
+	}
+	unique := ""
+	if index.Unique {
+		unique = "unique "
+	}
+	return "create " + unique + "index " + wrap(c.delimiter, index.Name) + " on " +
+		wrap(c.delimiter, table) + " (" + strings.Join(columns, ", ") + ")"
+}
+
+func (c commonDialect) DropIndex(table, index string) string {
+	return fmt.Sprintf(c.schema.dropIndex, wrap(c.delimiter, table), wrap(c.delimiter, index))
+}
+
+func (c commonDialect) column(column Column) string {
+	if column.Type == Increments {
+		return wrap(c.delimiter, column.Name) + " " + c.schema.increments
+	}
+
+	typ := c.schema.types[column.Type]
+	if strings.Contains(typ, "%d") {
+		typ = fmt.Sprintf(typ, column.Size)
+	}
+
+	def := wrap(c.delimiter, column.Name) + " " + typ
+	if column.Nullable {
+		def += " NULL"
+	} else {
+		def += " NOT NULL"
+	}
+	if column.Default == CurrentTimestamp {
+		def += " DEFAULT " + c.schema.currentTimestamp
+	} else if column.Default != "" {
+		def += " DEFAULT " + column.Default
+	}
+	if column.Unique {
+		def += " UNIQUE"
+	}
+	return def
+}
@@ -19,3 +19,9 @@ func (sqlite) ShowColumns(table string) string {
 func (sqlite) ShowTables() string {
 	return "SELECT name as tablename FROM sqlite_master WHERE type ='table'"
 }
+
+// EstimateCount takes the max rowid as the estimate, which is cheap but
+// counts the deleted rows. The table can not be bound, so it is quoted.
+func (s sqlite) EstimateCount(table string) (string, []interface{}) {
+	return "select max(rowid) as estimate from " + quote(s.delimiter, table), nil
+}
@@ -0,0 +1,332 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package migration
+
+import "github.com/GoAdminGroup/go-admin/modules/db/dialect"
+
+// The built-in migrations of the admin tables.
+func init() {
+	Register(
+		Migration{
+			Version: 1,
+			Name:    "create_admin_tables",
+			Up:      createAdminTables,
+			Down: func(s *Schema) error {
+				return s.DropTable(adminTables...)
+			},
+		},
+		Migration{
+			Version: 2,
+			Name:    "create_user_tokens_table",
+			Up: func(s *Schema) error {
+				return s.CreateTable(dialect.Table{
+					Name: "goadmin_user_tokens",
+					Columns: columns(
+						dialect.Column{Name: "id", Type: dialect.Increments},
+						dialect.Column{Name: "user_id", Type: dialect.Int},
+						dialect.Column{Name: "name", Type: dialect.Varchar, Size: 100, Default: "''"},
+						dialect.Column{Name: "token", Type: dialect.Varchar, Size: 64, Unique: true},
+						dialect.Column{Name: "last_used_at", Type: dialect.Timestamp, Nullable: true},
+					),
+					Indexes: []dialect.Index{
+						{Name: "admin_user_tokens_user_id_index", Columns: []string{"user_id"}},
+					},
+				})
+			},
+			Down: func(s *Schema) error {
+				return s.DropTable("goadmin_user_tokens")
+			},
+		},
+		Migration{
+			Version: 3,
+			Name:    "add_audit_columns_to_operation_log",
+			Up: func(s *Schema) error {
+				exist, err := s.HasColumn("goadmin_operation_log", "prefix")
+				if err != nil || exist {
+					return err
+				}
+				err = s.AddColumn("goadmin_operation_log",
+					dialect.Column{Name: "prefix", Type: dialect.Varchar, Size: 100, Default: "''"},
+					dialect.Column{Name: "pk", Type: dialect.Varchar, Size: 255, Default: "''"},
+					dialect.Column{Name: "op", Type: dialect.Varchar, Size: 20, Default: "''"},
+					dialect.Column{Name: "diff", Type: dialect.Text, Nullable: true},
+				)
+				if err != nil {
+					return err
+				}
+				return s.CreateIndex("goadmin_operation_log", dialect.Index{
+					Name:    "admin_operation_log_prefix_pk_index",
+					Columns: []string{"prefix", "pk"},
+				})
+			},
+			Down: func(s *Schema) error {
+				// only the mysql dump has the index, the error of the
+				// databases created by the other dumps is ignored.
+				_ = s.DropIndex("goadmin_operation_log", "admin_operation_log_prefix_pk_index")
+				return s.DropColumn("goadmin_operation_log", "prefix", "pk", "op", "diff")
+			},
+		},
+		Migration{
+			Version: 4,
+			Name:    "add_two_factor_authentication",
+			Up: func(s *Schema) error {
+				err := s.CreateTable(dialect.Table{
+					Name: "goadmin_user_two_factors",
+					Columns: columns(
+						dialect.Column{Name: "id", Type: dialect.Increments},
+						dialect.Column{Name: "user_id", Type: dialect.Int, Unique: true},
+						dialect.Column{Name: "secret", Type: dialect.Varchar, Size: 100},
+						dialect.Column{Name: "recovery_codes", Type: dialect.Varchar, Size: 1000, Default: "''"},
+						dialect.Column{Name: "last_counter", Type: dialect.BigInt, Default: "0"},
+					),
+				})
+				if err != nil {
+					return err
+				}
+				return s.AddColumn("goadmin_roles",
+					dialect.Column{Name: "two_factor_required", Type: dialect.TinyInt, Default: "0"})
+			},
+			Down: func(s *Schema) error {
+				if err := s.DropColumn("goadmin_roles", "two_factor_required"); err != nil {
+					return err
+				}
+				return s.DropTable("goadmin_user_two_factors")
+			},
+		},
+		Migration{
+			Version: 5,
+			Name:    "create_user_lockouts_table",
+			Up: func(s *Schema) error {
+				return s.CreateTable(dialect.Table{
+					Name: "goadmin_user_lockouts",
+					Columns: columns(
+						dialect.Column{Name: "id", Type: dialect.Increments},
+						dialect.Column{Name: "user_id", Type: dialect.Int, Unique: true},
+						dialect.Column{Name: "failures", Type: dialect.Int, Default: "0"},
+						dialect.Column{Name: "locked_until", Type: dialect.BigInt, Default: "0"},
+					),
+				})
+			},
+			Down: func(s *Schema) error {
+				return s.DropTable("goadmin_user_lockouts")
+			},
+		},
+		Migration{
+			Version: 6,
+			Name:    "create_saved_views_table",
+			Up: func(s *Schema) error {
+				return s.CreateTable(dialect.Table{
+					Name: "goadmin_saved_views",
+					Columns: columns(
+						dialect.Column{Name: "id", Type: dialect.Increments},
+						dialect.Column{Name: "user_id", Type: dialect.Int},
+						dialect.Column{Name: "role_id", Type: dialect.Int, Default: "0"},
+						dialect.Column{Name: "prefix", Type: dialect.Varchar, Size: 100},
+						dialect.Column{Name: "name", Type: dialect.Varchar, Size: 100},
+						dialect.Column{Name: "params", Type: dialect.Varchar, Size: 3000, Default: "''"},
+					),
+					Indexes: []dialect.Index{
+						{Name: "admin_saved_views_prefix_index", Columns: []string{"prefix"}},
+					},
+				})
+			},
+			Down: func(s *Schema) error {
+				return s.DropTable("goadmin_saved_views")
+			},
+		},
+	)
+}
+
+var adminTables = []string{
+	"goadmin_menu",
+	"goadmin_operation_log",
+	"goadmin_permissions",
+	"goadmin_role_menu",
+	"goadmin_role_permissions",
+	"goadmin_role_users",
+	"goadmin_roles",
+	"goadmin_session",
+	"goadmin_user_permissions",
+	"goadmin_users",
+}
+
+// columns return the columns followed by the timestamps.
+func columns(cols ...dialect.Column) []dialect.Column {
+	return append(cols, dialect.Timestamps()...)
+}
+
+func createAdminTables(s *Schema) error {
+	tables := []dialect.Table{
+		{
+			Name: "goadmin_menu",
+			Columns: columns(
+				dialect.Column{Name: "id", Type: dialect.Increments},
+				dialect.Column{Name: "parent_id", Type: dialect.Int, Default: "0"},
+				dialect.Column{Name: "type", Type: dialect.TinyInt, Default: "0"},
+				dialect.Column{Name: "order", Type: dialect.Int, Default: "0"},
+				dialect.Column{Name: "title", Type: dialect.Varchar, Size: 50},
+				dialect.Column{Name: "icon", Type: dialect.Varchar, Size: 50},
+				dialect.Column{Name: "uri", Type: dialect.Varchar, Size: 3000, Default: "''"},
+				dialect.Column{Name: "header", Type: dialect.Varchar, Size: 150, Nullable: true},
+			),
+		},
+		{
+			Name: "goadmin_operation_log",
+			Columns: columns(
+				dialect.Column{Name: "id", Type: dialect.Increments},
+				dialect.Column{Name: "user_id", Type: dialect.Int},
+				dialect.Column{Name: "path", Type: dialect.Varchar, Size: 255},
+				dialect.Column{Name: "method", Type: dialect.Varchar, Size: 10},
+				dialect.Column{Name: "ip", Type: dialect.Varchar, Size: 15},
+				dialect.Column{Name: "input", Type: dialect.Text},
+			),
+			Indexes: []dialect.Index{
+				{Name: "admin_operation_log_user_id_index", Columns: []string{"user_id"}},
+			},
+		},
+		{
+			Name: "goadmin_permissions",
+			Columns: columns(
+				dialect.Column{Name: "id", Type: dialect.Increments},
+				dialect.Column{Name: "name", Type: dialect.Varchar, Size: 50, Unique: true},
+				dialect.Column{Name: "slug", Type: dialect.Varchar, Size: 50},
+				dialect.Column{Name: "http_method", Type: dialect.Varchar, Size: 255, Nullable: true},
+				dialect.Column{Name: "http_path", Type: dialect.Text},
+			),
+		},
+		{
+			Name: "goadmin_role_menu",
+			Columns: columns(
+				dialect.Column{Name: "role_id", Type: dialect.Int},
+				dialect.Column{Name: "menu_id", Type: dialect.Int},
+			),
+			Indexes: []dialect.Index{
+				{Name: "admin_role_menu_role_id_menu_id_index", Columns: []string{"role_id", "menu_id"}},
+			},
+		},
+		{
+			Name: "goadmin_role_permissions",
+			Columns: columns(
+				dialect.Column{Name: "role_id", Type: dialect.Int},
+				dialect.Column{Name: "permission_id", Type: dialect.Int},
+			),
+			Indexes: []dialect.Index{
+				{Name: "admin_role_permissions", Columns: []string{"role_id", "permission_id"}, Unique: true},
+			},
+		},
+		{
+			Name: "goadmin_role_users",
+			Columns: columns(
+				dialect.Column{Name: "role_id", Type: dialect.Int},
+				dialect.Column{Name: "user_id", Type: dialect.Int},
+			),
+			Indexes: []dialect.Index{
+				{Name: "admin_user_roles", Columns: []string{"role_id", "user_id"}, Unique: true},
+			},
+		},
+		{
+			Name: "goadmin_roles",
+			Columns: columns(
+				dialect.Column{Name: "id", Type: dialect.Increments},
+				dialect.Column{Name: "name", Type: dialect.Varchar, Size: 50, Unique: true},
+				dialect.Column{Name: "slug", Type: dialect.Varchar, Size: 50},
+			),
+		},
+		{
+			Name: "goadmin_session",
+			Columns: columns(
+				dialect.Column{Name: "id", Type: dialect.Increments},
+				dialect.Column{Name: "sid", Type: dialect.Varchar, Size: 50, Default: "''"},
+				dialect.Column{Name: "values", Type: dialect.Varchar, Size: 3000, Default: "''"},
+			),
+		},
+		{
+			Name: "goadmin_user_permissions",
+			Columns: columns(
+				dialect.Column{Name: "user_id", Type: dialect.Int},
+				dialect.Column{Name: "permission_id", Type: dialect.Int},
+			),
+			Indexes: []dialect.Index{
+				{Name: "admin_user_permissions", Columns: []string{"user_id", "permission_id"}, Unique: true},
+			},
+		},
+		{
+			Name: "goadmin_users",
+			Columns: columns(
+				dialect.Column{Name: "id", Type: dialect.Increments},
+				dialect.Column{Name: "username", Type: dialect.Varchar, Size: 100, Unique: true},
+				dialect.Column{Name: "password", Type: dialect.Varchar, Size: 100, Default: "''"},
+				dialect.Column{Name: "name", Type: dialect.Varchar, Size: 100},
+				dialect.Column{Name: "avatar", Type: dialect.Varchar, Size: 255, Nullable: true},
+				dialect.Column{Name: "remember_token", Type: dialect.Varchar, Size: 100, Nullable: true},
+			),
+		},
+	}
+
+	for _, table := range tables {
+		if err := s.CreateTable(table); err != nil {
+			return err
+		}
+	}
+
+	return seedAdminTables(s)
+}
+
+// seedAdminTables insert the default menus, permissions, roles and users,
+// the password of the user admin is "admin" and the one of the user
+// operator is "admin" too.
+func seedAdminTables(s *Schema) error {
+	seeds := []struct {
+		table string
+		rows  []dialect.H
+	}{
+		{"goadmin_menu", []dialect.H{
+			{"parent_id": 0, "type": 1, "order": 2, "title": "Admin", "icon": "fa-tasks", "uri": ""},
+			{"parent_id": 1, "type": 1, "order": 2, "title": "Users", "icon": "fa-users", "uri": "/info/manager"},
+			{"parent_id": 1, "type": 1, "order": 3, "title": "Roles", "icon": "fa-user", "uri": "/info/roles"},
+			{"parent_id": 1, "type": 1, "order": 4, "title": "Permission", "icon": "fa-ban", "uri": "/info/permission"},
+			{"parent_id": 1, "type": 1, "order": 5, "title": "Menu", "icon": "fa-bars", "uri": "/menu"},
+			{"parent_id": 1, "type": 1, "order": 6, "title": "Operation log", "icon": "fa-history", "uri": "/info/op"},
+			{"parent_id": 0, "type": 1, "order": 1, "title": "Dashboard", "icon": "fa-bar-chart", "uri": "/"},
+		}},
+		{"goadmin_permissions", []dialect.H{
+			{"name": "All permission", "slug": "*", "http_method": "", "http_path": "*"},
+			{"name": "Dashboard", "slug": "dashboard", "http_method": "GET,PUT,POST,DELETE", "http_path": "/"},
+		}},
+		{"goadmin_roles", []dialect.H{
+			{"name": "Administrator", "slug": "administrator"},
+			{"name": "Operator", "slug": "operator"},
+		}},
+		{"goadmin_users", []dialect.H{
+			{"username": "admin", "password": "$2a$10$U3F/NSaf2kaVbyXTBp7ppOn0jZFyRqXRnYXB.AMioCjXl3Ciaj4oy", "name": "admin", "avatar": ""},
+			{"username": "operator", "password": "$2a$10$rVqkOzHjN2MdlEprRflb1eGP0oZXuSrbJLOmJagFsCd81YZm0bsh.", "name": "Operator", "avatar": ""},
+		}},
+		{"goadmin_role_menu", []dialect.H{
+			{"role_id": 1, "menu_id": 1},
+			{"role_id": 1, "menu_id": 7},
+			{"role_id": 2, "menu_id": 7},
+		}},
+		{"goadmin_role_permissions", []dialect.H{
+			{"role_id": 1, "permission_id": 1},
+			{"role_id": 1, "permission_id": 2},
+			{"role_id": 2, "permission_id": 2},
+		}},
+		{"goadmin_role_users", []dialect.H{
+			{"role_id": 1, "user_id": 1},
+			{"role_id": 2, "user_id": 2},
+		}},
+		{"goadmin_user_permissions", []dialect.H{
+			{"user_id": 1, "permission_id": 1},
+			{"user_id": 2, "permission_id": 2},
+		}},
+	}
+
+	for _, seed := range seeds {
+		if err := s.Seed(seed.table, seed.rows...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package migration
+
+import (
+	dbsql "database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+// TableName is the table recording the applied migrations.
+const TableName = "goadmin_migrations"
+
+// Migration is a versioned change of the database schema. The migrations
+// are applied in the order of their versions, the built-in migrations of
+// the admin tables take the versions below 1000, and the timestamps like
+// 20200102150405 are suggested for the others.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(s *Schema) error
+	Down    func(s *Schema) error
+}
+
+// Status is a migration with its applied state.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt string
+}
+
+var migrations = make(map[int64]Migration)
+
+// Register register the migrations, it panics if a version is registered
+// twice.
+func Register(list ...Migration) {
+	for _, m := range list {
+		if _, ok := migrations[m.Version]; ok {
+			panic("migration version " + strconv.FormatInt(m.Version, 10) + " has been registered")
+		}
+		migrations[m.Version] = m
+	}
+}
+
+// List return the registered migrations ordered by the versions.
+func List() []Migration {
+	list := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+	return list
+}
+
+// Migrator applies and rolls back the registered migrations of a connection.
+type Migrator struct {
+	schema *Schema
+}
+
+// NewMigrator return a Migrator of the connection.
+func NewMigrator(conn db.Connection) *Migrator {
+	return &Migrator{schema: NewSchema(conn)}
+}
+
+// Up apply all the pending migrations and return the applied ones. A
+// database created before the migrations is adopted, its tables and
+// columns are skipped by the Schema.
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]Migration, 0)
+	for _, mig := range List() {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		err := m.transaction(func(s *Schema) error {
+			if mig.Up != nil {
+				if err := mig.Up(s); err != nil {
+					return fmt.Errorf("migration %d %s: %s", mig.Version, mig.Name, err)
+				}
+			}
+			_, err := s.sql().Table(TableName).Insert(dialect.H{
+				"version": mig.Version,
+				"name":    mig.Name,
+			})
+			return err
+		})
+		if err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down roll back the last applied migrations of given steps and return
+// the rolled back ones.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	list := List()
+	done := make([]Migration, 0)
+	for i := len(list) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := list[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if mig.Down == nil {
+			return done, errors.New("migration " + strconv.FormatInt(mig.Version, 10) + " " + mig.Name + " can not be rolled back")
+		}
+		err := m.transaction(func(s *Schema) error {
+			if err := mig.Down(s); err != nil {
+				return fmt.Errorf("migration %d %s: %s", mig.Version, mig.Name, err)
+			}
+			return s.sql().Table(TableName).Where("version", "=", mig.Version).Delete()
+		})
+		if err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Status return all the registered migrations with their applied state.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	list := List()
+	status := make([]Status, len(list))
+	for i, mig := range list {
+		status[i].Migration = mig
+		status[i].AppliedAt, status[i].Applied = applied[mig.Version]
+	}
+	return status, nil
+}
+
+// applied create the migrations table if not exists, and return the
+// applied versions with the applied time.
+func (m *Migrator) applied() (map[int64]string, error) {
+	err := m.schema.CreateTable(dialect.Table{
+		Name: TableName,
+		Columns: append([]dialect.Column{
+			{Name: "id", Type: dialect.Increments},
+			{Name: "version", Type: dialect.BigInt, Unique: true},
+			{Name: "name", Type: dialect.Varchar, Size: 255, Default: "''"},
+		}, dialect.Timestamps()...),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.table().Select("version", "created_at").All()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		version, _ := strconv.ParseInt(fmt.Sprintf("%v", row["version"]), 10, 64)
+		applied[version] = fmt.Sprintf("%v", row["created_at"])
+	}
+	return applied, nil
+}
+
+// transaction run fn with the schema within a transaction, so that a
+// migration and the record of it are applied or rolled back together.
+// MySQL commits a transaction implicitly by a schema change, so the
+// statements of it run without a transaction.
+func (m *Migrator) transaction(fn func(s *Schema) error) error {
+	if m.schema.Driver() == db.DriverMysql {
+		return fn(m.schema)
+	}
+	_, err := db.WithDriver(m.schema.conn).WithTransaction(func(tx *dbsql.Tx) (error, map[string]interface{}) {
+		return fn(m.schema.withTx(tx)), nil
+	})
+	return err
+}
+
+func (m *Migrator) table() *db.SQL {
+	return db.WithDriver(m.schema.conn).Table(TableName)
+}
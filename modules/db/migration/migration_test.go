@@ -0,0 +1,122 @@
+package migration
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	_ "github.com/GoAdminGroup/go-admin/modules/db/drivers/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func testConn(file string) db.Connection {
+	return db.GetConnectionByDriver(db.DriverSqlite).InitDB(map[string]config.Database{
+		"default": {Driver: db.DriverSqlite, File: file},
+	})
+}
+
+func TestMigrator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migration")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	conn := testConn(filepath.Join(dir, "admin.db"))
+	m := NewMigrator(conn)
+
+	applied, err := m.Up()
+	assert.NoError(t, err)
+	assert.Equal(t, len(List()), len(applied))
+
+	user, err := db.WithDriver(conn).Table("goadmin_users").Where("username", "=", "admin").First()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), user["id"])
+
+	s := NewSchema(conn)
+	for _, table := range []string{"goadmin_user_tokens", "goadmin_user_two_factors", "goadmin_user_lockouts", "goadmin_saved_views"} {
+		exist, _ := s.HasTable(table)
+		assert.True(t, exist, table)
+	}
+	exist, _ := s.HasColumn("goadmin_roles", "two_factor_required")
+	assert.True(t, exist)
+
+	applied, err = m.Up()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(applied))
+
+	// the drop column is not supported by the sqlite of the driver, so
+	// only the last migration which drops a table is rolled back.
+	rolledBack, err := m.Down(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rolledBack))
+	assert.Equal(t, int64(6), rolledBack[0].Version)
+
+	exist, _ = s.HasTable("goadmin_saved_views")
+	assert.False(t, exist)
+
+	status, err := m.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, len(List()), len(status))
+	assert.True(t, status[0].Applied)
+	assert.False(t, status[len(status)-1].Applied)
+}
+
+func TestMigratorAdopt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migration")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// a database created by the dump is adopted without touching its data.
+	dump, err := ioutil.ReadFile("../../../data/admin.db")
+	assert.NoError(t, err)
+	file := filepath.Join(dir, "admin.db")
+	assert.NoError(t, ioutil.WriteFile(file, dump, 0644))
+
+	conn := testConn(file)
+	count, _ := db.WithDriver(conn).Table("goadmin_users").Count()
+
+	applied, err := NewMigrator(conn).Up()
+	assert.NoError(t, err)
+	assert.Equal(t, len(List()), len(applied))
+
+	after, _ := db.WithDriver(conn).Table("goadmin_users").Count()
+	assert.Equal(t, count, after)
+}
+
+func TestMigratorTransaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migration")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	m := NewMigrator(testConn(filepath.Join(dir, "admin.db")))
+	_, err = m.applied()
+	assert.NoError(t, err)
+
+	// a failed migration leaves neither its changes nor its record.
+	err = m.transaction(func(s *Schema) error {
+		err := s.CreateTable(dialect.Table{
+			Name:    "goadmin_tmp",
+			Columns: []dialect.Column{{Name: "id", Type: dialect.Increments}},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := s.sql().Table(TableName).Insert(dialect.H{"version": 9999, "name": "tmp"}); err != nil {
+			return err
+		}
+		return errors.New("migration failed")
+	})
+	assert.EqualError(t, err, "migration failed")
+
+	exist, err := m.schema.HasTable("goadmin_tmp")
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	count, err := m.table().Where("version", "=", 9999).Count()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
@@ -0,0 +1,183 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package migration
+
+import (
+	dbsql "database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+// Schema changes the schema of a connection with the statements generated
+// by the dialect of the connection. The existing tables and columns are
+// skipped when they are created, and so are the missing ones when they
+// are dropped. The statements run within the transaction if there is one.
+type Schema struct {
+	conn    db.Connection
+	dialect dialect.Dialect
+	tx      *dbsql.Tx
+}
+
+// NewSchema return a Schema of the connection.
+func NewSchema(conn db.Connection) *Schema {
+	return &Schema{
+		conn:    conn,
+		dialect: dialect.GetDialectByDriver(conn.Name()),
+	}
+}
+
+// withTx return a copy of the Schema running within the transaction.
+func (s *Schema) withTx(tx *dbsql.Tx) *Schema {
+	return &Schema{conn: s.conn, dialect: s.dialect, tx: tx}
+}
+
+func (s *Schema) sql() *db.SQL {
+	return db.WithDriver(s.conn).WithTx(s.tx)
+}
+
+// Driver return the driver name of the connection.
+func (s *Schema) Driver() string {
+	return s.conn.Name()
+}
+
+// Exec execute a raw statement.
+func (s *Schema) Exec(query string, args ...interface{}) error {
+	var err error
+	if s.tx != nil {
+		_, err = s.conn.ExecWithTx(s.tx, query, args...)
+	} else {
+		_, err = s.conn.Exec(query, args...)
+	}
+	return err
+}
+
+// HasTable check the table exists or not.
+func (s *Schema) HasTable(table string) (bool, error) {
+	rows, err := s.sql().ShowTables()
+	if err != nil {
+		return false, err
+	}
+	for _, row := range rows {
+		for _, value := range row {
+			if name, ok := value.(string); ok && strings.EqualFold(name, table) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// HasColumn check the column of the table exists or not.
+func (s *Schema) HasColumn(table, column string) (bool, error) {
+	rows, err := s.sql().Table(table).ShowColumns()
+	if err != nil {
+		return false, err
+	}
+	for _, row := range rows {
+		for _, key := range []string{"Field", "column_name", "name"} {
+			if name, ok := row[key].(string); ok && strings.EqualFold(name, column) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// CreateTable create the table with its indexes if the table not exists.
+func (s *Schema) CreateTable(table dialect.Table) error {
+	exist, err := s.HasTable(table.Name)
+	if err != nil || exist {
+		return err
+	}
+	for _, stmt := range s.dialect.CreateTable(table) {
+		if err := s.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropTable drop the tables which exist.
+func (s *Schema) DropTable(tables ...string) error {
+	for _, table := range tables {
+		exist, err := s.HasTable(table)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			continue
+		}
+		if err := s.Exec(s.dialect.DropTable(table)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddColumn add the columns to the table, the existing ones are skipped.
+func (s *Schema) AddColumn(table string, columns ...dialect.Column) error {
+	for _, column := range columns {
+		exist, err := s.HasColumn(table, column.Name)
+		if err != nil {
+			return err
+		}
+		if exist {
+			continue
+		}
+		if err := s.Exec(s.dialect.AddColumn(table, column)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropColumn drop the columns of the table, the missing ones are skipped.
+func (s *Schema) DropColumn(table string, columns ...string) error {
+	for _, column := range columns {
+		exist, err := s.HasColumn(table, column)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			continue
+		}
+		if err := s.Exec(s.dialect.DropColumn(table, column)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateIndex create the index of the table.
+func (s *Schema) CreateIndex(table string, index dialect.Index) error {
+	return s.Exec(s.dialect.CreateIndex(table, index))
+}
+
+// DropIndex drop the index of the table.
+func (s *Schema) DropIndex(table, index string) error {
+	return s.Exec(s.dialect.DropIndex(table, index))
+}
+
+// Seed insert the rows into the table only when the table has no rows,
+// so that the data of an existing database is kept.
+func (s *Schema) Seed(table string, rows ...dialect.H) error {
+	count, err := s.sql().Table(table).Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	for _, row := range rows {
+		comp := &dialect.SQLComponent{TableName: table, Values: row}
+		if err := s.Exec(s.dialect.Insert(comp), comp.Args...); err != nil {
+			return fmt.Errorf("seed %s: %s", table, err)
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strconv"
@@ -143,31 +144,28 @@ func (db *Mssql) parseSql(sql string) string {
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
 func (db *Mssql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	query = db.handleSqlBeforeExec(query)
-	return CommonQuery(db.DbList[con], query, args...)
+	return db.QueryWithConnectionAndContext(context.Background(), con, query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
 func (db *Mssql) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
-	query = db.handleSqlBeforeExec(query)
-	return CommonExec(db.DbList[con], query, args...)
+	return db.ExecWithConnectionAndContext(context.Background(), con, query, args...)
 }
 
 // Query implements the method Connection.Query.
 func (db *Mssql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	query = db.handleSqlBeforeExec(query)
-	return CommonQuery(db.DbList["default"], query, args...)
+	return db.QueryWithConnectionAndContext(context.Background(), "default", query, args...)
 }
 
 // Exec implements the method Connection.Exec.
 func (db *Mssql) Exec(query string, args ...interface{}) (sql.Result, error) {
-	query = db.handleSqlBeforeExec(query)
-	return CommonExec(db.DbList["default"], query, args...)
+	return db.ExecWithConnectionAndContext(context.Background(), "default", query, args...)
 }
 
 // InitDB implements the method Connection.InitDB.
 func (db *Mssql) InitDB(cfglist map[string]config.Database) Connection {
 	db.Once.Do(func() {
+		db.Configs = cfglist
 		for conn, cfg := range cfglist {
 
 			if cfg.Dsn == "" {
@@ -248,12 +246,54 @@ func (db *Mssql) BeginTxWithLevelAndConnection(conn string, level sql.IsolationL
 
 // QueryWithTx is query method within the transaction.
 func (db *Mssql) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	query = db.handleSqlBeforeExec(query)
-	return CommonQueryWithTx(tx, query, args...)
+	return db.QueryWithTxAndContext(context.Background(), tx, query, args...)
 }
 
 // ExecWithTx is exec method within the transaction.
 func (db *Mssql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
-	query = db.handleSqlBeforeExec(query)
-	return CommonExecWithTx(tx, query, args...)
+	return db.ExecWithTxAndContext(context.Background(), tx, query, args...)
+}
+
+// QueryWithContext implements the method Connection.QueryWithContext.
+func (db *Mssql) QueryWithContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return db.QueryWithConnectionAndContext(ctx, "default", query, args...)
+}
+
+// ExecWithContext implements the method Connection.ExecWithContext.
+func (db *Mssql) ExecWithContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecWithConnectionAndContext(ctx, "default", query, args...)
+}
+
+// QueryWithConnectionAndContext implements the method Connection.QueryWithConnectionAndContext.
+func (db *Mssql) QueryWithConnectionAndContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonQueryWithContext(ctx, db.DbList[con], db.handleSqlBeforeExec(query), args...)
+}
+
+// ExecWithConnectionAndContext implements the method Connection.ExecWithConnectionAndContext.
+func (db *Mssql) ExecWithConnectionAndContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonExecWithContext(ctx, db.DbList[con], db.handleSqlBeforeExec(query), args...)
+}
+
+// QueryWithTxAndContext is query method within the transaction with the context.
+func (db *Mssql) QueryWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTxTimeout(ctx)
+	defer cancel()
+	return CommonQueryWithTxAndContext(ctx, tx, db.handleSqlBeforeExec(query), args...)
+}
+
+// ExecWithTxAndContext is exec method within the transaction with the context.
+func (db *Mssql) ExecWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTxTimeout(ctx)
+	defer cancel()
+	return CommonExecWithTxAndContext(ctx, tx, db.handleSqlBeforeExec(query), args...)
+}
+
+// BeginTxWithContext starts a transaction with given transaction isolation level and connection,
+// the transaction is rolled back when the context is done.
+func (db *Mssql) BeginTxWithContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxWithContext(ctx, db.DbList[conn], level)
 }
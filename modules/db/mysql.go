@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 )
@@ -41,6 +42,7 @@ func (db *Mysql) GetDelimiter() string {
 // InitDB implements the method Connection.InitDB.
 func (db *Mysql) InitDB(cfgs map[string]config.Database) Connection {
 	db.Once.Do(func() {
+		db.Configs = cfgs
 		for conn, cfg := range cfgs {
 
 			if cfg.Dsn == "" {
@@ -68,22 +70,22 @@ func (db *Mysql) InitDB(cfgs map[string]config.Database) Connection {
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
 func (db *Mysql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList[con], query, args...)
+	return db.QueryWithConnectionAndContext(context.Background(), con, query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
 func (db *Mysql) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList[con], query, args...)
+	return db.ExecWithConnectionAndContext(context.Background(), con, query, args...)
 }
 
 // Query implements the method Connection.Query.
 func (db *Mysql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList["default"], query, args...)
+	return db.QueryWithConnectionAndContext(context.Background(), "default", query, args...)
 }
 
 // Exec implements the method Connection.Exec.
 func (db *Mysql) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList["default"], query, args...)
+	return db.ExecWithConnectionAndContext(context.Background(), "default", query, args...)
 }
 
 // BeginTxWithReadUncommitted starts a transaction with level LevelReadUncommitted.
@@ -138,10 +140,54 @@ func (db *Mysql) BeginTxWithLevelAndConnection(conn string, level sql.IsolationL
 
 // QueryWithTx is query method within the transaction.
 func (db *Mysql) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQueryWithTx(tx, query, args...)
+	return db.QueryWithTxAndContext(context.Background(), tx, query, args...)
 }
 
 // ExecWithTx is exec method within the transaction.
 func (db *Mysql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
-	return CommonExecWithTx(tx, query, args...)
+	return db.ExecWithTxAndContext(context.Background(), tx, query, args...)
+}
+
+// QueryWithContext implements the method Connection.QueryWithContext.
+func (db *Mysql) QueryWithContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return db.QueryWithConnectionAndContext(ctx, "default", query, args...)
+}
+
+// ExecWithContext implements the method Connection.ExecWithContext.
+func (db *Mysql) ExecWithContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecWithConnectionAndContext(ctx, "default", query, args...)
+}
+
+// QueryWithConnectionAndContext implements the method Connection.QueryWithConnectionAndContext.
+func (db *Mysql) QueryWithConnectionAndContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonQueryWithContext(ctx, db.DbList[con], query, args...)
+}
+
+// ExecWithConnectionAndContext implements the method Connection.ExecWithConnectionAndContext.
+func (db *Mysql) ExecWithConnectionAndContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonExecWithContext(ctx, db.DbList[con], query, args...)
+}
+
+// QueryWithTxAndContext is query method within the transaction with the context.
+func (db *Mysql) QueryWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTxTimeout(ctx)
+	defer cancel()
+	return CommonQueryWithTxAndContext(ctx, tx, query, args...)
+}
+
+// ExecWithTxAndContext is exec method within the transaction with the context.
+func (db *Mysql) ExecWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTxTimeout(ctx)
+	defer cancel()
+	return CommonExecWithTxAndContext(ctx, tx, query, args...)
+}
+
+// BeginTxWithContext starts a transaction with given transaction isolation level and connection,
+// the transaction is rolled back when the context is done.
+func (db *Mysql) BeginTxWithContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxWithContext(ctx, db.DbList[conn], level)
 }
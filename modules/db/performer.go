@@ -13,17 +13,23 @@ import (
 
 // CommonQuery is a common method of query.
 func CommonQuery(db *sql.DB, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryWithContext(context.Background(), db, query, args...)
+}
 
-	rs, err := db.Query(query, args...)
-
+// CommonQueryWithContext is a common method of query with the context.
+func CommonQueryWithContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rs, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	return scanRows(rs)
+}
+
+// scanRows scan all the rows into the maps and close the rows.
+func scanRows(rs *sql.Rows) ([]map[string]interface{}, error) {
 
 	defer func() {
-		if rs != nil {
-			_ = rs.Close()
-		}
+		_ = rs.Close()
 	}()
 
 	col, colErr := rs.Columns()
@@ -66,8 +72,13 @@ func CommonQuery(db *sql.DB, query string, args ...interface{}) ([]map[string]in
 
 // CommonExec is a common method of exec.
 func CommonExec(db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecWithContext(context.Background(), db, query, args...)
+}
+
+// CommonExecWithContext is a common method of exec with the context.
+func CommonExecWithContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
 
-	rs, err := db.Exec(query, args...)
+	rs, err := db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -76,60 +87,28 @@ func CommonExec(db *sql.DB, query string, args ...interface{}) (sql.Result, erro
 
 // CommonQueryWithTx is a common method of query.
 func CommonQueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryWithTxAndContext(context.Background(), tx, query, args...)
+}
 
-	rs, err := tx.Query(query, args...)
-
-	if err != nil {
-		panic(err)
-	}
-
-	defer func() {
-		if rs != nil {
-			_ = rs.Close()
-		}
-	}()
-
-	col, colErr := rs.Columns()
-
-	if colErr != nil {
-		return nil, colErr
-	}
-
-	typeVal, err := rs.ColumnTypes()
+// CommonQueryWithTxAndContext is a common method of query within the
+// transaction with the context.
+func CommonQueryWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rs, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
-
-	// TODO: regular expressions for sqlite, use the dialect module
-	// tell the drive to reduce the performance loss
-	results := make([]map[string]interface{}, 0)
-
-	r, _ := regexp.Compile(`\\((.*)\\)`)
-	for rs.Next() {
-		var colVar = make([]interface{}, len(col))
-		for i := 0; i < len(col); i++ {
-			typeName := strings.ToUpper(r.ReplaceAllString(typeVal[i].DatabaseTypeName(), ""))
-			SetColVarType(&colVar, i, typeName)
-		}
-		result := make(map[string]interface{})
-		if scanErr := rs.Scan(colVar...); scanErr != nil {
-			return nil, scanErr
-		}
-		for j := 0; j < len(col); j++ {
-			typeName := strings.ToUpper(r.ReplaceAllString(typeVal[j].DatabaseTypeName(), ""))
-			SetResultValue(&result, col[j], colVar[j], typeName)
-		}
-		results = append(results, result)
-	}
-	if err := rs.Err(); err != nil {
-		return nil, err
-	}
-	return results, nil
+	return scanRows(rs)
 }
 
 // CommonExecWithTx is a common method of exec.
 func CommonExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
-	rs, err := tx.Exec(query, args...)
+	return CommonExecWithTxAndContext(context.Background(), tx, query, args...)
+}
+
+// CommonExecWithTxAndContext is a common method of exec within the
+// transaction with the context.
+func CommonExecWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	rs, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -138,9 +117,15 @@ func CommonExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result
 
 // CommonBeginTxWithLevel starts a transaction with given transaction isolation level and db connection.
 func CommonBeginTxWithLevel(db *sql.DB, level sql.IsolationLevel) *sql.Tx {
-	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: level})
+	tx, err := CommonBeginTxWithContext(context.Background(), db, level)
 	if err != nil {
 		panic(err)
 	}
 	return tx
 }
+
+// CommonBeginTxWithContext starts a transaction with given transaction isolation level and db connection,
+// the transaction is rolled back when the context is done.
+func CommonBeginTxWithContext(ctx context.Context, db *sql.DB, level sql.IsolationLevel) (*sql.Tx, error) {
+	return db.BeginTx(ctx, &sql.TxOptions{Isolation: level})
+}
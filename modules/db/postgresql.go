@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/GoAdminGroup/go-admin/modules/config"
@@ -38,22 +39,22 @@ func (db *Postgresql) GetDelimiter() string {
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
 func (db *Postgresql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList[con], filterQuery(query), args...)
+	return db.QueryWithConnectionAndContext(context.Background(), con, query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
 func (db *Postgresql) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList[con], filterQuery(query), args...)
+	return db.ExecWithConnectionAndContext(context.Background(), con, query, args...)
 }
 
 // Query implements the method Connection.Query.
 func (db *Postgresql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList["default"], filterQuery(query), args...)
+	return db.QueryWithConnectionAndContext(context.Background(), "default", query, args...)
 }
 
 // Exec implements the method Connection.Exec.
 func (db *Postgresql) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList["default"], filterQuery(query), args...)
+	return db.ExecWithConnectionAndContext(context.Background(), "default", query, args...)
 }
 
 func filterQuery(query string) string {
@@ -69,6 +70,7 @@ func filterQuery(query string) string {
 // InitDB implements the method Connection.InitDB.
 func (db *Postgresql) InitDB(cfgList map[string]config.Database) Connection {
 	db.Once.Do(func() {
+		db.Configs = cfgList
 		for conn, cfg := range cfgList {
 
 			if cfg.Dsn == "" {
@@ -139,10 +141,54 @@ func (db *Postgresql) BeginTxWithLevelAndConnection(conn string, level sql.Isola
 
 // QueryWithTx is query method within the transaction.
 func (db *Postgresql) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQueryWithTx(tx, filterQuery(query), args...)
+	return db.QueryWithTxAndContext(context.Background(), tx, query, args...)
 }
 
 // ExecWithTx is exec method within the transaction.
 func (db *Postgresql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
-	return CommonExecWithTx(tx, filterQuery(query), args...)
+	return db.ExecWithTxAndContext(context.Background(), tx, query, args...)
+}
+
+// QueryWithContext implements the method Connection.QueryWithContext.
+func (db *Postgresql) QueryWithContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return db.QueryWithConnectionAndContext(ctx, "default", query, args...)
+}
+
+// ExecWithContext implements the method Connection.ExecWithContext.
+func (db *Postgresql) ExecWithContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecWithConnectionAndContext(ctx, "default", query, args...)
+}
+
+// QueryWithConnectionAndContext implements the method Connection.QueryWithConnectionAndContext.
+func (db *Postgresql) QueryWithConnectionAndContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonQueryWithContext(ctx, db.DbList[con], filterQuery(query), args...)
+}
+
+// ExecWithConnectionAndContext implements the method Connection.ExecWithConnectionAndContext.
+func (db *Postgresql) ExecWithConnectionAndContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonExecWithContext(ctx, db.DbList[con], filterQuery(query), args...)
+}
+
+// QueryWithTxAndContext is query method within the transaction with the context.
+func (db *Postgresql) QueryWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTxTimeout(ctx)
+	defer cancel()
+	return CommonQueryWithTxAndContext(ctx, tx, filterQuery(query), args...)
+}
+
+// ExecWithTxAndContext is exec method within the transaction with the context.
+func (db *Postgresql) ExecWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTxTimeout(ctx)
+	defer cancel()
+	return CommonExecWithTxAndContext(ctx, tx, filterQuery(query), args...)
+}
+
+// BeginTxWithContext starts a transaction with given transaction isolation level and connection,
+// the transaction is rolled back when the context is done.
+func (db *Postgresql) BeginTxWithContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxWithContext(ctx, db.DbList[conn], level)
 }
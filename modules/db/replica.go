@@ -0,0 +1,105 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ReplicaCheckInterval is the interval of the health checks of the replicas.
+var ReplicaCheckInterval = 10 * time.Second
+
+// replicaCheckTimeout is the timeout of a ping of the health check.
+const replicaCheckTimeout = 2 * time.Second
+
+type replicaState struct {
+	healthy   bool
+	checkedAt time.Time
+	checking  bool
+}
+
+// replicaRouter picks the replicas of the connections in turn. The state
+// of a replica is cached and checked again in the background after
+// ReplicaCheckInterval, so that the reads never wait for the pings.
+type replicaRouter struct {
+	lock   sync.Mutex
+	next   map[string]int
+	states map[string]*replicaState
+}
+
+// state return the state of the connection, the lock must be held.
+func (r *replicaRouter) state(conn string) *replicaState {
+	if r.states == nil {
+		r.states = make(map[string]*replicaState)
+	}
+	state, ok := r.states[conn]
+	if !ok {
+		state = new(replicaState)
+		r.states[conn] = state
+	}
+	return state
+}
+
+// ReadConnection implements the method Connection.ReadConnection.
+func (db *Base) ReadConnection(conn string, replicas ...string) string {
+	if len(replicas) == 0 {
+		replicas = db.Configs[conn].Replicas
+	}
+	if len(replicas) == 0 {
+		return conn
+	}
+
+	db.replicas.lock.Lock()
+	if db.replicas.next == nil {
+		db.replicas.next = make(map[string]int)
+	}
+	start := db.replicas.next[conn]
+	db.replicas.next[conn] = (start + 1) % len(replicas)
+	db.replicas.lock.Unlock()
+
+	for i := 0; i < len(replicas); i++ {
+		name := replicas[(start+i)%len(replicas)]
+		if db.isHealthy(name) {
+			return name
+		}
+	}
+	return conn
+}
+
+// CheckConnection implements the method Connection.CheckConnection.
+func (db *Base) CheckConnection(conn string) bool {
+	healthy := false
+	if sqlDB, ok := db.DbList[conn]; ok && sqlDB != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), replicaCheckTimeout)
+		healthy = sqlDB.PingContext(ctx) == nil
+		cancel()
+	}
+
+	db.replicas.lock.Lock()
+	state := db.replicas.state(conn)
+	state.healthy = healthy
+	state.checkedAt = time.Now()
+	state.checking = false
+	db.replicas.lock.Unlock()
+
+	return healthy
+}
+
+// isHealthy return the cached health of the connection, and start a check
+// in the background when it is out of date. A connection never checked is
+// not healthy until the first check is done.
+func (db *Base) isHealthy(conn string) bool {
+	db.replicas.lock.Lock()
+	defer db.replicas.lock.Unlock()
+
+	state := db.replicas.state(conn)
+	if !state.checking && time.Since(state.checkedAt) >= ReplicaCheckInterval {
+		state.checking = true
+		go db.CheckConnection(conn)
+	}
+	return state.healthy
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	_ "github.com/GoAdminGroup/go-admin/modules/db/drivers/sqlite"
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testReplicaConn(t *testing.T) (*Sqlite, func()) {
+	dir, err := ioutil.TempDir("", "replica")
+	assert.Equal(t, err, nil)
+
+	conn := GetSqliteDB()
+	conn.InitDB(map[string]config.Database{
+		"default": {Driver: DriverSqlite, File: filepath.Join(dir, "default.db"), Replicas: []string{"r1", "r2"}},
+		"r1":      {Driver: DriverSqlite, File: filepath.Join(dir, "r1.db")},
+		"r2":      {Driver: DriverSqlite, File: filepath.Join(dir, "r2.db")},
+	})
+
+	return conn, func() {
+		_ = conn.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// waitChecked wait for the background health checks of the connections.
+func waitChecked(conn *Sqlite, names ...string) {
+	for i := 0; i < 100; i++ {
+		conn.replicas.lock.Lock()
+		done := true
+		for _, name := range names {
+			if state, ok := conn.replicas.states[name]; !ok || state.checking || state.checkedAt.IsZero() {
+				done = false
+			}
+		}
+		conn.replicas.lock.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReadConnection(t *testing.T) {
+	conn, done := testReplicaConn(t)
+	defer done()
+
+	// the replicas never checked are not used until the checks are done.
+	assert.Equal(t, conn.ReadConnection("default"), "default")
+	waitChecked(conn, "r1", "r2")
+
+	// the healthy replicas are picked in turn.
+	first := conn.ReadConnection("default")
+	second := conn.ReadConnection("default")
+	assert.Equal(t, first != second, true)
+	assert.Equal(t, conn.ReadConnection("default"), first)
+
+	assert.Equal(t, conn.ReadConnection("default", "r2"), "r2")
+	assert.Equal(t, conn.ReadConnection("r1"), "r1")
+
+	// the unhealthy replicas are skipped.
+	_ = conn.DbList["r2"].Close()
+	assert.Equal(t, conn.CheckConnection("r2"), false)
+	assert.Equal(t, conn.ReadConnection("default"), "r1")
+	assert.Equal(t, conn.ReadConnection("default"), "r1")
+
+	// the primary is used when no replica is healthy.
+	_ = conn.DbList["r1"].Close()
+	assert.Equal(t, conn.CheckConnection("r1"), false)
+	assert.Equal(t, conn.ReadConnection("default"), "default")
+	assert.Equal(t, conn.ReadConnection("default", "r3"), "default")
+}
+
+func TestReadConnectionRecheck(t *testing.T) {
+	conn, done := testReplicaConn(t)
+	defer done()
+
+	interval := ReplicaCheckInterval
+	ReplicaCheckInterval = 50 * time.Millisecond
+	defer func() {
+		ReplicaCheckInterval = interval
+	}()
+
+	assert.Equal(t, conn.CheckConnection("r1"), true)
+	assert.Equal(t, conn.CheckConnection("r2"), true)
+
+	// the cached health is used until it is out of date, and then it is
+	// checked in the background while the cached one is still used.
+	_ = conn.DbList["r1"].Close()
+	_ = conn.DbList["r2"].Close()
+	assert.Equal(t, conn.ReadConnection("default"), "r1")
+
+	time.Sleep(ReplicaCheckInterval)
+	assert.Equal(t, conn.ReadConnection("default"), "r2")
+	assert.Equal(t, conn.ReadConnection("default"), "r1")
+
+	waitChecked(conn, "r1", "r2")
+	assert.Equal(t, conn.ReadConnection("default"), "default")
+}
@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 )
@@ -35,27 +36,28 @@ func (db *Sqlite) GetDelimiter() string {
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
 func (db *Sqlite) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList[con], query, args...)
+	return db.QueryWithConnectionAndContext(context.Background(), con, query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
 func (db *Sqlite) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList[con], query, args...)
+	return db.ExecWithConnectionAndContext(context.Background(), con, query, args...)
 }
 
 // Query implements the method Connection.Query.
 func (db *Sqlite) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList["default"], query, args...)
+	return db.QueryWithConnectionAndContext(context.Background(), "default", query, args...)
 }
 
 // Exec implements the method Connection.Exec.
 func (db *Sqlite) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList["default"], query, args...)
+	return db.ExecWithConnectionAndContext(context.Background(), "default", query, args...)
 }
 
 // InitDB implements the method Connection.InitDB.
 func (db *Sqlite) InitDB(cfgList map[string]config.Database) Connection {
 	db.Once.Do(func() {
+		db.Configs = cfgList
 		for conn, cfg := range cfgList {
 			sqlDB, err := sql.Open("sqlite3", cfg.File)
 
@@ -121,10 +123,54 @@ func (db *Sqlite) BeginTxWithLevelAndConnection(conn string, level sql.Isolation
 
 // QueryWithTx is query method within the transaction.
 func (db *Sqlite) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQueryWithTx(tx, query, args...)
+	return db.QueryWithTxAndContext(context.Background(), tx, query, args...)
 }
 
 // ExecWithTx is exec method within the transaction.
 func (db *Sqlite) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
-	return CommonExecWithTx(tx, query, args...)
+	return db.ExecWithTxAndContext(context.Background(), tx, query, args...)
+}
+
+// QueryWithContext implements the method Connection.QueryWithContext.
+func (db *Sqlite) QueryWithContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return db.QueryWithConnectionAndContext(ctx, "default", query, args...)
+}
+
+// ExecWithContext implements the method Connection.ExecWithContext.
+func (db *Sqlite) ExecWithContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecWithConnectionAndContext(ctx, "default", query, args...)
+}
+
+// QueryWithConnectionAndContext implements the method Connection.QueryWithConnectionAndContext.
+func (db *Sqlite) QueryWithConnectionAndContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonQueryWithContext(ctx, db.DbList[con], query, args...)
+}
+
+// ExecWithConnectionAndContext implements the method Connection.ExecWithConnectionAndContext.
+func (db *Sqlite) ExecWithConnectionAndContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonExecWithContext(ctx, db.DbList[con], query, args...)
+}
+
+// QueryWithTxAndContext is query method within the transaction with the context.
+func (db *Sqlite) QueryWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTxTimeout(ctx)
+	defer cancel()
+	return CommonQueryWithTxAndContext(ctx, tx, query, args...)
+}
+
+// ExecWithTxAndContext is exec method within the transaction with the context.
+func (db *Sqlite) ExecWithTxAndContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTxTimeout(ctx)
+	defer cancel()
+	return CommonExecWithTxAndContext(ctx, tx, query, args...)
+}
+
+// BeginTxWithContext starts a transaction with given transaction isolation level and connection,
+// the transaction is rolled back when the context is done.
+func (db *Sqlite) BeginTxWithContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxWithContext(ctx, db.DbList[conn], level)
 }
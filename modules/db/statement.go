@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	dbsql "database/sql"
 	"errors"
 	"fmt"
@@ -24,6 +25,7 @@ type SQL struct {
 	dialect dialect.Dialect
 	conn    string
 	tx      *dbsql.Tx
+	ctx     context.Context
 }
 
 // SQLPool is a object pool of SQL.
@@ -44,6 +46,7 @@ var SQLPool = sync.Pool{
 			},
 			diver:   nil,
 			dialect: nil,
+			ctx:     context.Background(),
 		}
 	},
 }
@@ -105,6 +108,13 @@ func (sql *SQL) WithTx(tx *dbsql.Tx) *SQL {
 	return sql
 }
 
+// WithContext set the context of SQL, the statements are cancelled when
+// the context is done.
+func (sql *SQL) WithContext(ctx context.Context) *SQL {
+	sql.ctx = ctx
+	return sql
+}
+
 // TableName set table of SQL.
 func (sql *SQL) Table(table string) *SQL {
 	sql.clean()
@@ -365,7 +375,10 @@ type TxFn func(tx *dbsql.Tx) (error, map[string]interface{})
 // catch the error.
 func (sql *SQL) WithTransaction(fn TxFn) (res map[string]interface{}, err error) {
 
-	tx := sql.diver.BeginTxAndConnection(sql.conn)
+	tx, err := sql.diver.BeginTxWithContext(sql.ctx, sql.conn, dbsql.LevelDefault)
+	if err != nil {
+		return nil, err
+	}
 
 	defer func() {
 		if p := recover(); p != nil {
@@ -389,7 +402,10 @@ func (sql *SQL) WithTransaction(fn TxFn) (res map[string]interface{}, err error)
 // of given transaction level and catch the error.
 func (sql *SQL) WithTransactionByLevel(level dbsql.IsolationLevel, fn TxFn) (res map[string]interface{}, err error) {
 
-	tx := sql.diver.BeginTxWithLevelAndConnection(sql.conn, level)
+	tx, err := sql.diver.BeginTxWithContext(sql.ctx, sql.conn, level)
+	if err != nil {
+		return nil, err
+	}
 
 	defer func() {
 		if p := recover(); p != nil {
@@ -427,11 +443,7 @@ func (sql *SQL) First() (map[string]interface{}, error) {
 		err error
 	)
 
-	if sql.tx != nil {
-		res, err = sql.diver.QueryWithTx(sql.tx, sql.Statement, sql.Args...)
-	} else {
-		res, err = sql.diver.QueryWithConnection(sql.conn, sql.Statement, sql.Args...)
-	}
+	res, err = sql.query()
 
 	if err != nil {
 		return nil, err
@@ -449,24 +461,25 @@ func (sql *SQL) All() ([]map[string]interface{}, error) {
 
 	sql.dialect.Select(&sql.SQLComponent)
 
-	if sql.tx != nil {
-		return sql.diver.QueryWithTx(sql.tx, sql.Statement, sql.Args...)
-	}
-	return sql.diver.QueryWithConnection(sql.conn, sql.Statement, sql.Args...)
+	return sql.query()
 }
 
-// ShowColumns show columns info.
+// ShowColumns show columns info, within the transaction if there is one.
 func (sql *SQL) ShowColumns() ([]map[string]interface{}, error) {
 	defer RecycleSQL(sql)
 
-	return sql.diver.QueryWithConnection(sql.conn, sql.dialect.ShowColumns(sql.TableName))
+	sql.Statement = sql.dialect.ShowColumns(sql.TableName)
+
+	return sql.query()
 }
 
-// ShowTables show table info.
+// ShowTables show table info, within the transaction if there is one.
 func (sql *SQL) ShowTables() ([]map[string]interface{}, error) {
 	defer RecycleSQL(sql)
 
-	return sql.diver.QueryWithConnection(sql.conn, sql.dialect.ShowTables())
+	sql.Statement = sql.dialect.ShowTables()
+
+	return sql.query()
 }
 
 // Update exec the update method of given key/value pairs.
@@ -482,11 +495,7 @@ func (sql *SQL) Update(values dialect.H) (int64, error) {
 		err error
 	)
 
-	if sql.tx != nil {
-		res, err = sql.diver.ExecWithTx(sql.tx, sql.Statement, sql.Args...)
-	} else {
-		res, err = sql.diver.ExecWithConnection(sql.conn, sql.Statement, sql.Args...)
-	}
+	res, err = sql.exec()
 
 	if err != nil {
 		return 0, err
@@ -510,11 +519,7 @@ func (sql *SQL) Delete() error {
 		err error
 	)
 
-	if sql.tx != nil {
-		res, err = sql.diver.ExecWithTx(sql.tx, sql.Statement, sql.Args...)
-	} else {
-		res, err = sql.diver.ExecWithConnection(sql.conn, sql.Statement, sql.Args...)
-	}
+	res, err = sql.exec()
 
 	if err != nil {
 		return err
@@ -538,11 +543,7 @@ func (sql *SQL) Exec() (int64, error) {
 		err error
 	)
 
-	if sql.tx != nil {
-		res, err = sql.diver.ExecWithTx(sql.tx, sql.Statement, sql.Args...)
-	} else {
-		res, err = sql.diver.ExecWithConnection(sql.conn, sql.Statement, sql.Args...)
-	}
+	res, err = sql.exec()
 
 	if err != nil {
 		return 0, err
@@ -555,49 +556,92 @@ func (sql *SQL) Exec() (int64, error) {
 	return res.LastInsertId()
 }
 
-// Insert exec the insert method of given key/value pairs.
+// Insert exec the insert method of given key/value pairs, and return the
+// id of the inserted row, which is zero if the table has no auto increment
+// id. Use InsertReturning for the tables whose primary key is not an auto
+// increment id.
 func (sql *SQL) Insert(values dialect.H) (int64, error) {
 	defer RecycleSQL(sql)
 
 	sql.Values = values
 
+	switch sql.diver.Name() {
+	case DriverPostgresql:
+		// the whole row is returned, as the table may have no id column.
+		sql.Returning = []string{"*"}
+	case DriverMssql:
+		column, err := sql.autoIncrementColumn()
+		if err != nil {
+			return 0, err
+		}
+		if column != "" {
+			sql.Returning = []string{column}
+		}
+	}
+
 	sql.dialect.Insert(&sql.SQLComponent)
 
-	var (
-		res    dbsql.Result
-		err    error
-		resMap []map[string]interface{}
-	)
+	if len(sql.Returning) == 0 {
+		return sql.insert()
+	}
+
+	row, err := sql.insertedRow()
+
+	if err != nil {
+		return 0, err
+	}
 
 	if sql.diver.Name() == DriverPostgresql {
-		if sql.TableName == "goadmin_menu" ||
-			sql.TableName == "goadmin_permissions" ||
-			sql.TableName == "goadmin_roles" ||
-			sql.TableName == "goadmin_users" {
-
-			if sql.tx != nil {
-				resMap, err = sql.diver.QueryWithTx(sql.tx, sql.Statement+" RETURNING id", sql.Args...)
-			} else {
-				resMap, err = sql.diver.QueryWithConnection(sql.conn, sql.Statement+" RETURNING id", sql.Args...)
-			}
-
-			if err != nil {
-				return 0, err
-			}
-
-			if len(resMap) == 0 {
-				return 0, errors.New("no affect row")
-			}
-			return resMap[0]["id"].(int64), nil
+		return toInt64(row["id"]), nil
+	}
+	return toInt64(row[sql.Returning[0]]), nil
+}
+
+// InsertReturning exec the insert method of given key/value pairs, and
+// return the values of the given keys of the inserted row, the keys are
+// usually the primary keys of the table. Postgresql returns the keys by
+// the returning clause and mssql by the output clause. The other drivers
+// only return the id of the auto increment column, the keys given in the
+// values are returned as they are and the other ones are selected by the
+// id. The keys which can not be found are nil.
+func (sql *SQL) InsertReturning(values dialect.H, keys ...string) (map[string]interface{}, error) {
+	defer RecycleSQL(sql)
+
+	sql.Values = values
+
+	if len(keys) > 0 && (sql.diver.Name() == DriverPostgresql || sql.diver.Name() == DriverMssql) {
+		sql.Returning = keys
+		sql.dialect.Insert(&sql.SQLComponent)
+
+		row, err := sql.insertedRow()
+
+		if err != nil {
+			return nil, err
+		}
+
+		res := make(map[string]interface{}, len(keys))
+		for _, key := range keys {
+			res[key] = row[key]
 		}
+		return res, nil
 	}
 
-	if sql.tx != nil {
-		res, err = sql.diver.ExecWithTx(sql.tx, sql.Statement, sql.Args...)
-	} else {
-		res, err = sql.diver.ExecWithConnection(sql.conn, sql.Statement, sql.Args...)
+	sql.dialect.Insert(&sql.SQLComponent)
+
+	id, err := sql.insert()
+
+	if err != nil {
+		return nil, err
 	}
 
+	return sql.insertedKeys(values, keys, id)
+}
+
+// insert exec the insert statement and return the last insert id, which
+// is zero for postgresql and mssql as they do not support it.
+func (sql *SQL) insert() (int64, error) {
+	res, err := sql.exec()
+
 	if err != nil {
 		return 0, err
 	}
@@ -606,9 +650,179 @@ func (sql *SQL) Insert(values dialect.H) (int64, error) {
 		return 0, errors.New("no affect row")
 	}
 
+	if sql.diver.Name() == DriverPostgresql || sql.diver.Name() == DriverMssql {
+		return 0, nil
+	}
+
 	return res.LastInsertId()
 }
 
+// insertedRow exec the insert statement with the returning or the output
+// clause and return the inserted row of it.
+func (sql *SQL) insertedRow() (map[string]interface{}, error) {
+	resMap, err := sql.query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resMap) == 0 {
+		return nil, errors.New("no affect row")
+	}
+
+	return resMap[0], nil
+}
+
+// insertedKeys return the given keys of the row inserted with the values
+// and the last insert id. The keys are nil if the table has no auto
+// increment column, or the row can not be selected by it.
+func (sql *SQL) insertedKeys(values dialect.H, keys []string, id int64) (map[string]interface{}, error) {
+	row := make(map[string]interface{}, len(keys))
+	missing := make([]string, 0)
+	for _, key := range keys {
+		if value, ok := values[key]; ok {
+			row[key] = value
+		} else {
+			row[key] = nil
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) == 0 || id == 0 {
+		return row, nil
+	}
+
+	column, err := sql.autoIncrementColumn()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if column == "" {
+		return row, nil
+	}
+
+	if len(missing) == 1 && missing[0] == column {
+		row[column] = id
+		return row, nil
+	}
+
+	fields := make([]string, len(missing))
+	for i, key := range missing {
+		fields[i] = sql.wrap(key)
+	}
+
+	sql.Statement = "select " + strings.Join(fields, ",") + " from " + sql.TableName +
+		" where " + sql.wrap(column) + " = ?"
+	sql.Args = []interface{}{id}
+
+	// the keys are not found rather than failing the insertion, such as the
+	// tables of sqlite without the rowid.
+	if resMap, err := sql.query(); err == nil && len(resMap) > 0 {
+		for _, key := range missing {
+			row[key] = resMap[0][key]
+		}
+	}
+	return row, nil
+}
+
+// autoIncrementColumns caches the auto increment columns of the tables,
+// whose keys are the drivers, the connections and the tables.
+var autoIncrementColumns sync.Map
+
+// autoIncrementColumn return the column of the table whose value is the
+// last insert id, which is the rowid of sqlite, and empty if there is no
+// such column. The columns are queried once for each table.
+func (sql *SQL) autoIncrementColumn() (string, error) {
+	if sql.diver.Name() == DriverSqlite {
+		return "rowid", nil
+	}
+
+	key := sql.diver.Name() + "." + sql.conn + "." + sql.TableName
+	if column, ok := autoIncrementColumns.Load(key); ok {
+		return column.(string), nil
+	}
+
+	var (
+		statement string
+		args      []interface{}
+	)
+
+	if sql.diver.Name() == DriverMssql {
+		statement = "select name from sys.identity_columns where object_id = object_id(?)"
+		args = []interface{}{sql.TableName}
+	} else {
+		statement = sql.dialect.ShowColumns(sql.TableName)
+	}
+
+	var (
+		columns []map[string]interface{}
+		err     error
+	)
+
+	if sql.tx != nil {
+		columns, err = sql.diver.QueryWithTxAndContext(sql.ctx, sql.tx, statement, args...)
+	} else {
+		columns, err = sql.diver.QueryWithConnectionAndContext(sql.ctx, sql.conn, statement, args...)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	column := ""
+	for _, col := range columns {
+		if sql.diver.Name() == DriverMssql {
+			column = fmt.Sprintf("%s", col["name"])
+			break
+		}
+		if extra, ok := col["Extra"].(string); ok && strings.Contains(extra, "auto_increment") {
+			column = fmt.Sprintf("%s", col["Field"])
+			break
+		}
+	}
+
+	autoIncrementColumns.Store(key, column)
+	return column, nil
+}
+
+// toInt64 convert the scanned integer of the drivers to int64.
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case []byte:
+		id, _ := strconv.ParseInt(string(v), 10, 64)
+		return id
+	case string:
+		id, _ := strconv.ParseInt(v, 10, 64)
+		return id
+	}
+	return 0
+}
+
+// query run the statement within the transaction or on the connection.
+func (sql *SQL) query() ([]map[string]interface{}, error) {
+	if sql.tx != nil {
+		return sql.diver.QueryWithTxAndContext(sql.ctx, sql.tx, sql.Statement, sql.Args...)
+	}
+	return sql.diver.QueryWithConnectionAndContext(sql.ctx, sql.conn, sql.Statement, sql.Args...)
+}
+
+// exec run the statement within the transaction or on the connection.
+func (sql *SQL) exec() (dbsql.Result, error) {
+	if sql.tx != nil {
+		return sql.diver.ExecWithTxAndContext(sql.ctx, sql.tx, sql.Statement, sql.Args...)
+	}
+	return sql.diver.ExecWithConnectionAndContext(sql.ctx, sql.conn, sql.Statement, sql.Args...)
+}
+
 func (sql *SQL) wrap(field string) string {
 	if sql.diver.Name() == "mssql" {
 		return fmt.Sprintf(`[%s]`, field)
@@ -631,6 +845,7 @@ func (sql *SQL) clean() {
 	sql.WhereRaws = ""
 	sql.UpdateRaws = make([]dialect.RawUpdate, 0)
 	sql.Statement = ""
+	sql.Returning = nil
 }
 
 // RecycleSQL clear the SQL and put into the pool.
@@ -643,6 +858,7 @@ func RecycleSQL(sql *SQL) {
 	sql.conn = ""
 	sql.diver = nil
 	sql.tx = nil
+	sql.ctx = context.Background()
 	sql.dialect = nil
 
 	SQLPool.Put(sql)
@@ -48,3 +48,7 @@ func TestMssqlSQL_Delete(t *testing.T)      { testSQLDelete(t, driverTestMssqlCo
 func TestMssqlSQL_Exec(t *testing.T)        { testSQLExec(t, driverTestMssqlConn) }
 func TestMssqlSQL_Insert(t *testing.T)      { testSQLInsert(t, driverTestMssqlConn) }
 func TestMssqlSQL_Wrap(t *testing.T)        { testSQLWrap(t, driverTestMssqlConn) }
+func TestMssqlSQL_InsertReturning(t *testing.T) {
+	testSQLInsertReturning(t, driverTestMssqlConn,
+		`create table insert_returning (id int identity(1,1) primary key, name varchar(50), code varchar(50) default 'abc')`)
+}
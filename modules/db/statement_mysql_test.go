@@ -60,3 +60,7 @@ func TestMysqlSQL_Delete(t *testing.T)      { testSQLDelete(t, driverTestMysqlCo
 func TestMysqlSQL_Exec(t *testing.T)        { testSQLExec(t, driverTestMysqlConn) }
 func TestMysqlSQL_Insert(t *testing.T)      { testSQLInsert(t, driverTestMysqlConn) }
 func TestMysqlSQL_Wrap(t *testing.T)        { testSQLWrap(t, driverTestMysqlConn) }
+func TestMysqlSQL_InsertReturning(t *testing.T) {
+	testSQLInsertReturning(t, driverTestMysqlConn,
+		`create table insert_returning (id int auto_increment primary key, name varchar(50), code varchar(50) default 'abc')`)
+}
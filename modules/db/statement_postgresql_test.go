@@ -61,3 +61,7 @@ func TestPgSQL_Delete(t *testing.T)      { testSQLDelete(t, driverTestPgConn) }
 func TestPgSQL_Exec(t *testing.T)        { testSQLExec(t, driverTestPgConn) }
 func TestPgSQL_Insert(t *testing.T)      { testSQLInsert(t, driverTestPgConn) }
 func TestPgSQL_Wrap(t *testing.T)        { testSQLWrap(t, driverTestPgConn) }
+func TestPgSQL_InsertReturning(t *testing.T) {
+	testSQLInsertReturning(t, driverTestPgConn,
+		`create table insert_returning (id serial primary key, name varchar(50), code varchar(50) default 'abc')`)
+}
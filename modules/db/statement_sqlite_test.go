@@ -1,8 +1,15 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
 	_ "github.com/GoAdminGroup/go-admin/modules/db/drivers/sqlite"
+	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -42,3 +49,53 @@ func TestSQLiteSQL_Delete(t *testing.T)      { testSQLDelete(t, driverTestSQLite
 func TestSQLiteSQL_Exec(t *testing.T)        { testSQLExec(t, driverTestSQLiteConn) }
 func TestSQLiteSQL_Insert(t *testing.T)      { testSQLInsert(t, driverTestSQLiteConn) }
 func TestSQLiteSQL_Wrap(t *testing.T)        { testSQLWrap(t, driverTestSQLiteConn) }
+func TestSQLiteSQL_InsertReturning(t *testing.T) {
+	testSQLInsertReturning(t, driverTestSQLiteConn,
+		`create table insert_returning (id integer primary key autoincrement, name varchar(50), code varchar(50) default 'abc')`)
+}
+
+func TestSQLiteSQL_InsertReturningWithoutId(t *testing.T) {
+	_, _ = driverTestSQLiteConn.Exec("drop table if exists insert_without_id")
+	_, err := driverTestSQLiteConn.Exec(`create table insert_without_id (code varchar(50) primary key default 'abc', name varchar(50)) without rowid`)
+	assert.Equal(t, err, nil)
+	defer func() {
+		_, _ = driverTestSQLiteConn.Exec("drop table insert_without_id")
+	}()
+
+	// the keys which can not be found are nil rather than failing the insertion.
+	res, err := WithDriver(driverTestSQLiteConn).Table("insert_without_id").InsertReturning(dialect.H{"name": "jack"}, "code")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res["code"], nil)
+
+	count, err := WithDriver(driverTestSQLiteConn).Table("insert_without_id").Count()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, count, int64(1))
+}
+
+func TestSQLiteSQL_TxQueryTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	assert.Equal(t, err, nil)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	conn := testConn(DriverSqlite, config.Database{File: filepath.Join(dir, "timeout.db"), QueryTimeout: 1})
+	defer conn.Close()
+
+	slow := "with recursive c(x) as (select 1 union all select x + 1 from c where x < 1000000000) select count(*) from c"
+
+	_, err = conn.Query(slow)
+	assert.Equal(t, err, context.DeadlineExceeded)
+
+	_, err = WithDriver(conn).WithTransaction(func(tx *sql.Tx) (error, map[string]interface{}) {
+		_, err := conn.QueryWithTx(tx, slow)
+		return err, nil
+	})
+	assert.Equal(t, err, context.DeadlineExceeded)
+
+	// the earlier deadline of the given context is kept.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = WithDriver(conn).WithContext(ctx).Table("sqlite_master").All()
+	assert.Equal(t, err, context.Canceled)
+}
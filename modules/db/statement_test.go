@@ -2,6 +2,8 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
 	_ "github.com/GoAdminGroup/go-admin/modules/db/drivers/mssql"
 	_ "github.com/GoAdminGroup/go-admin/modules/db/drivers/postgres"
 	"github.com/magiconair/properties/assert"
@@ -100,5 +102,44 @@ func testSQLExec(t *testing.T, conn Connection) {}
 // TODO
 func testSQLInsert(t *testing.T, conn Connection) {}
 
+// testSQLInsertReturning insert into the table insert_returning of given
+// schema, whose id is an auto increment column and code has a default.
+func testSQLInsertReturning(t *testing.T, conn Connection, schema string) {
+	_, _ = conn.Exec("drop table if exists insert_returning")
+	_, err := conn.Exec(schema)
+	assert.Equal(t, err, nil)
+	defer func() {
+		_, _ = conn.Exec("drop table insert_returning")
+	}()
+
+	res, err := WithDriver(conn).Table("insert_returning").InsertReturning(dialect.H{"name": "jack"}, "id")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res["id"], int64(1))
+
+	// the keys other than the auto increment one are selected rather than
+	// taken as the last insert id.
+	res, err = WithDriver(conn).Table("insert_returning").InsertReturning(dialect.H{"name": "rose"}, "id", "code")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, toInt64(res["id"]), int64(2))
+	assert.Equal(t, fmt.Sprintf("%s", res["code"]), "abc")
+
+	res, err = WithDriver(conn).Table("insert_returning").InsertReturning(dialect.H{"name": "tom", "code": "def"}, "name", "code")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res["name"], "tom")
+	assert.Equal(t, res["code"], "def")
+
+	_, _ = WithDriver(conn).WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
+		res, err := WithDriver(conn).WithTx(tx).Table("insert_returning").InsertReturning(dialect.H{"name": "mike"}, "id", "code")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, toInt64(res["id"]), int64(4))
+		assert.Equal(t, fmt.Sprintf("%s", res["code"]), "abc")
+		return nil, nil
+	})
+
+	id, err := WithDriver(conn).Table("insert_returning").Insert(dialect.H{"name": "lucy"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, id, int64(5))
+}
+
 // TODO
 func testSQLWrap(t *testing.T, conn Connection) {}
@@ -47,6 +47,9 @@ var cn = map[string]string{
 	"search":            "搜索",
 
 	"permission denied": "没有权限",
+	"unauthorized":      "未登录",
+	"validation failed": "校验失败",
+	"required":          "必填",
 	"error":             "错误",
 	"success":           "成功",
 	"current page":      "当前页",
@@ -103,4 +106,94 @@ var cn = map[string]string{
 	"fixed the sidebar":                             "固定侧边栏",
 	"enter fullscreen":                              "进入全屏",
 	"exit fullscreen":                               "退出全屏",
+
+	"api tokens":   "API令牌",
+	"token":        "令牌",
+	"last used at": "最后使用时间",
+	"copy the token now, it can not be found again": "请立即复制令牌，之后将无法再查看",
+
+	"table":         "表",
+	"primary key":   "主键",
+	"changes":       "变更",
+	"insert":        "新增",
+	"update":        "更新",
+	"inline update": "行内更新",
+	"field":         "字段",
+	"before":        "变更前",
+	"after":         "变更后",
+
+	"wrong export format": "错误的导出格式",
+
+	"import": "导入",
+	"file":   "文件",
+	"csv or xlsx file, the first line is the head: ": "csv或xlsx文件，第一行为表头：",
+	"%d of %d rows will be imported":                 "共%[2]d行，将导入%[1]d行",
+	"%d of %d rows have been imported":               "共%[2]d行，已导入%[1]d行",
+	"line":                                           "行号",
+	"errors":                                         "错误",
+	"preview":                                        "预览",
+	"ignored columns":                                "忽略的列",
+	"import fail, wrong token":                       "导入失败，令牌错误",
+	"wrong import file":                              "错误的导入文件",
+	"wrong import file type":                         "导入文件类型错误，仅支持csv和xlsx",
+	"empty import file":                              "导入文件为空",
+
+	"two-factor authentication":                                                                     "两步验证",
+	"two-factor authentication required":                                                            "要求两步验证",
+	"the users of the role have to login with the two-factor authentication":                        "该角色的用户必须通过两步验证登录",
+	"scan the qr code with the authenticator app, or enter the secret: ":                            "使用身份验证器应用扫描二维码，或输入密钥：",
+	"enter the code of the authenticator app or a recovery code":                                    "输入身份验证器应用中的验证码或恢复码",
+	"save the recovery codes, each of them can be used once to login without the authenticator app": "请保存恢复码，每个恢复码可在没有身份验证器应用时登录一次",
+	"two-factor authentication is enabled, %d recovery codes left":                                  "两步验证已启用，剩余%d个恢复码",
+	"two-factor authentication is required by your role":                                            "您的角色要求启用两步验证",
+	"two-factor authentication has been reset":                                                      "两步验证已重置",
+	"reset two-factor authentication":                                                               "重置两步验证",
+	"enable two-factor authentication fail":                                                         "启用两步验证失败",
+	"disable two-factor authentication fail":                                                        "停用两步验证失败",
+	"login expired, please login again":                                                             "登录已过期，请重新登录",
+	"too many attempts, please login again":                                                         "尝试次数过多，请重新登录",
+	"recovery codes":                                                                                "恢复码",
+	"wrong code":                                                                                    "验证码错误",
+	"wrong token":                                                                                   "令牌错误",
+	"code":                                                                                          "验证码",
+	"verify":                                                                                        "验证",
+	"continue":                                                                                      "继续",
+	"enable":                                                                                        "启用",
+	"disable":                                                                                       "停用",
+	"enabled":                                                                                       "已启用",
+	"disabled":                                                                                      "未启用",
+	"status":                                                                                        "状态",
+	"normal":                                                                                        "正常",
+	"locked":                                                                                        "已锁定",
+	"unlock":                                                                                        "解锁",
+	"user not found":                                                                                "用户不存在",
+	"user has been unlocked":                                                                        "用户已解锁",
+	"too many login attempts, please try again later":                                               "登录尝试次数过多，请稍后再试",
+	"account locked, please try again later":                                                        "账号已锁定，请稍后再试",
+	"login failure":                                                                                 "登录失败",
+	"wrong captcha":                                                                                 "验证码错误",
+
+	"move up":     "上移",
+	"move down":   "下移",
+	"is required": "不能为空",
+
+	"sum":      "合计",
+	"avg":      "平均",
+	"min":      "最小",
+	"max":      "最大",
+	"count":    "计数",
+	"group by": "分组",
+	"no group": "不分组",
+
+	"export format": "导出格式",
+
+	"saved views":           "已保存视图",
+	"save current view":     "保存当前视图",
+	"share with":            "共享给",
+	"only me":               "仅自己",
+	"name can not be empty": "名称不能为空",
+	"save view fail":        "保存视图失败",
+	"delete view fail":      "删除视图失败",
+	"view not found":        "视图不存在",
+	"no results":            "没有结果",
 }
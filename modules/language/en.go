@@ -81,4 +81,92 @@ var en = map[string]string{
 	"menu":      "Menu",
 	"dashboard": "Dashboard",
 	"home":      "Home",
+
+	"api tokens":   "API Tokens",
+	"token":        "Token",
+	"last used at": "Last used at",
+	"copy the token now, it can not be found again": "Copy the token now, it can not be found again",
+
+	"table":         "Table",
+	"primary key":   "Primary key",
+	"changes":       "Changes",
+	"insert":        "Insert",
+	"update":        "Update",
+	"inline update": "Inline update",
+	"field":         "Field",
+	"before":        "Before",
+	"after":         "After",
+
+	"import": "Import",
+	"file":   "File",
+	"csv or xlsx file, the first line is the head: ": "CSV or XLSX file, the first line is the head: ",
+	"%d of %d rows will be imported":                 "%d of %d rows will be imported",
+	"%d of %d rows have been imported":               "%d of %d rows have been imported",
+	"line":                                           "Line",
+	"errors":                                         "Errors",
+	"preview":                                        "Preview",
+	"ignored columns":                                "Ignored columns",
+	"import fail, wrong token":                       "Import fail, wrong token",
+	"wrong import file":                              "Wrong import file",
+	"wrong import file type":                         "Wrong import file type, only csv and xlsx are supported",
+	"empty import file":                              "Empty import file",
+
+	"two-factor authentication":                                                                     "Two-factor authentication",
+	"two-factor authentication required":                                                            "Two-factor authentication required",
+	"the users of the role have to login with the two-factor authentication":                        "The users of the role have to login with the two-factor authentication",
+	"scan the qr code with the authenticator app, or enter the secret: ":                            "Scan the QR code with the authenticator app, or enter the secret: ",
+	"enter the code of the authenticator app or a recovery code":                                    "Enter the code of the authenticator app or a recovery code",
+	"save the recovery codes, each of them can be used once to login without the authenticator app": "Save the recovery codes, each of them can be used once to login without the authenticator app",
+	"two-factor authentication is enabled, %d recovery codes left":                                  "Two-factor authentication is enabled, %d recovery codes left",
+	"two-factor authentication is required by your role":                                            "Two-factor authentication is required by your role",
+	"two-factor authentication has been reset":                                                      "Two-factor authentication has been reset",
+	"reset two-factor authentication":                                                               "Reset two-factor authentication",
+	"enable two-factor authentication fail":                                                         "Enable two-factor authentication fail",
+	"disable two-factor authentication fail":                                                        "Disable two-factor authentication fail",
+	"login expired, please login again":                                                             "Login expired, please login again",
+	"too many attempts, please login again":                                                         "Too many attempts, please login again",
+	"recovery codes":                                                                                "Recovery codes",
+	"wrong code":                                                                                    "Wrong code",
+	"wrong token":                                                                                   "Wrong token",
+	"code":                                                                                          "Code",
+	"verify":                                                                                        "Verify",
+	"continue":                                                                                      "Continue",
+	"enable":                                                                                        "Enable",
+	"disable":                                                                                       "Disable",
+	"enabled":                                                                                       "Enabled",
+	"disabled":                                                                                      "Disabled",
+	"status":                                                                                        "Status",
+	"normal":                                                                                        "Normal",
+	"locked":                                                                                        "Locked",
+	"unlock":                                                                                        "Unlock",
+	"user not found":                                                                                "User not found",
+	"user has been unlocked":                                                                        "User has been unlocked",
+	"too many login attempts, please try again later":                                               "Too many login attempts, please try again later",
+	"account locked, please try again later":                                                        "Account locked, please try again later",
+	"login failure":                                                                                 "Login failure",
+	"wrong captcha":                                                                                 "Wrong captcha",
+
+	"move up":     "Move up",
+	"move down":   "Move down",
+	"is required": "is required",
+
+	"sum":      "Sum",
+	"avg":      "Average",
+	"min":      "Min",
+	"max":      "Max",
+	"count":    "Count",
+	"group by": "Group by",
+	"no group": "No group",
+
+	"export format": "Export format",
+
+	"saved views":           "Saved views",
+	"save current view":     "Save current view",
+	"share with":            "Share with",
+	"only me":               "Only me",
+	"name can not be empty": "Name can not be empty",
+	"save view fail":        "Save view fail",
+	"delete view fail":      "Delete view fail",
+	"view not found":        "View not found",
+	"no results":            "No results",
 }
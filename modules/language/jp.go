@@ -46,6 +46,9 @@ var jp = map[string]string{
 	"search":            "検索",
 
 	"permission denied": "権限がありません",
+	"unauthorized":      "ログインしていません",
+	"validation failed": "検証に失敗しました",
+	"required":          "必須",
 	"error":             "エラー",
 	"current page":      "現在のページ",
 
@@ -92,4 +95,94 @@ var jp = map[string]string{
 	"a path a line":                                 "パスを１行ずつ入力してください",
 	"slug or http_path or name should not be empty": "スラッグ、http_pathまたユーザー名が正しく入力されていることを確認してください",
 	"no roles":                                      "ロールなし",
+
+	"api tokens":   "APIトークン",
+	"token":        "トークン",
+	"last used at": "最終使用日時",
+	"copy the token now, it can not be found again": "今すぐトークンをコピーしてください。後で確認することはできません",
+
+	"table":         "テーブル",
+	"primary key":   "主キー",
+	"changes":       "変更",
+	"insert":        "新規作成",
+	"update":        "更新",
+	"inline update": "インライン更新",
+	"field":         "フィールド",
+	"before":        "変更前",
+	"after":         "変更後",
+
+	"wrong export format": "エクスポート形式が正しくありません",
+
+	"import": "インポート",
+	"file":   "ファイル",
+	"csv or xlsx file, the first line is the head: ": "csvまたはxlsxファイル、1行目はヘッダー：",
+	"%d of %d rows will be imported":                 "%[2]d行中%[1]d行をインポートします",
+	"%d of %d rows have been imported":               "%[2]d行中%[1]d行をインポートしました",
+	"line":                                           "行",
+	"errors":                                         "エラー",
+	"preview":                                        "プレビュー",
+	"ignored columns":                                "無視された列",
+	"import fail, wrong token":                       "インポートに失敗しました、トークンが正しくありません",
+	"wrong import file":                              "インポートファイルが正しくありません",
+	"wrong import file type":                         "ファイル形式が正しくありません、csvとxlsxのみ対応しています",
+	"empty import file":                              "インポートファイルが空です",
+
+	"two-factor authentication":                                                                     "二段階認証",
+	"two-factor authentication required":                                                            "二段階認証を必須にする",
+	"the users of the role have to login with the two-factor authentication":                        "このロールのユーザーは二段階認証でログインする必要があります",
+	"scan the qr code with the authenticator app, or enter the secret: ":                            "認証アプリでQRコードをスキャンするか、シークレットを入力してください：",
+	"enter the code of the authenticator app or a recovery code":                                    "認証アプリのコードまたはリカバリーコードを入力してください",
+	"save the recovery codes, each of them can be used once to login without the authenticator app": "リカバリーコードを保存してください。各コードは認証アプリなしで一度だけログインに使用できます",
+	"two-factor authentication is enabled, %d recovery codes left":                                  "二段階認証は有効です。リカバリーコードは残り%d個です",
+	"two-factor authentication is required by your role":                                            "あなたのロールでは二段階認証が必須です",
+	"two-factor authentication has been reset":                                                      "二段階認証はリセットされました",
+	"reset two-factor authentication":                                                               "二段階認証をリセット",
+	"enable two-factor authentication fail":                                                         "二段階認証の有効化に失敗しました",
+	"disable two-factor authentication fail":                                                        "二段階認証の無効化に失敗しました",
+	"login expired, please login again":                                                             "ログインの有効期限が切れました。再度ログインしてください",
+	"too many attempts, please login again":                                                         "試行回数が多すぎます。再度ログインしてください",
+	"recovery codes":                                                                                "リカバリーコード",
+	"wrong code":                                                                                    "コードが間違っています",
+	"wrong token":                                                                                   "トークンが間違っています",
+	"code":                                                                                          "コード",
+	"verify":                                                                                        "確認",
+	"continue":                                                                                      "続ける",
+	"enable":                                                                                        "有効にする",
+	"disable":                                                                                       "無効にする",
+	"enabled":                                                                                       "有効",
+	"disabled":                                                                                      "無効",
+	"status":                                                                                        "状態",
+	"normal":                                                                                        "正常",
+	"locked":                                                                                        "ロック中",
+	"unlock":                                                                                        "ロック解除",
+	"user not found":                                                                                "ユーザーが存在しません",
+	"user has been unlocked":                                                                        "ユーザーのロックを解除しました",
+	"too many login attempts, please try again later":                                               "ログイン試行回数が多すぎます。しばらくしてから再試行してください",
+	"account locked, please try again later":                                                        "アカウントはロックされています。しばらくしてから再試行してください",
+	"login failure":                                                                                 "ログイン失敗",
+	"wrong captcha":                                                                                 "キャプチャが間違っています",
+
+	"move up":     "上に移動",
+	"move down":   "下に移動",
+	"is required": "は必須です",
+
+	"sum":      "合計",
+	"avg":      "平均",
+	"min":      "最小",
+	"max":      "最大",
+	"count":    "件数",
+	"group by": "グループ化",
+	"no group": "グループなし",
+
+	"export format": "エクスポート形式",
+
+	"saved views":           "保存済みビュー",
+	"save current view":     "現在のビューを保存",
+	"share with":            "共有先",
+	"only me":               "自分のみ",
+	"name can not be empty": "名前は必須です",
+	"save view fail":        "ビューの保存に失敗しました",
+	"delete view fail":      "ビューの削除に失敗しました",
+	"view not found":        "ビューが見つかりません",
+	"no results":            "結果がありません",
 }
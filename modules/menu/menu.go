@@ -5,6 +5,7 @@
 package menu
 
 import (
+	"github.com/GoAdminGroup/go-admin/modules/cache"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
@@ -41,55 +42,69 @@ func (menu *Menu) AddMaxOrder() {
 	menu.MaxOrder++
 }
 
-// SetActiveClass set the active class of menu.
+// SetActiveClass set the active class of the menu item of given path and
+// all of its ancestors, at any depth.
 func (menu *Menu) SetActiveClass(path string) *Menu {
 
 	reg, _ := regexp.Compile(`\?(.*)`)
 	path = reg.ReplaceAllString(path, "")
 
-	for i := 0; i < len(menu.List); i++ {
-		menu.List[i].Active = ""
+	resetActive(menu.List)
+	setActive(menu.List, path)
+
+	return menu
+}
+
+func resetActive(list []Item) {
+	for i := 0; i < len(list); i++ {
+		list[i].Active = ""
+		resetActive(list[i].ChildrenList)
 	}
+}
 
-	for i := 0; i < len(menu.List); i++ {
-		if menu.List[i].Url == path && len(menu.List[i].ChildrenList) == 0 {
-			menu.List[i].Active = "active"
-			return menu
+// setActive mark the first leaf item of given path and its ancestors in
+// the list, and report whether it is found.
+func setActive(list []Item, path string) bool {
+	for i := 0; i < len(list); i++ {
+		if (len(list[i].ChildrenList) == 0 && list[i].Url == path) ||
+			setActive(list[i].ChildrenList, path) {
+			list[i].Active = "active"
+			return true
 		}
+	}
+	return false
+}
 
-		for j := 0; j < len(menu.List[i].ChildrenList); j++ {
-			if menu.List[i].ChildrenList[j].Url == path {
-				menu.List[i].Active = "active"
-				menu.List[i].ChildrenList[j].Active = "active"
-				return menu
-			}
-
-			menu.List[i].Active = ""
-			menu.List[i].ChildrenList[j].Active = ""
+// ActivePath return the active item and its ancestors, from the top one.
+func (menu Menu) ActivePath() []Item {
+	path := make([]Item, 0)
+	for list := menu.List; ; {
+		item, ok := activeItem(list)
+		if !ok {
+			return path
 		}
+		path = append(path, item)
+		list = item.ChildrenList
 	}
+}
 
-	return menu
+func activeItem(list []Item) (Item, bool) {
+	for _, item := range list {
+		if item.Active != "" {
+			return item, true
+		}
+	}
+	return Item{}, false
 }
 
 // FormatPath get template.HTML for front-end.
 func (menu Menu) FormatPath() template.HTML {
 	res := template.HTML(``)
-	for i := 0; i < len(menu.List); i++ {
-		if menu.List[i].Active != "" {
-			if menu.List[i].Url != "#" && menu.List[i].Url != "" && len(menu.List[i].ChildrenList) > 0 {
-				res += template.HTML(`<li><a href="` + menu.List[i].Url + `">` + menu.List[i].Name + `</a></li>`)
-			} else {
-				res += template.HTML(`<li>` + menu.List[i].Name + `</li>`)
-				if len(menu.List[i].ChildrenList) == 0 {
-					return res
-				}
-			}
-			for j := 0; j < len(menu.List[i].ChildrenList); j++ {
-				if menu.List[i].ChildrenList[j].Active != "" {
-					return res + template.HTML(`<li>`+menu.List[i].ChildrenList[j].Name+`</li>`)
-				}
-			}
+	for _, item := range menu.ActivePath() {
+		if item.Url != "#" && item.Url != "" && len(item.ChildrenList) > 0 {
+			res += template.HTML(`<li><a href="` + item.Url + `">` + item.Name + `</a></li>`)
+		} else {
+			res += template.HTML(`<li>` + item.Name + `</li>`)
 		}
 	}
 	return res
@@ -108,24 +123,17 @@ func GetGlobalMenu(user models.UserModel, conn db.Connection) *Menu {
 		menuOption = make([]map[string]string, 0)
 	)
 
-	user.WithRoles().WithMenus()
-
 	if user.IsSuperAdmin() {
-		menus, _ = db.WithDriver(conn).Table("goadmin_menu").
-			Where("id", ">", 0).
-			OrderBy("order", "asc").
-			All()
+		menus = allMenus(conn)
 	} else {
-
-		var ids []interface{}
-		for i := 0; i < len(user.MenuIds); i++ {
-			ids = append(ids, user.MenuIds[i])
+		for _, item := range allMenus(conn) {
+			for i := 0; i < len(user.MenuIds); i++ {
+				if item["id"] == user.MenuIds[i] {
+					menus = append(menus, item)
+					break
+				}
+			}
 		}
-
-		menus, _ = db.WithDriver(conn).Table("goadmin_menu").
-			WhereIn("id", ids).
-			OrderBy("order", "asc").
-			All()
 	}
 
 	var title string
@@ -152,6 +160,32 @@ func GetGlobalMenu(user models.UserModel, conn db.Connection) *Menu {
 	}
 }
 
+// cacheKey is the key of the menu items in the cache.
+const cacheKey = "menu"
+
+// allMenus return all the menu items in order, which are cached until
+// they expire or the menu table is written. The items are shared by the
+// callers, which must not change them.
+func allMenus(conn db.Connection) []map[string]interface{} {
+
+	if menus, ok := cache.Default().Get(cacheKey); ok {
+		return menus.([]map[string]interface{})
+	}
+
+	generation := cache.Default().Generation("goadmin_menu")
+
+	menus, err := db.WithDriver(conn).Table("goadmin_menu").
+		Where("id", ">", 0).
+		OrderBy("order", "asc").
+		All()
+
+	if err == nil {
+		cache.Default().SetWithGeneration(cacheKey, menus, generation, "goadmin_menu")
+	}
+
+	return menus
+}
+
 func constructMenuTree(menus []map[string]interface{}, parentID int64) []Item {
 
 	branch := make([]Item, 0)
@@ -79,3 +79,35 @@ func TestMenu_SetActiveClass(t *testing.T) {
 	assert.Equal(t, menus.List[3].ChildrenList[0].Active, "active")
 	assert.Equal(t, menus.List[3].ChildrenList[1].Active, "")
 }
+
+func TestMenu_SetActiveClassNested(t *testing.T) {
+	menus := Menu{
+		List: []Item{
+			{Name: "item1", ID: "1", Url: "/item1"},
+			{Name: "item2", ID: "2", Url: "/item2", ChildrenList: []Item{
+				{Name: "item3", ID: "3", Url: "#", ChildrenList: []Item{
+					{Name: "item4", ID: "4", Url: "/item4"},
+					{Name: "item5", ID: "5", Url: "/item5"},
+				}},
+			}},
+		},
+	}
+
+	menus.SetActiveClass("/item5?page=1")
+
+	assert.Equal(t, menus.List[0].Active, "")
+	assert.Equal(t, menus.List[1].Active, "active")
+	assert.Equal(t, menus.List[1].ChildrenList[0].Active, "active")
+	assert.Equal(t, menus.List[1].ChildrenList[0].ChildrenList[0].Active, "")
+	assert.Equal(t, menus.List[1].ChildrenList[0].ChildrenList[1].Active, "active")
+	assert.Equal(t, len(menus.ActivePath()), 3)
+	assert.Equal(t, string(menus.FormatPath()),
+		`<li><a href="/item2">item2</a></li><li>item3</li><li>item5</li>`)
+
+	menus.SetActiveClass("/item1")
+
+	assert.Equal(t, menus.List[0].Active, "active")
+	assert.Equal(t, menus.List[1].Active, "")
+	assert.Equal(t, menus.List[1].ChildrenList[0].ChildrenList[1].Active, "")
+	assert.Equal(t, string(menus.FormatPath()), `<li>item1</li>`)
+}
@@ -39,6 +39,7 @@ func (admin *Admin) InitPlugin(services service.List) {
 		"op":             st.GetOpTable,
 		"menu":           st.GetMenuTable,
 		"normal_manager": st.GetNormalManagerTable,
+		"user_tokens":    st.GetUserTokenTable,
 	})
 	admin.guardian = guard.New(admin.services, admin.conn, admin.tableList)
 	admin.handler = controller.New(controller.Config{
@@ -49,7 +50,8 @@ func (admin *Admin) InitPlugin(services service.List) {
 	})
 	admin.initRouter(cfg.Prefix())
 	admin.handler.SetRoutes(admin.app.Routers)
-	admin.tableList.InjectRoutes(admin.app, admin.services)
+	types.AddNavbarItem("search", admin.handler.SearchBox())
+	admin.tableList.InjectRoutes(admin.app, admin.services, admin.guardian.CheckToken)
 
 	table.SetServices(services)
 }
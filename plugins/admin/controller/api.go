@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/file"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+// ApiList return the rows of the table with the filters, sort and pagination
+// of the url parameters, the same as the info page.
+func (h *Handler) ApiList(ctx *context.Context) {
+
+	param := guard.GetApiListParam(ctx)
+
+	panelInfo, err := param.Panel.GetDataWithContext(ctx.Request.Context(), param.Param)
+
+	if err != nil {
+		logger.Error(err)
+		response.Error(ctx, err.Error())
+		return
+	}
+
+	list := make([]map[string]string, len(panelInfo.InfoList))
+	for i, row := range panelInfo.InfoList {
+		list[i] = make(map[string]string, len(row))
+		for key, item := range row {
+			list[i][key] = item.Value
+		}
+	}
+
+	response.OkWithData(ctx, map[string]interface{}{
+		"list":      list,
+		"total":     panelInfo.Total,
+		"page":      param.Param.PageInt,
+		"page_size": param.Param.PageSizeInt,
+		"next":      panelInfo.NextCursor,
+		"previous":  panelInfo.PreviousCursor,
+	})
+}
+
+// ApiDetail return the row of given primary key.
+func (h *Handler) ApiDetail(ctx *context.Context) {
+
+	param := guard.GetApiDetailParam(ctx)
+
+	formInfo, err := param.Panel.GetDataWithIdWithContext(ctx.Request.Context(), param.Param)
+
+	if err == table.ErrOutOfScope {
+		response.Forbidden(ctx, err.Error())
+		return
+	}
+
+	if err != nil {
+		logger.Error(err)
+		response.Error(ctx, err.Error())
+		return
+	}
+
+	fieldList := formInfo.FieldList
+	if len(formInfo.GroupFieldList) > 0 {
+		fieldList = make(types.FormFields, 0)
+		for _, fields := range formInfo.GroupFieldList {
+			fieldList = append(fieldList, fields...)
+		}
+	}
+
+	row := make(map[string]string, len(fieldList))
+	for _, field := range fieldList {
+		row[field.Field] = string(field.Value)
+	}
+
+	response.OkWithData(ctx, map[string]interface{}{
+		"row": row,
+	})
+}
+
+// ApiCreate insert a table row and return the primary key of it.
+func (h *Handler) ApiCreate(ctx *context.Context) {
+
+	param := guard.GetApiCreateParam(ctx)
+
+	if !h.apiUpload(ctx, param) {
+		return
+	}
+
+	if err := param.Panel.InsertData(param.Values); err != nil {
+		apiError(ctx, err)
+		return
+	}
+
+	pk := param.Panel.GetPrimaryKey().Name
+
+	response.OkWithData(ctx, map[string]interface{}{
+		pk: param.Values.Get(pk),
+	})
+}
+
+// ApiUpdate update the table row of given primary key.
+func (h *Handler) ApiUpdate(ctx *context.Context) {
+
+	param := guard.GetApiUpdateParam(ctx)
+
+	if !h.apiUpload(ctx, param) {
+		return
+	}
+
+	if err := param.Panel.UpdateData(param.Values); err != nil {
+		apiError(ctx, err)
+		return
+	}
+
+	response.Ok(ctx)
+}
+
+// ApiDelete delete the table rows of given primary keys.
+func (h *Handler) ApiDelete(ctx *context.Context) {
+
+	param := guard.GetApiDeleteParam(ctx)
+
+	if err := param.Panel.DeleteData(param.Id); err == table.ErrOutOfScope {
+		response.Forbidden(ctx, err.Error())
+		return
+	} else if err != nil {
+		logger.Error(err)
+		response.Error(ctx, err.Error())
+		return
+	}
+
+	response.Ok(ctx)
+}
+
+// apiUpload process the uploading files of the multipart form, it returns
+// false when failing and the error has been responded.
+func (h *Handler) apiUpload(ctx *context.Context, param *guard.ApiFormParam) bool {
+	if !param.HasFile() {
+		return true
+	}
+	if err := file.GetFileEngine(h.config.FileUploadEngine.Name).Upload(param.MultiForm); err != nil {
+		response.Error(ctx, err.Error())
+		return false
+	}
+	return true
+}
+
+func apiError(ctx *context.Context, err error) {
+	switch e := err.(type) {
+	case form.FieldErrors:
+		response.ValidationError(ctx, e)
+	case form.FieldError:
+		response.ValidationError(ctx, form.FieldErrors{e})
+	default:
+		if err == table.ErrOutOfScope {
+			response.Forbidden(ctx, err.Error())
+			return
+		}
+		response.BadRequest(ctx, err.Error())
+	}
+}
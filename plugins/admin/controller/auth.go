@@ -14,21 +14,52 @@ import (
 	"github.com/GoAdminGroup/go-admin/template/types"
 	template2 "html/template"
 	"net/http"
+	"time"
 )
 
 // Auth check the input password and username for authentication.
+// The failed logins are limited by the client ip and the username, and
+// a user is locked after too many failures in a row.
 func (h *Handler) Auth(ctx *context.Context) {
 
-	s, exist := h.services.GetOrNot(auth.ServiceKey)
-
 	var (
-		user models.UserModel
-		ok   bool
+		user     models.UserModel
+		ok       bool
+		ip       = auth.ClientIP(ctx)
+		username = ctx.FormValue("username")
+		limiter  = auth.GetLoginLimiter()
 	)
 
+	// the refused logins are not recorded, so that they can not write the
+	// operation logs once the limit is reached.
+	if !limiter.Allow(ip, username) {
+		response.BadRequest(ctx, "too many login attempts, please try again later")
+		return
+	}
+
+	if cd, ok := captcha.Get(h.captchaConfig["driver"]); ok && !cd.Validate(ctx.FormValue("token")) {
+		limiter.Fail(ip, username)
+		h.recordLoginFailure(ctx, 0, username, "wrong captcha")
+		response.BadRequest(ctx, "wrong captcha")
+		return
+	}
+
+	// the lockout is checked before the password, so that whether the
+	// password of a locked user is right can not be found out.
+	var target models.UserModel
+	if username != "" {
+		target = models.User().SetConn(h.conn).FindByUserName(username)
+	}
+	if !target.IsEmpty() && models.UserLockout().SetConn(h.conn).FindByUserId(target.Id).IsLocked() {
+		h.recordLoginFailure(ctx, target.Id, username, "account locked")
+		response.BadRequest(ctx, "account locked, please try again later")
+		return
+	}
+
+	s, exist := h.services.GetOrNot(auth.ServiceKey)
+
 	if !exist {
 		password := ctx.FormValue("password")
-		username := ctx.FormValue("username")
 
 		if password == "" || username == "" {
 			response.BadRequest(ctx, "wrong password or username")
@@ -41,14 +72,19 @@ func (h *Handler) Auth(ctx *context.Context) {
 
 	if ok {
 
-		cd, ok := captcha.Get(h.captchaConfig["driver"])
-
-		if ok {
-			if !cd.Validate(ctx.FormValue("token")) {
-				response.BadRequest(ctx, "wrong captcha")
-			}
+		// the failures are cleared after the second step if it is needed,
+		// so that they are counted for both steps.
+		if h.needTwoFactor(user) {
+			h.startTwoFactorLogin(ctx, user)
+			response.OkWithData(ctx, map[string]interface{}{
+				"url": h.config.Url("/login/two_factor"),
+			})
+			return
 		}
 
+		limiter.Reset(username)
+		h.unlockUser(user.Id)
+
 		auth.SetCookie(ctx, user, h.conn)
 
 		response.OkWithData(ctx, map[string]interface{}{
@@ -56,11 +92,43 @@ func (h *Handler) Auth(ctx *context.Context) {
 		})
 		return
 	}
+
+	limiter.Fail(ip, username)
+	if user.IsEmpty() {
+		user = target
+	}
+	if !user.IsEmpty() {
+		h.countLockout(user.Id)
+	}
+	h.recordLoginFailure(ctx, user.Id, username, "wrong password or username")
+
 	response.BadRequest(ctx, "fail")
 }
 
+// countLockout count a failed login of the user for the lockout.
+func (h *Handler) countLockout(userId int64) {
+	limit := h.config.LoginLimit
+	if limit.LockoutFailures <= 0 {
+		return
+	}
+	lockout := models.UserLockout().SetConn(h.conn).FindByUserId(userId)
+	_, err := lockout.Fail(userId, limit.LockoutFailures, time.Duration(limit.LockoutDuration)*time.Second)
+	if err != nil {
+		logger.Error("count the lockout failures error: ", err)
+	}
+}
+
+// unlockUser clear the failures of the user after a successful login.
+func (h *Handler) unlockUser(userId int64) {
+	err := models.UserLockout().SetConn(h.conn).DeleteByUserId(userId)
+	if err != nil && err.Error() != "no affect row" {
+		logger.Error("unlock the user error: ", err)
+	}
+}
+
 // Logout delete the cookie.
 func (h *Handler) Logout(ctx *context.Context) {
+	h.authSrv().DeleteTokens(ctx)
 	auth.DelCookie(ctx, db.GetConnection(h.services))
 	ctx.AddHeader("Location", h.config.Url("/login"))
 	ctx.SetStatusCode(302)
@@ -76,11 +144,13 @@ func (h *Handler) ShowLogin(ctx *context.Context) {
 		Title     string
 		Logo      template2.HTML
 		CdnUrl    string
+		Token     string
 		System    types.SystemInfo
 	}{
 		UrlPrefix: h.config.AssertPrefix(),
 		Title:     h.config.LoginTitle,
 		Logo:      h.config.LoginLogo,
+		Token:     h.authSrv().AddAnonymousToken(ctx),
 		System: types.SystemInfo{
 			Version: system.Version(),
 		},
@@ -7,6 +7,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
@@ -52,7 +53,11 @@ func (h *Handler) SetRoutes(r context.RouterMap) {
 }
 
 func (h *Handler) table(prefix string, ctx *context.Context) table.Table {
-	return h.generators[prefix](ctx)
+	t := h.generators[prefix](ctx)
+	if user, ok := ctx.User().(models.UserModel); ok {
+		t.SetUser(user)
+	}
+	return t
 }
 
 func (h *Handler) route(name string) context.Router {
@@ -182,6 +187,34 @@ func formFooter(page string) template2.HTML {
 		}
 	});
 </script>
+`)
+	} else if page == "new_edit" {
+		checkBoxs = template.HTML(`
+			<label class="pull-right" style="margin: 5px 10px 0 0;">
+                <input type="checkbox" class="continue_edit" style="position: absolute; opacity: 0;"> ` + language.Get("continue editing") + `
+            </label>
+			<label class="pull-right" style="margin: 5px 10px 0 0;">
+                <input type="checkbox" class="continue_new" style="position: absolute; opacity: 0;"> ` + language.Get("continue creating") + `
+            </label>`)
+		checkBoxJS = template.HTML(`<script>	
+	let previous_url_goadmin = $('input[name="` + form.PreviousKey + `"]').attr("value")
+	$('.continue_edit').iCheck({checkboxClass: 'icheckbox_minimal-blue'}).on('ifChanged', function (event) {
+		if (this.checked) {
+			$('.continue_new').iCheck('uncheck');
+			$('input[name="` + form.PreviousKey + `"]').val(location.href.replace('/new', '/edit'))
+		} else {
+			$('input[name="` + form.PreviousKey + `"]').val(previous_url_goadmin)
+		}
+	});	
+	$('.continue_new').iCheck({checkboxClass: 'icheckbox_minimal-blue'}).on('ifChanged', function (event) {
+		if (this.checked) {
+			$('.continue_edit').iCheck('uncheck');
+			$('input[name="` + form.PreviousKey + `"]').val(location.href)
+		} else {
+			$('input[name="` + form.PreviousKey + `"]').val(previous_url_goadmin)
+		}
+	});
+</script>
 `)
 	} else if page == "new" {
 		checkBoxs = template.HTML(`
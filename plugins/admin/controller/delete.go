@@ -12,21 +12,13 @@ func (h *Handler) Delete(ctx *context.Context) {
 
 	param := guard.GetDeleteParam(ctx)
 
-	//token := ctx.FormValue("_t")
-	//
-	//if !auth.TokenHelper.CheckToken(token) {
-	//	ctx.SetStatusCode(http.StatusBadRequest)
-	//	ctx.WriteString(`{"code":400, "msg":"delete fail"}`)
-	//	return
-	//}
-
-	if err := h.table(param.Prefix, ctx).DeleteData(param.Id); err != nil {
+	if err := param.Panel.DeleteData(param.Id); err != nil {
 		logger.Error(err)
 		response.Error(ctx, "删除失败")
 		return
 	}
 
-	newToken := h.authSrv().AddToken()
+	newToken := h.authSrv().AddToken(ctx)
 
 	response.OkWithData(ctx, map[string]interface{}{
 		"token": newToken,
@@ -117,7 +117,7 @@ $('.delete-btn').on('click', function (event) {
 		desc = panel.GetInfo().Description + language.Get("Detail")
 	}
 
-	formInfo, err := newPanel.GetDataWithId(param.WithPKs(id))
+	formInfo, err := newPanel.GetDataWithIdWithContext(ctx.Request.Context(), param.WithPKs(id))
 
 	var alert template2.HTML
 
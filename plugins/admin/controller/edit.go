@@ -39,7 +39,7 @@ func (h *Handler) showForm(ctx *context.Context, alert template2.HTML, prefix st
 		footerKind = "edit_only"
 	}
 
-	formInfo, err := panel.GetDataWithId(param)
+	formInfo, err := panel.GetDataWithIdWithContext(ctx.Request.Context(), param)
 
 	if err != nil && alert == "" {
 		alert = aAlert().SetTitle(constant.DefaultErrorMsg).
@@ -69,7 +69,7 @@ func (h *Handler) showForm(ctx *context.Context, alert template2.HTML, prefix st
 			SetPrimaryKey(panel.GetPrimaryKey().Name).
 			SetUrl(editUrl).
 			SetHiddenFields(map[string]string{
-				form2.TokenKey:    h.authSrv().AddToken(),
+				form2.TokenKey:    h.authSrv().AddToken(ctx),
 				form2.PreviousKey: infoUrl,
 			}).
 			SetOperationFooter(formFooter(footerKind)).
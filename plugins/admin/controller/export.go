@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// xlsxMaxRows is the max number of rows of a xlsx sheet, the rest rows
+// are written into the next sheets.
+const xlsxMaxRows = 1048576
+
+// Export export table rows as a file of the format chosen by the table.
+// All rows of the table are read in chunks, and the csv and json files
+// are streamed while reading.
+func (h *Handler) Export(ctx *context.Context) {
+	param := guard.GetExportParam(ctx)
+
+	var (
+		panel     = param.Panel
+		tableInfo = panel.GetInfo()
+		params    = parameter.GetParam(ctx.Request.URL, tableInfo.DefaultPageSize, tableInfo.SortField,
+			tableInfo.GetSort())
+		fileName string
+	)
+
+	if len(param.Id) == 0 {
+		fileName = fmt.Sprintf("%s-%d-page-%s-pageSize-%s.%s", tableInfo.Title, time.Now().Unix(),
+			params.Page, params.PageSize, param.Format)
+	} else {
+		fileName = fmt.Sprintf("%s-%d-id-%s.%s", tableInfo.Title, time.Now().Unix(),
+			strings.Join(param.Id, "_"), param.Format)
+	}
+
+	reader := &exportReader{
+		req:    ctx.Request,
+		panel:  panel,
+		params: params,
+		ids:    param.Id,
+		isAll:  param.IsAll,
+	}
+
+	first, err := reader.next()
+
+	if err != nil {
+		logger.Error(err)
+		response.Error(ctx, "export error")
+		return
+	}
+
+	columns := exportColumns(first.Thead)
+
+	var writeRows = func(fn func(values []string) error) error {
+		info := first
+		for {
+			for _, row := range info.InfoList {
+				values := make([]string, len(columns))
+				for i, column := range columns {
+					if tableInfo.IsExportValue() {
+						values[i] = row[column.field].Value
+					} else {
+						values[i] = string(row[column.field].Content)
+					}
+				}
+				if err := fn(values); err != nil {
+					return err
+				}
+			}
+			if reader.done {
+				return nil
+			}
+			var err error
+			if info, err = reader.next(); err != nil {
+				return err
+			}
+		}
+	}
+
+	table.ContextAuditor(ctx, param.Prefix)(table.AuditRecord{
+		PK: strings.Join(param.Id, ","),
+		Op: table.AuditExport,
+	})
+
+	ctx.AddHeader("content-disposition", `attachment; filename=`+fileName)
+
+	switch param.Format {
+	case types.ExportFormatCSV:
+		ctx.DataWithReader(http.StatusOK, "text/csv; charset=utf-8", streamExport(func(w io.Writer) error {
+			cw := csv.NewWriter(w)
+			if err := cw.Write(columns.heads()); err != nil {
+				return err
+			}
+			if err := writeRows(cw.Write); err != nil {
+				return err
+			}
+			cw.Flush()
+			return cw.Error()
+		}))
+	case types.ExportFormatJSON:
+		ctx.DataWithReader(http.StatusOK, "application/json", streamExport(func(w io.Writer) error {
+			if _, err := io.WriteString(w, "["); err != nil {
+				return err
+			}
+			count := 0
+			err := writeRows(func(values []string) error {
+				row := make(map[string]string, len(columns))
+				for i, column := range columns {
+					row[column.field] = values[i]
+				}
+				b, err := json.Marshal(row)
+				if err != nil {
+					return err
+				}
+				if count > 0 {
+					b = append([]byte(","), b...)
+				}
+				count++
+				_, err = w.Write(b)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(w, "]")
+			return err
+		}))
+	default:
+		f := excelize.NewFile()
+		sheet := "Sheet1"
+		f.SetActiveSheet(f.NewSheet(sheet))
+
+		var writeHeads = func() {
+			for i, head := range columns.heads() {
+				f.SetCellValue(sheet, excelize.ToAlphaString(i)+"1", head)
+			}
+		}
+
+		writeHeads()
+
+		count, sheetCount := 2, 1
+		err := writeRows(func(values []string) error {
+			if count > xlsxMaxRows {
+				sheetCount++
+				sheet = "Sheet" + strconv.Itoa(sheetCount)
+				f.NewSheet(sheet)
+				writeHeads()
+				count = 2
+			}
+			for i, value := range values {
+				f.SetCellValue(sheet, excelize.ToAlphaString(i)+strconv.Itoa(count), value)
+			}
+			count++
+			return nil
+		})
+
+		if err != nil {
+			logger.Error(err)
+			response.Error(ctx, "export error")
+			return
+		}
+
+		buf, err := f.WriteToBuffer()
+
+		if err != nil || buf == nil {
+			response.Error(ctx, "export error")
+			return
+		}
+
+		ctx.Data(http.StatusOK, "application/vnd.ms-excel", buf.Bytes())
+	}
+}
+
+type exportColumn struct {
+	field string
+	head  string
+}
+
+type exportColumnList []exportColumn
+
+func (c exportColumnList) heads() []string {
+	heads := make([]string, len(c))
+	for i, column := range c {
+		heads[i] = column.head
+	}
+	return heads
+}
+
+func exportColumns(thead types.Thead) exportColumnList {
+	columns := make(exportColumnList, 0)
+	for _, head := range thead {
+		if !head.Hide {
+			columns = append(columns, exportColumn{field: head.Field, head: head.Head})
+		}
+	}
+	return columns
+}
+
+// exportReader reads the rows to export. The rows of given ids or the rows
+// of the current page are read at once, and all rows of the table are
+// read page by page with the export chunk size of the table. The reading
+// is stopped when the request is cancelled.
+type exportReader struct {
+	req    *http.Request
+	panel  table.Table
+	params parameter.Parameters
+	ids    []string
+	isAll  bool
+	page   int
+	cursor string
+	done   bool
+}
+
+func (r *exportReader) next() (table.PanelInfo, error) {
+	if r.done {
+		return table.PanelInfo{}, nil
+	}
+
+	if len(r.ids) > 0 {
+		r.done = true
+		return r.panel.GetDataWithIdsWithContext(r.req.Context(), r.params.WithPKs(r.ids...))
+	}
+
+	if !r.isAll {
+		r.done = true
+		return r.panel.GetDataWithContext(r.req.Context(), r.params.WithIsAll(false))
+	}
+
+	size := r.panel.GetInfo().GetExportChunkSize()
+	r.page++
+
+	params := r.params.WithIsAll(false).WithPage(r.page, size)
+	if r.panel.GetInfo().IsKeysetPagination {
+		params = params.WithAfter(r.cursor)
+	}
+
+	info, err := r.panel.GetDataWithContext(r.req.Context(), params)
+	if err != nil || len(info.InfoList) < size {
+		r.done = true
+	}
+	r.cursor = info.NextCursor
+	if r.panel.GetInfo().IsKeysetPagination && r.cursor == "" {
+		r.done = true
+	}
+
+	return info, err
+}
+
+// streamExport return a reader of the content written by fn in a goroutine.
+func streamExport(fn func(w io.Writer) error) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				logger.Error(err)
+				_ = pw.CloseWithError(fmt.Errorf("%v", err))
+			}
+		}()
+		err := fn(pw)
+		if err != nil {
+			logger.Error(err)
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr
+}
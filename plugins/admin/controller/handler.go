@@ -117,7 +117,7 @@ func (h *Handler) setFormWithReturnErrMessage(ctx *context.Context, errMsg strin
 			SetPrimaryKey(panel.GetPrimaryKey().Name).
 			SetPrefix(h.config.PrefixFixSlash()).
 			SetHiddenFields(map[string]string{
-				form.TokenKey:    h.authSrv().AddToken(),
+				form.TokenKey:    h.authSrv().AddToken(ctx),
 				form.PreviousKey: h.config.Url("/info/" + prefix + queryParam),
 			}).
 			SetUrl(h.config.Url("/"+kind+"/"+prefix)).
@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/menu"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	template2 "html/template"
+	"net/http"
+	"strings"
+)
+
+// ShowImport show the page to upload the file to import.
+func (h *Handler) ShowImport(ctx *context.Context) {
+	param := guard.GetShowImportParam(ctx)
+	h.showImport(ctx, "", param.Panel, param.Prefix)
+}
+
+func (h *Handler) showImport(ctx *context.Context, alert template2.HTML, panel table.Table, prefix string) {
+
+	user := auth.Auth(ctx)
+
+	heads := make([]string, 0)
+	for _, field := range panel.GetForm().FieldList {
+		if !field.NotAllowAdd && !field.Hide {
+			heads = append(heads, field.Head)
+		}
+	}
+
+	fields := types.NewFormPanel().
+		AddField(language.Get("file"), form2.ImportFileKey, db.Varchar, form.File).
+		FieldMust().
+		FieldHelpMsg(template2.HTML(language.Get("csv or xlsx file, the first line is the head: ") +
+			template2.HTMLEscapeString(strings.Join(heads, ", ")))).
+		FieldList
+
+	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	buf := template.Execute(tmpl, tmplName, user, types.Panel{
+		Content: alert + formContent(aForm().
+			SetPrefix(h.config.PrefixFixSlash()).
+			SetContent(fields).
+			SetUrl(h.routePathWithPrefix("import_preview", prefix)).
+			SetPrimaryKey(panel.GetPrimaryKey().Name).
+			SetHiddenFields(map[string]string{
+				form2.TokenKey:    h.authSrv().AddToken(ctx),
+				form2.PreviousKey: h.routePathWithPrefix("info", prefix),
+			}).
+			SetTitle(language.GetFromHtml("import")).
+			SetOperationFooter(formFooter("import"))),
+		Description: panel.GetForm().Description,
+		Title:       panel.GetForm().Title,
+	}, h.config, menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.config.URLRemovePrefix(ctx.Path())), alert == "")
+	ctx.HTML(http.StatusOK, buf.String())
+}
+
+// ImportPreview show the rows of the uploaded file with the errors of
+// every row, and a form to confirm the import.
+func (h *Handler) ImportPreview(ctx *context.Context) {
+
+	param := guard.GetImportPreviewParam(ctx)
+
+	if param.HasAlert() {
+		h.showImport(ctx, param.Alert, param.Panel, param.Prefix)
+		ctx.AddHeader(constant.PjaxUrlHeader, h.routePathWithPrefix("show_import", param.Prefix))
+		return
+	}
+
+	user := auth.Auth(ctx)
+
+	validCount := 0
+	for _, row := range param.Rows {
+		if len(row.Errors) == 0 {
+			validCount++
+		}
+	}
+
+	content := importTable(param.Panel, param.Columns, param.Rows)
+
+	if validCount > 0 {
+		rows, _ := json.Marshal(param.Rows)
+		content += formContent(aForm().
+			SetPrefix(h.config.PrefixFixSlash()).
+			SetContent(types.FormFields{}).
+			SetUrl(h.routePathWithPrefix("import", param.Prefix)).
+			SetPrimaryKey(param.Panel.GetPrimaryKey().Name).
+			SetHiddenFields(map[string]string{
+				form2.TokenKey:      h.authSrv().AddToken(ctx),
+				form2.PreviousKey:   h.routePathWithPrefix("info", param.Prefix),
+				form2.ImportRowsKey: string(rows),
+			}).
+			SetTitle(template2.HTML(fmt.Sprintf(language.Get("%d of %d rows will be imported"),
+				validCount, len(param.Rows)))).
+			SetOperationFooter(formFooter("import")))
+	}
+
+	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	buf := template.Execute(tmpl, tmplName, user, types.Panel{
+		Content:     content,
+		Description: param.Panel.GetForm().Description,
+		Title:       param.Panel.GetForm().Title,
+	}, h.config, menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.config.URLRemovePrefix(ctx.Path())))
+	ctx.HTML(http.StatusOK, buf.String())
+}
+
+// Import insert the confirmed rows and show the report of the import.
+func (h *Handler) Import(ctx *context.Context) {
+
+	param := guard.GetImportParam(ctx)
+
+	report := param.Panel.ImportData(param.Rows)
+
+	user := auth.Auth(ctx)
+
+	theme := "success"
+	if len(report.Failed) > 0 {
+		theme = "warning"
+	}
+
+	content := aAlert().
+		SetTitle(language.GetFromHtml("import")).
+		SetTheme(theme).
+		SetContent(template2.HTML(fmt.Sprintf(language.Get("%d of %d rows have been imported"),
+			report.Inserted, report.Total))).
+		GetContent()
+
+	if len(report.Failed) > 0 {
+		content += importTable(param.Panel, nil, report.Failed)
+	}
+
+	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	buf := template.Execute(tmpl, tmplName, user, types.Panel{
+		Content:     content,
+		Description: param.Panel.GetForm().Description,
+		Title:       param.Panel.GetForm().Title,
+	}, h.config, menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.config.URLRemovePrefix(ctx.Path())))
+	ctx.HTML(http.StatusOK, buf.String())
+}
+
+// importTable render the rows to import with their errors. The columns of
+// the table are the mapped form fields, which are all fields of the form
+// when the columns are not given.
+func importTable(panel table.Table, columns []table.ImportColumn, rows []table.ImportRow) template2.HTML {
+
+	var (
+		fields     = panel.GetForm().FieldList
+		lineHead   = language.Get("line")
+		errorsHead = language.Get("errors")
+		thead      = types.Thead{{Head: lineHead, Field: lineHead}}
+		unmapped   = make([]string, 0)
+	)
+
+	if columns == nil {
+		for _, field := range fields {
+			if !field.NotAllowAdd && !field.Hide {
+				columns = append(columns, table.ImportColumn{Head: field.Head, Field: field.Field})
+			}
+		}
+	}
+
+	for _, column := range columns {
+		if column.Field == "" {
+			unmapped = append(unmapped, column.Head)
+			continue
+		}
+		head := fields.FindByFieldName(column.Field).Head
+		thead = append(thead, types.TheadItem{Head: head, Field: head})
+	}
+	thead = append(thead, types.TheadItem{Head: errorsHead, Field: errorsHead})
+
+	list := make([]map[string]types.InfoItem, len(rows))
+	for i, row := range rows {
+		list[i] = map[string]types.InfoItem{
+			lineHead: {Content: template2.HTML(fmt.Sprintf("%d", row.Line))},
+		}
+		for _, column := range columns {
+			if column.Field == "" {
+				continue
+			}
+			value := row.Values.Get(column.Field)
+			if v, ok := row.Values[column.Field+"[]"]; ok {
+				value = strings.Join(v, ",")
+			}
+			if table.IsSensitiveField(column.Field) {
+				value = table.AuditRedacted
+			}
+			list[i][fields.FindByFieldName(column.Field).Head] = types.InfoItem{
+				Content: template2.HTML(template2.HTMLEscapeString(value)),
+				Value:   value,
+			}
+		}
+		errs := make([]string, len(row.Errors))
+		for j, err := range row.Errors {
+			errs[j] = template2.HTMLEscapeString(err)
+		}
+		list[i][errorsHead] = types.InfoItem{
+			Content: template2.HTML(`<span class="text-red">` + strings.Join(errs, "<br>") + `</span>`),
+		}
+	}
+
+	header := template2.HTML(language.Get("preview"))
+	if len(unmapped) > 0 {
+		header += template2.HTML(" <small>" + language.Get("ignored columns") + ": " +
+			template2.HTMLEscapeString(strings.Join(unmapped, ", ")) + "</small>")
+	}
+
+	return aBox().
+		SetHeader(header).
+		WithHeadBorder().
+		SetBody(aTemplate().Table().SetType("table").SetThead(thead).SetInfoList(list).GetContent()).
+		GetContent()
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/cache"
 	"github.com/GoAdminGroup/go-admin/modules/menu"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
@@ -45,7 +46,7 @@ $('.icon').iconpicker({placement: 'bottomLeft'});
 			SetPrimaryKey(panel.GetPrimaryKey().Name).
 			SetUrl(h.config.Url("/menu/edit")).
 			SetHiddenFields(map[string]string{
-				form2.TokenKey:    h.authSrv().AddToken(),
+				form2.TokenKey:    h.authSrv().AddToken(ctx),
 				form2.PreviousKey: h.config.Url("/menu"),
 			}).
 			SetOperationFooter(formFooter("new"))) +
@@ -99,7 +100,7 @@ $('.icon').iconpicker({placement: 'bottomLeft'});
 			SetUrl(h.config.Url("/menu/edit")).
 			SetOperationFooter(formFooter("edit")).
 			SetHiddenFields(map[string]string{
-				form2.TokenKey:    h.authSrv().AddToken(),
+				form2.TokenKey:    h.authSrv().AddToken(ctx),
 				form2.PreviousKey: h.config.Url("/menu"),
 			})) + template2.HTML(js),
 		Description: formInfo.Description,
@@ -112,6 +113,7 @@ $('.icon').iconpicker({placement: 'bottomLeft'});
 // DeleteMenu delete the menu of given id.
 func (h *Handler) DeleteMenu(ctx *context.Context) {
 	models.MenuWithId(guard.GetMenuDeleteParam(ctx).Id).SetConn(h.conn).Delete()
+	cache.Invalidate("goadmin_menu", "goadmin_role_menu")
 	response.Ok(ctx)
 }
 
@@ -135,6 +137,7 @@ func (h *Handler) EditMenu(ctx *context.Context) {
 	}
 
 	menuModel.Update(param.Title, param.Icon, param.Uri, param.Header, param.ParentId)
+	cache.Invalidate("goadmin_menu", "goadmin_role_menu")
 
 	h.getMenuInfoPanel(ctx, "")
 	ctx.AddHeader("Content-Type", "text/html; charset=utf-8")
@@ -162,6 +165,8 @@ func (h *Handler) NewMenu(ctx *context.Context) {
 		menuModel.AddRole(roleId)
 	}
 
+	cache.Invalidate("goadmin_menu", "goadmin_role_menu")
+
 	menu.GetGlobalMenu(user, h.conn).AddMaxOrder()
 
 	h.getMenuInfoPanel(ctx, "")
@@ -175,7 +180,13 @@ func (h *Handler) MenuOrder(ctx *context.Context) {
 	var data []map[string]interface{}
 	_ = json.Unmarshal([]byte(ctx.FormValue("_order")), &data)
 
-	models.Menu().SetConn(h.conn).ResetOrder(data)
+	err := models.Menu().SetConn(h.conn).ResetOrder(data)
+	cache.Invalidate("goadmin_menu", "goadmin_role_menu")
+
+	if err != nil {
+		response.Error(ctx, err.Error())
+		return
+	}
 
 	response.Ok(ctx)
 }
@@ -208,7 +219,7 @@ func (h *Handler) getMenuInfoPanel(ctx *context.Context, alert template2.HTML) {
 		SetUrl(h.config.Url("/menu/new")).
 		SetPrimaryKey(h.table("menu", ctx).GetPrimaryKey().Name).
 		SetHiddenFields(map[string]string{
-			form2.TokenKey:    h.authSrv().AddToken(),
+			form2.TokenKey:    h.authSrv().AddToken(ctx),
 			form2.PreviousKey: h.config.Url("/menu"),
 		}).
 		SetOperationFooter(formFooter("menu")).
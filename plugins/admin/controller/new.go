@@ -6,6 +6,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/auth"
 	"github.com/GoAdminGroup/go-admin/modules/file"
 	"github.com/GoAdminGroup/go-admin/modules/menu"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
@@ -13,6 +14,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/template/types"
 	template2 "html/template"
 	"net/http"
+	"net/url"
 )
 
 // ShowNewForm show a new form page.
@@ -33,6 +35,12 @@ func (h *Handler) showNewForm(ctx *context.Context, alert template2.HTML, prefix
 	newUrl := h.routePathWithPrefix("new", prefix)
 	showNewUrl := h.routePathWithPrefix("show_new", prefix) + paramStr
 
+	editUrl := modules.AorEmpty(panel.GetEditable(), h.routePathWithPrefix("show_edit", prefix)+paramStr)
+	footerKind := "new"
+	if editUrl != "" && user.CheckPermissionByUrlMethod(editUrl, h.route("show_edit").Method(), url.Values{}) {
+		footerKind = "new_edit"
+	}
+
 	referer := ctx.Headers("Referer")
 
 	if referer != "" && !isInfoUrl(referer) && !isNewUrl(referer, ctx.Query(constant.PrefixKey)) {
@@ -50,11 +58,11 @@ func (h *Handler) showNewForm(ctx *context.Context, alert template2.HTML, prefix
 			SetUrl(newUrl).
 			SetPrimaryKey(panel.GetPrimaryKey().Name).
 			SetHiddenFields(map[string]string{
-				form2.TokenKey:    h.authSrv().AddToken(),
+				form2.TokenKey:    h.authSrv().AddToken(ctx),
 				form2.PreviousKey: infoUrl,
 			}).
 			SetTitle("New").
-			SetOperationFooter(formFooter("new")).
+			SetOperationFooter(formFooter(footerKind)).
 			SetHeader(panel.GetForm().HeaderHtml).
 			SetFooter(panel.GetForm().FooterHtml)),
 		Description: panel.GetForm().Description,
@@ -107,6 +115,12 @@ func (h *Handler) NewForm(ctx *context.Context) {
 			return
 		}
 
+		if isEditUrl(param.PreviousPath, param.Prefix) {
+			id := param.Value().Get(param.Panel.GetPrimaryKey().Name)
+			h.showForm(ctx, param.Alert, param.Prefix, param.Param.WithPKs(id).AddField(constant.EditPKKey, id), true, false)
+			return
+		}
+
 		ctx.HTML(http.StatusOK, fmt.Sprintf(`<script>location.href="%s"</script>`, param.PreviousPath))
 		ctx.AddHeader(constant.PjaxUrlHeader, param.PreviousPath)
 		return
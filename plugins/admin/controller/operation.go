@@ -3,19 +3,62 @@ package controller
 import (
 	"encoding/json"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
 
 	"github.com/GoAdminGroup/go-admin/context"
 )
 
 // RecordOperationLog record all operation logs, store into database.
+// The table writings of the request are recorded with their changes.
 func (h *Handler) RecordOperationLog(ctx *context.Context) {
 	if user, ok := ctx.UserValue["user"].(models.UserModel); ok {
 		var input []byte
 		form := ctx.Request.MultipartForm
 		if form != nil {
-			input, _ = json.Marshal((*form).Value)
+			input, _ = json.Marshal(redactInput((*form).Value))
 		}
 
-		models.OperationLog().SetConn(h.conn).New(user.Id, ctx.Path(), ctx.Method(), ctx.LocalIP(), string(input))
+		records := table.GetAuditRecords(ctx)
+
+		if len(records) == 0 {
+			models.OperationLog().SetConn(h.conn).New(user.Id, ctx.Path(), ctx.Method(), ctx.LocalIP(), string(input))
+			return
+		}
+
+		for _, record := range records {
+			var diff []byte
+			if len(record.Changes) > 0 {
+				diff, _ = json.Marshal(record.Changes)
+			}
+			models.OperationLog().SetConn(h.conn).NewAudit(user.Id, ctx.Path(), ctx.Method(), ctx.LocalIP(),
+				string(input), record.Prefix, record.PK, record.Op, string(diff))
+		}
+	}
+}
+
+func redactInput(values map[string][]string) map[string][]string {
+	res := make(map[string][]string, len(values))
+	for key, value := range values {
+		if table.IsSensitiveField(key) {
+			redacted := make([]string, len(value))
+			for i := range value {
+				redacted[i] = table.AuditRedacted
+			}
+			res[key] = redacted
+		} else {
+			res[key] = value
+		}
 	}
+	return res
+}
+
+// recordLoginFailure record a failed login with the reason, the password
+// is never recorded.
+func (h *Handler) recordLoginFailure(ctx *context.Context, userId int64, username, reason string) {
+	input, _ := json.Marshal(map[string]string{
+		"username": username,
+		"reason":   reason,
+	})
+	models.OperationLog().SetConn(h.conn).NewAudit(userId, ctx.Path(), ctx.Method(), ctx.LocalIP(),
+		string(input), "", "", table.AuditLoginFailure, "")
 }
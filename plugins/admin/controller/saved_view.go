@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"bytes"
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	template2 "html/template"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SaveView save the view of the list posted, which is private to the user
+// or shared with a role of the user.
+func (h *Handler) SaveView(ctx *context.Context) {
+
+	var (
+		prefix    = ctx.Query(constant.PrefixKey)
+		user      = auth.Auth(ctx)
+		name      = strings.TrimSpace(ctx.FormValue("name"))
+		roleId, _ = strconv.ParseInt(ctx.FormValue("role_id"), 10, 64)
+	)
+
+	if name == "" {
+		response.BadRequest(ctx, "name can not be empty")
+		return
+	}
+
+	if roleId != 0 && !hasRole(user, roleId) {
+		response.Forbidden(ctx, "operation not allow")
+		return
+	}
+
+	info := h.table(prefix, ctx).GetInfo()
+	params := parameter.GetParam(&url.URL{RawQuery: ctx.FormValue("params")}, info.DefaultPageSize,
+		info.SortField, info.GetSort())
+
+	_, err := models.SavedView().SetConn(h.conn).New(user.Id, roleId, prefix, name, params.ViewParamStr())
+
+	if err != nil {
+		logger.Error(err)
+		response.Error(ctx, "save view fail")
+		return
+	}
+
+	response.Ok(ctx)
+}
+
+// DeleteView delete a saved view of the list, only the user who saved the
+// view can delete it.
+func (h *Handler) DeleteView(ctx *context.Context) {
+
+	view := models.SavedView().SetConn(h.conn).Find(ctx.FormValue("id"))
+
+	if view.IsEmpty() || view.Prefix != ctx.Query(constant.PrefixKey) {
+		response.NotFound(ctx, "view not found")
+		return
+	}
+
+	if view.UserId != auth.Auth(ctx).Id {
+		response.Forbidden(ctx, "operation not allow")
+		return
+	}
+
+	if err := view.Delete(); err != nil {
+		logger.Error(err)
+		response.Error(ctx, "delete view fail")
+		return
+	}
+
+	response.Ok(ctx)
+}
+
+func hasRole(user models.UserModel, roleId int64) bool {
+	for _, role := range user.Roles {
+		if role.Id == roleId {
+			return true
+		}
+	}
+	return false
+}
+
+type savedViewItem struct {
+	Id        int64
+	Name      string
+	URL       string
+	Role      string
+	Active    bool
+	Deletable bool
+}
+
+var savedViewsTmpl = template2.Must(template2.New("saved_views").Funcs(template2.FuncMap{"lang": language.Get}).Parse(`
+<div class="btn-group pull-right saved-views" style="margin-right: 10px;">
+<button type="button" class="btn btn-sm btn-default dropdown-toggle" data-toggle="dropdown">
+<i class="fa fa-bookmark"></i> {{if .Current}}{{.Current}}{{else}}{{lang "saved views"}}{{end}} <span class="caret"></span>
+</button>
+<ul class="dropdown-menu" role="menu">
+{{- range .Views}}<li{{if .Active}} class="active"{{end}}><a href="{{.URL}}">{{.Name}}
+{{- if .Role}} <small>({{.Role}})</small>{{end}}
+{{- if .Deletable}}<span class="pull-right saved-view-delete" data-id="{{.Id}}" style="margin-left: 10px;"><i class="fa fa-trash"></i></span>{{end -}}
+</a></li>{{end}}
+{{- if .SaveUrl}}{{if .Views}}<li class="divider"></li>{{end}}
+<li><a href="javascript:;" class="saved-view-save"><i class="fa fa-save"></i> {{lang "save current view"}}</a></li>{{end}}
+</ul>
+</div>
+{{- if .SaveUrl}}
+<div class="modal fade" id="saved-view-modal" tabindex="-1" role="dialog">
+<div class="modal-dialog modal-sm" role="document"><div class="modal-content"><form>
+<div class="modal-header"><button type="button" class="close" data-dismiss="modal"><span>&times;</span></button>
+<h4 class="modal-title">{{lang "save current view"}}</h4></div>
+<div class="modal-body">
+<div class="form-group"><label>{{lang "name"}}</label><input type="text" class="form-control" name="name" maxlength="100" required></div>
+<div class="form-group"><label>{{lang "share with"}}</label><select class="form-control" name="role_id">
+<option value="0">{{lang "only me"}}</option>
+{{- range .Roles}}<option value="{{.Id}}">{{.Name}}</option>{{end -}}
+</select></div>
+</div>
+<div class="modal-footer"><button type="submit" class="btn btn-primary btn-sm">{{lang "save"}}</button></div>
+</form></div></div>
+</div>
+<script>
+(function () {
+	let saveUrl = {{.SaveUrl}};
+	let deleteUrl = {{.DeleteUrl}};
+	let params = {{.Params}};
+	let modal = $('#saved-view-modal');
+	let done = function (data) {
+		if (typeof (data) === "string") {
+			data = JSON.parse(data);
+		}
+		if (data.code === 200) {
+			modal.modal('hide');
+			$.pjax.reload('#pjax-container');
+		} else {
+			swal(data.msg, '', 'error');
+		}
+	};
+	let fail = function (xhr) {
+		let data = xhr.responseJSON || {};
+		swal(data.msg || xhr.statusText, '', 'error');
+	};
+	$('.saved-views .saved-view-save').on('click', function () {
+		modal.modal('show');
+	});
+	modal.find('form').on('submit', function (event) {
+		event.preventDefault();
+		$.ajax({
+			method: 'post',
+			url: saveUrl,
+			data: {
+				name: modal.find('[name="name"]').val(),
+				role_id: modal.find('[name="role_id"]').val(),
+				params: params
+			},
+			success: done,
+			error: fail
+		});
+	});
+	$('.saved-views .saved-view-delete').on('click', function (event) {
+		event.preventDefault();
+		event.stopPropagation();
+		$.ajax({
+			method: 'post',
+			url: deleteUrl,
+			data: {
+				id: $(this).data('id')
+			},
+			success: done,
+			error: fail
+		});
+	});
+})();
+</script>
+{{- end}}`))
+
+// savedViews render the dropdown of the saved views of the list in the
+// header of the data table, the current view is marked active and can be
+// saved if the user is permitted.
+func (h *Handler) savedViews(ctx *context.Context, prefix string, params parameter.Parameters) template2.HTML {
+
+	var (
+		user      = auth.Auth(ctx)
+		infoUrl   = h.routePathWithPrefix("info", prefix)
+		saveUrl   = user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("save_view", prefix), h.route("save_view").Method())
+		deleteUrl = user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("delete_view", prefix), h.route("delete_view").Method())
+		current   = params.ViewParamStr()
+		roles     = make(map[int64]string)
+		views     = models.SavedView().SetConn(h.conn).List(prefix, user)
+		items     = make([]savedViewItem, len(views))
+		active    = ""
+	)
+
+	if saveUrl == "" && len(views) == 0 {
+		return ""
+	}
+
+	for _, role := range user.Roles {
+		roles[role.Id] = role.Name
+	}
+
+	for i, view := range views {
+		items[i] = savedViewItem{
+			Id:        view.Id,
+			Name:      view.Name,
+			URL:       infoUrl + "?" + view.Params,
+			Role:      roles[view.RoleId],
+			Active:    view.Params == current,
+			Deletable: view.UserId == user.Id && deleteUrl != "",
+		}
+		if items[i].Active && active == "" {
+			active = view.Name
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	err := savedViewsTmpl.Execute(buf, map[string]interface{}{
+		"Current":   active,
+		"Views":     items,
+		"Roles":     user.Roles,
+		"SaveUrl":   saveUrl,
+		"DeleteUrl": deleteUrl,
+		"Params":    current,
+	})
+	if err != nil {
+		logger.Error(err)
+		return ""
+	}
+	return template2.HTML(buf.String())
+}
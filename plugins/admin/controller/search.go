@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"bytes"
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/menu"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	template2 "html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// searchLimit is the max number of the results of a table.
+const searchLimit = 10
+
+type searchRow struct {
+	URL    string
+	ID     string
+	Values []string
+}
+
+type searchGroup struct {
+	Title   string
+	InfoUrl string
+	Heads   []string
+	Rows    []searchRow
+}
+
+var searchTmpl = template2.Must(template2.New("search").Funcs(template2.FuncMap{"lang": language.Get}).Parse(`
+{{- define "form"}}<form action="{{.Url}}" method="get" class="global-search-form" style="margin-bottom: 15px;">
+<div class="input-group">
+<input type="text" name="q" class="form-control" value="{{.Keyword}}" placeholder="{{lang "search"}}" autofocus>
+<span class="input-group-btn"><button type="submit" class="btn btn-primary"><i class="fa fa-search"></i></button></span>
+</div>
+</form>
+{{- if and .Keyword (not .Groups)}}<p class="text-muted">{{lang "no results"}}</p>{{end}}{{end}}
+{{- define "group"}}<table class="table table-hover table-condensed" style="margin-bottom: 0;">
+<thead><tr><th style="width: 80px;">ID</th>{{range .Heads}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>{{range .Rows}}<tr>
+<td>{{if .URL}}<a href="{{.URL}}">{{.ID}}</a>{{else}}{{.ID}}{{end}}</td>
+{{- range .Values}}<td>{{.}}</td>{{end -}}
+</tr>{{end}}</tbody>
+</table>{{end}}`))
+
+// Search search the keyword in the searchable fields of all the tables,
+// the tables of which the user is not permitted to see the list are
+// skipped. The results are grouped by the table and linked to the detail
+// pages.
+func (h *Handler) Search(ctx *context.Context) {
+
+	var (
+		keyword  = strings.TrimSpace(ctx.Query("q"))
+		user     = auth.Auth(ctx)
+		groups   = make([]searchGroup, 0)
+		prefixes = make([]string, 0, len(h.generators))
+	)
+
+	for prefix := range h.generators {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	for _, prefix := range prefixes {
+		if keyword == "" {
+			break
+		}
+
+		infoUrl := user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("info", prefix), h.route("info").Method())
+		if infoUrl == "" {
+			continue
+		}
+
+		panel := h.table(prefix, ctx)
+		info := panel.GetInfo()
+
+		rows, err := panel.Search(ctx.Request.Context(), keyword, searchLimit)
+		if err != nil {
+			logger.Error("search ", prefix, " error: ", err)
+			continue
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		var (
+			pk        = panel.GetPrimaryKey().Name
+			detailUrl = user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("detail", prefix), h.route("detail").Method())
+			group     = searchGroup{Title: info.Title, InfoUrl: infoUrl, Heads: make([]string, 0)}
+			fields    = make([]types.Field, 0)
+		)
+
+		if group.Title == "" {
+			group.Title = prefix
+		}
+
+		for _, field := range info.FieldList {
+			if field.Searchable && field.Field != pk {
+				group.Heads = append(group.Heads, field.Head)
+				fields = append(fields, field)
+			}
+		}
+
+		for _, row := range rows {
+			id := db.GetValueFromDatabaseType(panel.GetPrimaryKey().Type, row[pk], false).String()
+			item := searchRow{ID: id, Values: make([]string, len(fields))}
+			if detailUrl != "" {
+				item.URL = detailUrl + "?" + url.Values{constant.DetailPKKey: []string{id}}.Encode()
+			}
+			for i, field := range fields {
+				item.Values[i] = db.GetValueFromDatabaseType(field.TypeName, row[field.Field], false).String()
+			}
+			group.Rows = append(group.Rows, item)
+		}
+
+		groups = append(groups, group)
+	}
+
+	buf := new(bytes.Buffer)
+	_ = searchTmpl.ExecuteTemplate(buf, "form", map[string]interface{}{
+		"Url":     h.routePath("search"),
+		"Keyword": keyword,
+		"Groups":  groups,
+	})
+	content := template2.HTML(buf.String())
+
+	for _, group := range groups {
+		buf.Reset()
+		_ = searchTmpl.ExecuteTemplate(buf, "group", group)
+		content += aBox().
+			SetHeader(template2.HTML(`<a href="` + template2.HTMLEscapeString(group.InfoUrl) + `">` +
+				template2.HTMLEscapeString(group.Title) + `</a>`)).
+			WithHeadBorder().
+			SetNoPadding().
+			SetBody(template2.HTML(buf.String())).
+			GetContent()
+	}
+
+	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	buf = template.Execute(tmpl, tmplName, user, types.Panel{
+		Content:     content,
+		Description: keyword,
+		Title:       language.Get("search"),
+	}, h.config, menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.config.URLRemovePrefix(ctx.Path())))
+	ctx.HTML(http.StatusOK, buf.String())
+}
+
+// The results are loaded with pjax when it is available.
+const searchBoxJS = `<script>
+document.addEventListener('submit', function (event) {
+	let form = event.target;
+	if (!form.closest || !form.closest('.global-search') || !window.$ || !$.pjax) {
+		return;
+	}
+	event.preventDefault();
+	$.pjax({url: form.action + '?' + $(form).serialize(), container: '#pjax-container'});
+});
+</script>`
+
+// SearchBox return the search box of the navbar.
+func (h *Handler) SearchBox() template2.HTML {
+	return template2.HTML(`<li class="global-search"><form class="navbar-form" action="`+
+		template2.HTMLEscapeString(h.routePath("search"))+`" method="get" style="margin: 8px 10px; padding: 0; border: 0;">`+
+		`<input type="text" name="q" class="form-control input-sm" placeholder="`+
+		template2.HTMLEscapeString(language.Get("search"))+`"></form></li>`) + searchBoxJS
+}
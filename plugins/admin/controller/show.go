@@ -2,8 +2,6 @@ package controller
 
 import (
 	"bytes"
-	"fmt"
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/auth"
 	"github.com/GoAdminGroup/go-admin/modules/language"
@@ -12,10 +10,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
-	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
-	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
-	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
 	"github.com/GoAdminGroup/go-admin/template"
 	"github.com/GoAdminGroup/go-admin/template/icon"
 	"github.com/GoAdminGroup/go-admin/template/types"
@@ -24,9 +19,7 @@ import (
 	template2 "html/template"
 	"net/http"
 	"path"
-	"strconv"
 	"strings"
-	"time"
 )
 
 // ShowInfo show info page.
@@ -46,7 +39,7 @@ func (h *Handler) showTable(ctx *context.Context, prefix string, params paramete
 
 	panel := h.table(prefix, ctx)
 
-	panelInfo, err := panel.GetData(params.WithIsAll(false))
+	panelInfo, err := panel.GetDataWithContext(ctx.Request.Context(), params.WithIsAll(false))
 
 	if err != nil {
 		tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
@@ -70,6 +63,7 @@ func (h *Handler) showTable(ctx *context.Context, prefix string, params paramete
 	deleteUrl := modules.AorEmpty(panel.GetDeletable(), h.routePathWithPrefix("delete", prefix))
 	exportUrl := modules.AorEmpty(panel.GetExportable(), h.routePathWithPrefix("export", prefix)+paramStr)
 	detailUrl := modules.AorEmpty(panel.IsShowDetail(), h.routePathWithPrefix("detail", prefix)+paramStr)
+	importUrl := modules.AorEmpty(panel.GetImportable(), h.routePathWithPrefix("show_import", prefix)+paramStr)
 
 	infoUrl := h.routePathWithPrefix("info", prefix)
 	updateUrl := h.routePathWithPrefix("update", prefix)
@@ -81,18 +75,39 @@ func (h *Handler) showTable(ctx *context.Context, prefix string, params paramete
 	deleteUrl = user.GetCheckPermissionByUrlMethod(deleteUrl, h.route("delete").Method())
 	exportUrl = user.GetCheckPermissionByUrlMethod(exportUrl, h.route("export").Method())
 	detailUrl = user.GetCheckPermissionByUrlMethod(detailUrl, h.route("detail").Method())
+	importUrl = user.GetCheckPermissionByUrlMethod(importUrl, h.route("show_import").Method())
+
+	// the export button exports to the format chosen by the selector.
+	exportFormat := panel.GetInfo().ExportFormat(ctx.Query(constant.ExportFormatKey))
+	if exportUrl != "" {
+		exportUrl += "&" + constant.ExportFormatKey + "=" + exportFormat
+	}
+
+	// the subtotals of the groups are not the rows to act on.
+	if panelInfo.GroupBy != "" {
+		editUrl, deleteUrl, detailUrl = "", "", ""
+	}
 
 	var (
-		body       template2.HTML
-		dataTable  types.DataTableAttribute
-		info       = panel.GetInfo()
-		actionBtns = info.Action
-		actionJs   template2.JS
+		body            template2.HTML
+		dataTable       types.DataTableAttribute
+		info            = panel.GetInfo()
+		actionBtns      = info.Action
+		actionJs        template2.JS
+		hideRowSelector = info.IsHideRowSelector || panelInfo.GroupBy != ""
 	)
 
+	if panelInfo.GroupBy != "" {
+		actionBtns = ""
+	}
+
+	if importUrl != "" {
+		info.AddButton(language.GetFromHtml("import"), icon.Upload, action.Jump(importUrl))
+	}
+
 	btns, btnsJs := info.Buttons.Content()
 
-	if actionBtns == template.HTML("") && len(info.ActionButtons) > 0 {
+	if actionBtns == template.HTML("") && len(info.ActionButtons) > 0 && panelInfo.GroupBy == "" {
 		ext := template.HTML("")
 		if deleteUrl != "" {
 			ext = html.LiEl().SetClass("divider").Get()
@@ -152,7 +167,7 @@ func (h *Handler) showTable(ctx *context.Context, prefix string, params paramete
 					SetIsTab(key != 0).
 					SetPrimaryKey(panel.GetPrimaryKey().Name).
 					SetThead(theadArr[key]).
-					SetHideRowSelector(info.IsHideRowSelector).
+					SetHideRowSelector(hideRowSelector).
 					SetLayout(info.TableLayout).
 					SetExportUrl(exportUrl).
 					SetNewUrl(newUrl).
@@ -176,20 +191,34 @@ func (h *Handler) showTable(ctx *context.Context, prefix string, params paramete
 			SetPrimaryKey(panel.GetPrimaryKey().Name).
 			SetThead(panelInfo.Thead).
 			SetExportUrl(exportUrl).
-			SetHideRowSelector(info.IsHideRowSelector).
+			SetHideRowSelector(hideRowSelector).
 			SetHideFilterArea(info.IsHideFilterArea).
 			SetNewUrl(newUrl).
 			SetEditUrl(editUrl).
 			SetUpdateUrl(updateUrl).
 			SetDetailUrl(detailUrl).
 			SetDeleteUrl(deleteUrl)
+		if len(panelInfo.Aggregates) > 0 {
+			dataTable = dataTable.SetFooter(panelInfo.Thead.AggregateFooter(panelInfo.Aggregates))
+		}
 		body = dataTable.GetContent()
 	}
 
+	exportFormatSelector := template2.HTML("")
+	if exportUrl != "" {
+		exportFormatSelector = info.ExportFormatSelector(exportFormat, func(format string) string {
+			return infoUrl + paramStr + "&" + constant.ExportFormatKey + "=" + format
+		})
+	}
+
+	groupBy := info.FieldList.GroupBySelector(panelInfo.GroupBy, func(field string) string {
+		return infoUrl + params.WithGroupBy(field).GetRouteParamStr()
+	})
+
 	boxModel := aBox().
 		SetBody(body).
 		SetNoPadding().
-		SetHeader(dataTable.GetDataTableHeader() + info.HeaderHtml).
+		SetHeader(dataTable.GetDataTableHeader() + exportFormatSelector + groupBy + h.savedViews(ctx, prefix, params) + info.HeaderHtml).
 		WithHeadBorder().
 		SetFooter(panelInfo.Paginator.GetContent() + info.FooterHtml)
 
@@ -248,78 +277,3 @@ func (h *Handler) Assets(ctx *context.Context) {
 		"content-type": contentType,
 	}, string(data))
 }
-
-// Export export table rows as excel object.
-func (h *Handler) Export(ctx *context.Context) {
-	param := guard.GetExportParam(ctx)
-
-	tableName := "Sheet1"
-	prefix := ctx.Query(constant.PrefixKey)
-	panel := h.table(prefix, ctx)
-
-	f := excelize.NewFile()
-	index := f.NewSheet(tableName)
-	f.SetActiveSheet(index)
-
-	// TODO: support any numbers of fields.
-	orders := []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K",
-		"L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z"}
-
-	var (
-		infoData  table.PanelInfo
-		fileName  string
-		err       error
-		tableInfo = panel.GetInfo()
-	)
-
-	if len(param.Id) == 0 {
-		params := parameter.GetParam(ctx.Request.URL, tableInfo.DefaultPageSize, tableInfo.SortField,
-			tableInfo.GetSort())
-		infoData, err = panel.GetData(params.WithIsAll(param.IsAll))
-		fileName = fmt.Sprintf("%s-%d-page-%s-pageSize-%s.xlsx", tableInfo.Title, time.Now().Unix(),
-			params.Page, params.PageSize)
-	} else {
-		infoData, err = panel.GetDataWithIds(parameter.GetParam(ctx.Request.URL,
-			tableInfo.DefaultPageSize, tableInfo.SortField, tableInfo.GetSort()).WithPKs(param.Id...))
-		fileName = fmt.Sprintf("%s-%d-id-%s.xlsx", tableInfo.Title, time.Now().Unix(), strings.Join(param.Id, "_"))
-	}
-
-	if err != nil {
-		response.Error(ctx, "export error")
-		return
-	}
-
-	columnIndex := 0
-	for _, head := range infoData.Thead {
-		if !head.Hide {
-			f.SetCellValue(tableName, orders[columnIndex]+"1", head.Head)
-			columnIndex++
-		}
-	}
-
-	count := 2
-	for _, info := range infoData.InfoList {
-		columnIndex = 0
-		for _, head := range infoData.Thead {
-			if !head.Hide {
-				if tableInfo.IsExportValue() {
-					f.SetCellValue(tableName, orders[columnIndex]+strconv.Itoa(count), info[head.Field].Value)
-				} else {
-					f.SetCellValue(tableName, orders[columnIndex]+strconv.Itoa(count), info[head.Field].Content)
-				}
-				columnIndex++
-			}
-		}
-		count++
-	}
-
-	buf, err := f.WriteToBuffer()
-
-	if err != nil || buf == nil {
-		response.Error(ctx, "export error")
-		return
-	}
-
-	ctx.AddHeader("content-disposition", `attachment; filename=`+fileName)
-	ctx.Data(200, "application/vnd.ms-excel", buf.Bytes())
-}
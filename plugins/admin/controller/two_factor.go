@@ -0,0 +1,403 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/menu"
+	"github.com/GoAdminGroup/go-admin/modules/system"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/login"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/skip2/go-qrcode"
+	template2 "html/template"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The session keys of the second step of login.
+const (
+	twoFactorUserSesKey     = "two_factor_user_id"
+	twoFactorExpiresSesKey  = "two_factor_expires"
+	twoFactorAttemptsSesKey = "two_factor_attempts"
+	twoFactorSecretSesKey   = "two_factor_secret"
+)
+
+const (
+	twoFactorLoginTimeout     = 5 * time.Minute
+	twoFactorLoginMaxAttempts = 5
+)
+
+// needTwoFactor check the user should pass the two-factor authentication
+// to login, which is enabled by the user or required by a role of the user.
+func (h *Handler) needTwoFactor(user models.UserModel) bool {
+	return user.SetConn(h.conn).IsTwoFactorRequired() ||
+		!models.UserTwoFactor().SetConn(h.conn).FindByUserId(user.Id).IsEmpty()
+}
+
+// startTwoFactorLogin keep the user passed the password checking in the
+// session, and the user is logged in after the second step.
+func (h *Handler) startTwoFactorLogin(ctx *context.Context, user models.UserModel) {
+	ses := auth.InitSession(ctx, h.conn)
+	ses.Values = map[string]interface{}{
+		twoFactorExpiresSesKey: time.Now().Add(twoFactorLoginTimeout).Unix(),
+	}
+	ses.Add(twoFactorUserSesKey, user.Id)
+}
+
+func (h *Handler) twoFactorLoginUser(ctx *context.Context) (*auth.Session, models.UserModel, bool) {
+	ses := auth.InitSession(ctx, h.conn)
+
+	id, ok := ses.Get(twoFactorUserSesKey).(float64)
+	expires, _ := ses.Get(twoFactorExpiresSesKey).(float64)
+
+	if !ok || int64(expires) < time.Now().Unix() {
+		return ses, models.User(), false
+	}
+
+	user := models.User().SetConn(h.conn).Find(int64(id))
+
+	return ses, user, !user.IsEmpty()
+}
+
+// ShowTwoFactorLogin show the second step of login, which checks the code of
+// the authenticator app or a recovery code. The users required to use the
+// two-factor authentication by their roles enroll here at the first time.
+func (h *Handler) ShowTwoFactorLogin(ctx *context.Context) {
+
+	ses, user, ok := h.twoFactorLoginUser(ctx)
+
+	if !ok {
+		ctx.AddHeader("Location", h.config.Url("/login"))
+		ctx.SetStatusCode(http.StatusFound)
+		return
+	}
+
+	var (
+		qrCode template2.URL
+		secret string
+	)
+
+	if models.UserTwoFactor().SetConn(h.conn).FindByUserId(user.Id).IsEmpty() {
+		secret = h.pendingTwoFactorSecret(ses)
+		qrCode = twoFactorQRCode(h.config.Title, user.UserName, secret)
+	}
+
+	tmpl, name := login.GetLoginComponent().GetTwoFactorTemplate()
+	buf := new(bytes.Buffer)
+	if err := tmpl.ExecuteTemplate(buf, name, struct {
+		UrlPrefix string
+		Title     string
+		CdnUrl    string
+		QRCode    template2.URL
+		Secret    string
+		Token     string
+		System    types.SystemInfo
+	}{
+		UrlPrefix: h.config.AssertPrefix(),
+		Title:     h.config.LoginTitle,
+		CdnUrl:    h.config.AssetUrl,
+		QRCode:    qrCode,
+		Secret:    secret,
+		Token:     h.authSrv().AddAnonymousToken(ctx),
+		System: types.SystemInfo{
+			Version: system.Version(),
+		},
+	}); err == nil {
+		ctx.HTML(http.StatusOK, buf.String())
+	} else {
+		logger.Error(err)
+		ctx.HTML(http.StatusOK, "parse template error (；′⌒`)")
+	}
+}
+
+// TwoFactorLogin check the code of the second step of login and set the
+// cookie of the user. The recovery codes are responded when the user
+// enrolls at the login.
+func (h *Handler) TwoFactorLogin(ctx *context.Context) {
+
+	ses, user, ok := h.twoFactorLoginUser(ctx)
+
+	if !ok {
+		twoFactorLoginExpired(ctx, h.config.Url("/login"), "login expired, please login again")
+		return
+	}
+
+	// the failed codes are limited and lock the user as the wrong passwords.
+	if !auth.GetLoginLimiter().Allow(auth.ClientIP(ctx), user.UserName) {
+		response.BadRequest(ctx, "too many login attempts, please try again later")
+		return
+	}
+
+	if models.UserLockout().SetConn(h.conn).FindByUserId(user.Id).IsLocked() {
+		ses.Clear()
+		h.recordLoginFailure(ctx, user.Id, user.UserName, "account locked")
+		twoFactorLoginExpired(ctx, h.config.Url("/login"), "account locked, please try again later")
+		return
+	}
+
+	var (
+		code          = ctx.FormValue("code")
+		twoFactor     = models.UserTwoFactor().SetConn(h.conn).FindByUserId(user.Id)
+		recoveryCodes []string
+	)
+
+	if twoFactor.IsEmpty() {
+		secret, _ := ses.Get(twoFactorSecretSesKey).(string)
+		counter, valid := auth.CheckTwoFactorCode(secret, code, 0)
+		if secret == "" || !valid {
+			h.twoFactorLoginFail(ctx, ses, user)
+			return
+		}
+		var err error
+		twoFactor, recoveryCodes, err = models.UserTwoFactor().SetConn(h.conn).New(user.Id, secret)
+		if err != nil {
+			logger.Error(err)
+			response.Error(ctx, "enable two-factor authentication fail")
+			return
+		}
+		twoFactor.UpdateLastCounter(counter)
+	} else if !auth.VerifyTwoFactor(twoFactor, code) {
+		h.twoFactorLoginFail(ctx, ses, user)
+		return
+	}
+
+	auth.GetLoginLimiter().Reset(user.UserName)
+	h.unlockUser(user.Id)
+
+	ses.Clear()
+	auth.SetCookie(ctx, user, h.conn)
+
+	data := map[string]interface{}{
+		"url": h.config.GetIndexURL(),
+	}
+	if recoveryCodes != nil {
+		data["recovery_codes"] = recoveryCodes
+	}
+
+	response.OkWithData(ctx, data)
+}
+
+// twoFactorLoginFail count the failed attempts of the second step of login
+// for the login limiter and the lockout, and the user has to login again
+// after too many failures.
+func (h *Handler) twoFactorLoginFail(ctx *context.Context, ses *auth.Session, user models.UserModel) {
+	auth.GetLoginLimiter().Fail(auth.ClientIP(ctx), user.UserName)
+	h.countLockout(user.Id)
+	h.recordLoginFailure(ctx, user.Id, user.UserName, "wrong two-factor code")
+	attempts, _ := ses.Get(twoFactorAttemptsSesKey).(float64)
+	if int(attempts)+1 >= twoFactorLoginMaxAttempts {
+		ses.Clear()
+		twoFactorLoginExpired(ctx, h.config.Url("/login"), "too many attempts, please login again")
+		return
+	}
+	ses.Add(twoFactorAttemptsSesKey, attempts+1)
+	response.BadRequest(ctx, "wrong code")
+}
+
+func twoFactorLoginExpired(ctx *context.Context, loginUrl, msg string) {
+	ctx.JSON(http.StatusBadRequest, map[string]interface{}{
+		"code": http.StatusBadRequest,
+		"msg":  language.Get(msg),
+		"data": map[string]interface{}{
+			"url": loginUrl,
+		},
+	})
+}
+
+// pendingTwoFactorSecret return the secret to enroll which is kept in the
+// session until the first code is checked.
+func (h *Handler) pendingTwoFactorSecret(ses *auth.Session) string {
+	secret, _ := ses.Get(twoFactorSecretSesKey).(string)
+	if secret == "" {
+		secret = auth.NewTwoFactorSecret()
+		ses.Add(twoFactorSecretSesKey, secret)
+	}
+	return secret
+}
+
+// twoFactorQRCode return the qr code image of the secret as a data url.
+func twoFactorQRCode(issuer, account, secret string) template2.URL {
+	png, err := qrcode.Encode(auth.TwoFactorURI(issuer, account, secret), qrcode.Medium, 256)
+	if err != nil {
+		logger.Error("two-factor qr code error: ", err)
+		return ""
+	}
+	return template2.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png))
+}
+
+// ShowTwoFactor show the two-factor authentication page of the current user.
+func (h *Handler) ShowTwoFactor(ctx *context.Context) {
+	h.showTwoFactor(ctx, "")
+}
+
+func (h *Handler) showTwoFactor(ctx *context.Context, alert template2.HTML) {
+
+	var (
+		user      = auth.Auth(ctx)
+		twoFactor = models.UserTwoFactor().SetConn(h.conn).FindByUserId(user.Id)
+		content   template2.HTML
+		codeField = types.NewFormPanel().
+				AddField(language.Get("code"), "code", db.Varchar, form.Text).
+				FieldMust().
+				FieldHelpMsg(language.GetFromHtml("enter the code of the authenticator app or a recovery code")).
+				FieldList
+	)
+
+	if twoFactor.IsEmpty() {
+
+		var (
+			secret = h.pendingTwoFactorSecret(auth.InitSession(ctx, h.conn))
+			qrCode = twoFactorQRCode(h.config.Title, user.UserName, secret)
+		)
+
+		content = aBox().
+			SetHeader(language.GetFromHtml("scan the qr code with the authenticator app, or enter the secret: ")).
+			WithHeadBorder().
+			SetBody(template2.HTML(fmt.Sprintf(`<p class="text-center"><img src="%s" width="200" height="200" alt="qr code"></p>`+
+				`<p class="text-center"><code>%s</code></p>`,
+				template2.HTMLEscapeString(string(qrCode)), template2.HTMLEscapeString(secret)))).
+			GetContent() +
+			formContent(aForm().
+				SetPrefix(h.config.PrefixFixSlash()).
+				SetContent(codeField).
+				SetUrl(h.config.Url("/two_factor/enable")).
+				SetPrimaryKey("id").
+				SetHiddenFields(map[string]string{
+					form2.TokenKey:    h.authSrv().AddToken(ctx),
+					form2.PreviousKey: h.config.Url("/two_factor"),
+				}).
+				SetTitle(language.GetFromHtml("enable")).
+				SetOperationFooter(formFooter("")))
+	} else {
+
+		content = aAlert().
+			SetTitle(language.GetFromHtml("two-factor authentication")).
+			SetTheme("success").
+			SetContent(template2.HTML(fmt.Sprintf(language.Get("two-factor authentication is enabled, %d recovery codes left"),
+				len(twoFactor.RecoveryCodes)))).
+			GetContent()
+
+		if user.SetConn(h.conn).IsTwoFactorRequired() {
+			content += aAlert().
+				SetTitle(language.GetFromHtml("two-factor authentication")).
+				SetTheme("info").
+				SetContent(language.GetFromHtml("two-factor authentication is required by your role")).
+				GetContent()
+		} else {
+			content += formContent(aForm().
+				SetPrefix(h.config.PrefixFixSlash()).
+				SetContent(codeField).
+				SetUrl(h.config.Url("/two_factor/disable")).
+				SetPrimaryKey("id").
+				SetHiddenFields(map[string]string{
+					form2.TokenKey:    h.authSrv().AddToken(ctx),
+					form2.PreviousKey: h.config.Url("/two_factor"),
+				}).
+				SetTitle(language.GetFromHtml("disable")).
+				SetOperationFooter(formFooter("")))
+		}
+	}
+
+	h.twoFactorPage(ctx, user, alert+content)
+}
+
+func (h *Handler) twoFactorPage(ctx *context.Context, user models.UserModel, content template2.HTML) {
+	tmpl, tmplName := aTemplate().GetTemplate(isPjax(ctx))
+	buf := template.Execute(tmpl, tmplName, user, types.Panel{
+		Content:     content,
+		Description: language.Get("two-factor authentication"),
+		Title:       language.Get("two-factor authentication"),
+	}, h.config, menu.GetGlobalMenu(user, h.conn).SetActiveClass(h.config.URLRemovePrefix(ctx.Path())))
+	ctx.HTML(http.StatusOK, buf.String())
+}
+
+func twoFactorAlert(msg string) template2.HTML {
+	return aAlert().SetTitle(constant.DefaultErrorMsg).
+		SetTheme("warning").
+		SetContent(template2.HTML(language.Get(msg))).
+		GetContent()
+}
+
+// EnableTwoFactor check the first code of the pending secret and enable the
+// two-factor authentication of the current user, the recovery codes are
+// shown only once.
+func (h *Handler) EnableTwoFactor(ctx *context.Context) {
+
+	var (
+		user      = auth.Auth(ctx)
+		ses       = auth.InitSession(ctx, h.conn)
+		secret, _ = ses.Get(twoFactorSecretSesKey).(string)
+	)
+
+	counter, ok := auth.CheckTwoFactorCode(secret, ctx.FormValue("code"), 0)
+
+	if secret == "" || !ok {
+		h.showTwoFactor(ctx, twoFactorAlert("wrong code"))
+		return
+	}
+
+	twoFactor, recoveryCodes, err := models.UserTwoFactor().SetConn(h.conn).New(user.Id, secret)
+
+	if err != nil {
+		logger.Error(err)
+		h.showTwoFactor(ctx, twoFactorAlert("enable two-factor authentication fail"))
+		return
+	}
+
+	twoFactor.UpdateLastCounter(counter)
+	ses.Delete(twoFactorSecretSesKey)
+
+	codes := template2.HTMLEscapeString(strings.Join(recoveryCodes, "\n"))
+
+	content := aAlert().
+		SetTitle(language.GetFromHtml("two-factor authentication")).
+		SetTheme("success").
+		SetContent(language.GetFromHtml("save the recovery codes, each of them can be used once to login without the authenticator app")).
+		GetContent() +
+		aBox().
+			SetHeader(language.GetFromHtml("recovery codes")).
+			WithHeadBorder().
+			SetBody(template2.HTML("<pre>"+codes+"</pre>")).
+			GetContent()
+
+	h.twoFactorPage(ctx, user, content)
+}
+
+// DisableTwoFactor disable the two-factor authentication of the current user
+// with a code, unless it is required by a role of the user.
+func (h *Handler) DisableTwoFactor(ctx *context.Context) {
+
+	user := auth.Auth(ctx)
+
+	if user.SetConn(h.conn).IsTwoFactorRequired() {
+		h.showTwoFactor(ctx, twoFactorAlert("two-factor authentication is required by your role"))
+		return
+	}
+
+	twoFactor := models.UserTwoFactor().SetConn(h.conn).FindByUserId(user.Id)
+
+	if twoFactor.IsEmpty() || !auth.VerifyTwoFactor(twoFactor, ctx.FormValue("code")) {
+		h.showTwoFactor(ctx, twoFactorAlert("wrong code"))
+		return
+	}
+
+	if err := twoFactor.DeleteByUserId(user.Id); err != nil {
+		logger.Error(err)
+		h.showTwoFactor(ctx, twoFactorAlert("disable two-factor authentication fail"))
+		return
+	}
+
+	h.showTwoFactor(ctx, "")
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	dbsql "database/sql"
+	"fmt"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
 	"strconv"
@@ -105,34 +107,76 @@ func (t MenuModel) Update(title, icon, uri, header string, parentId int64) MenuM
 	return t
 }
 
-// ResetOrder update the order of menu models.
-func (t MenuModel) ResetOrder(data []map[string]interface{}) {
-	count := 1
-	for _, v := range data {
-		if child, ok := v["children"]; ok {
-			_, _ = t.Table(t.TableName).
-				Where("id", "=", v["id"]).Update(dialect.H{
-				"order":     count,
-				"parent_id": 0,
-			})
-
-			for _, v2 := range child.([]interface{}) {
-				_, _ = t.Table(t.TableName).
-					Where("id", "=", v2.(map[string]interface{})["id"]).Update(dialect.H{
-					"order":     count,
-					"parent_id": v["id"],
-				})
-				count++
+// ResetOrder update the order and the parents of menu models of the
+// nested list, in which the children of an item are in its "children".
+// The items are ordered as they are listed, depth first, and an item
+// listed twice is only moved at the first time, so the parents never form
+// a cycle. The items are all moved in a transaction, so either all or
+// none of them are changed.
+func (t MenuModel) ResetOrder(data []map[string]interface{}) error {
+	items := make([]interface{}, len(data))
+	for i, v := range data {
+		items[i] = v
+	}
+	_, err := db.WithDriver(t.Conn).WithTransaction(func(tx *dbsql.Tx) (error, map[string]interface{}) {
+		count := 1
+		return t.resetOrder(tx, items, 0, &count, make(map[string]bool)), nil
+	})
+	return err
+}
+
+func (t MenuModel) resetOrder(tx *dbsql.Tx, items []interface{}, parentId interface{}, count *int, moved map[string]bool) error {
+	for _, item := range items {
+		v, ok := item.(map[string]interface{})
+		if !ok || v["id"] == nil || moved[fmt.Sprint(v["id"])] {
+			continue
+		}
+		moved[fmt.Sprint(v["id"])] = true
+
+		_, err := db.WithDriver(t.Conn).WithTx(tx).Table(t.TableName).
+			Where("id", "=", v["id"]).Update(dialect.H{
+			"order":     *count,
+			"parent_id": parentId,
+		})
+		if err != nil && err.Error() != "no affect row" {
+			return err
+		}
+		*count++
+
+		if children, ok := v["children"].([]interface{}); ok {
+			if err := t.resetOrder(tx, children, v["id"], count, moved); err != nil {
+				return err
 			}
-		} else {
-			_, _ = t.Table(t.TableName).
-				Where("id", "=", v["id"]).Update(dialect.H{
-				"order":     count,
-				"parent_id": 0,
-			})
-			count++
 		}
 	}
+	return nil
+}
+
+// CheckParent check the menu can be put under the menu of given id, which
+// must exist and be neither the menu itself nor any descendant of it.
+// The root 0 is always a valid parent.
+func (t MenuModel) CheckParent(parentId int64) bool {
+	if parentId == 0 {
+		return true
+	}
+
+	items, _ := t.Table(t.TableName).Select("id", "parent_id").All()
+
+	parents := make(map[int64]int64, len(items))
+	for _, item := range items {
+		parents[toInt64(item["id"])] = toInt64(item["parent_id"])
+	}
+
+	// the ancestors of the parent are walked up to the root, which are
+	// not more than all the menus unless they are in a cycle already.
+	for id, depth := parentId, 0; id != 0; depth++ {
+		parent, ok := parents[id]
+		if !ok || id == t.Id || depth > len(parents) {
+			return false
+		}
+		id = parent
+	}
+	return true
 }
 
 // CheckRole check the role if has permission to get the menu.
@@ -176,3 +220,25 @@ func (t MenuModel) MapToModel(m map[string]interface{}) MenuModel {
 	t.UpdatedAt, _ = m["updated_at"].(string)
 	return t
 }
+
+// toInt64 convert the scanned integer of the drivers to int64, which is
+// the string of the number for some drivers.
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case []byte:
+		id, _ := strconv.ParseInt(string(v), 10, 64)
+		return id
+	case string:
+		id, _ := strconv.ParseInt(v, 10, 64)
+		return id
+	}
+	return 0
+}
@@ -15,6 +15,10 @@ type OperationLogModel struct {
 	Method    string
 	Ip        string
 	Input     string
+	Prefix    string
+	Pk        string
+	Op        string
+	Diff      string
 	CreatedAt string
 	UpdatedAt string
 }
@@ -56,6 +60,36 @@ func (t OperationLogModel) New(userId int64, path, method, ip, input string) Ope
 	return t
 }
 
+// NewAudit create a new operation log model of a table writing, which
+// has the table prefix, primary key, operation type and the changes.
+func (t OperationLogModel) NewAudit(userId int64, path, method, ip, input, prefix, pk, op, diff string) OperationLogModel {
+
+	id, _ := t.Table(t.TableName).Insert(dialect.H{
+		"user_id": userId,
+		"path":    path,
+		"method":  method,
+		"ip":      ip,
+		"input":   input,
+		"prefix":  prefix,
+		"pk":      pk,
+		"op":      op,
+		"diff":    diff,
+	})
+
+	t.Id = id
+	t.UserId = userId
+	t.Path = path
+	t.Method = method
+	t.Ip = ip
+	t.Input = input
+	t.Prefix = prefix
+	t.Pk = pk
+	t.Op = op
+	t.Diff = diff
+
+	return t
+}
+
 // MapToModel get the operation log model from given map.
 func (t OperationLogModel) MapToModel(m map[string]interface{}) OperationLogModel {
 	t.Id = m["id"].(int64)
@@ -64,6 +98,10 @@ func (t OperationLogModel) MapToModel(m map[string]interface{}) OperationLogMode
 	t.Method, _ = m["method"].(string)
 	t.Ip, _ = m["ip"].(string)
 	t.Input, _ = m["input"].(string)
+	t.Prefix, _ = m["prefix"].(string)
+	t.Pk, _ = m["pk"].(string)
+	t.Op, _ = m["op"].(string)
+	t.Diff, _ = m["diff"].(string)
 	t.CreatedAt, _ = m["created_at"].(string)
 	t.UpdatedAt, _ = m["updated_at"].(string)
 	return t
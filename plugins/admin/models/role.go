@@ -16,6 +16,8 @@ type RoleModel struct {
 	Slug      string
 	CreatedAt string
 	UpdatedAt string
+
+	TwoFactorRequired bool
 }
 
 // Role return a default role model.
@@ -85,6 +87,26 @@ func (t RoleModel) Update(name, slug string) RoleModel {
 	return t
 }
 
+// SetTwoFactorRequired set the role requires the two-factor authentication
+// of its users or not.
+func (t RoleModel) SetTwoFactorRequired(required bool) RoleModel {
+
+	value := 0
+	if required {
+		value = 1
+	}
+
+	_, _ = t.Table(t.TableName).
+		Where("id", "=", t.Id).
+		Update(dialect.H{
+			"two_factor_required": value,
+		})
+
+	t.TwoFactorRequired = required
+
+	return t
+}
+
 // CheckPermission check the permission of role.
 func (t RoleModel) CheckPermission(permissionId string) bool {
 	checkPermission, _ := t.Table("goadmin_role_permissions").
@@ -121,5 +143,7 @@ func (t RoleModel) MapToModel(m map[string]interface{}) RoleModel {
 	t.Slug, _ = m["slug"].(string)
 	t.CreatedAt, _ = m["created_at"].(string)
 	t.UpdatedAt, _ = m["updated_at"].(string)
+	required, _ := m["two_factor_required"].(int64)
+	t.TwoFactorRequired = required == 1
 	return t
 }
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"strings"
+)
+
+// SavedViewModel is the saved view model structure of a list. Params are
+// the route parameters of the view, which are the filters, the sort, the
+// visible columns and the page size. A view of a role is shared with all
+// the users of the role, and the one of no role is private.
+type SavedViewModel struct {
+	Base
+
+	Id        int64
+	UserId    int64
+	RoleId    int64
+	Prefix    string
+	Name      string
+	Params    string
+	CreatedAt string
+	UpdatedAt string
+}
+
+// SavedView return a default saved view model.
+func SavedView() SavedViewModel {
+	return SavedViewModel{Base: Base{TableName: "goadmin_saved_views"}}
+}
+
+func (t SavedViewModel) SetConn(con db.Connection) SavedViewModel {
+	t.Conn = con
+	return t
+}
+
+// Find return a default saved view model of given id.
+func (t SavedViewModel) Find(id interface{}) SavedViewModel {
+	item, _ := t.Table(t.TableName).Find(id)
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the saved view model is empty or not.
+func (t SavedViewModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// List return the views of the list of given prefix which are saved by the
+// user or shared with the roles of the user, ordered by the name.
+func (t SavedViewModel) List(prefix string, user UserModel) []SavedViewModel {
+
+	var (
+		cond = "(user_id = ? and role_id = 0)"
+		args = []interface{}{user.Id}
+	)
+
+	if len(user.Roles) > 0 {
+		cond = "(" + cond + " or role_id in (" + strings.Repeat("?,", len(user.Roles)-1) + "?))"
+		for _, role := range user.Roles {
+			args = append(args, role.Id)
+		}
+	}
+
+	items, _ := t.Table(t.TableName).
+		Where("prefix", "=", prefix).
+		WhereRaw(cond, args...).
+		OrderBy("name", "asc").
+		All()
+
+	views := make([]SavedViewModel, len(items))
+	for i, item := range items {
+		views[i] = t.MapToModel(item)
+	}
+	return views
+}
+
+// New create a view of the list of given prefix saved by the user, which
+// is shared with the role if roleId is not zero.
+func (t SavedViewModel) New(userId, roleId int64, prefix, name, params string) (SavedViewModel, error) {
+
+	id, err := t.Table(t.TableName).Insert(dialect.H{
+		"user_id": userId,
+		"role_id": roleId,
+		"prefix":  prefix,
+		"name":    name,
+		"params":  params,
+	})
+
+	t.Id = id
+	t.UserId = userId
+	t.RoleId = roleId
+	t.Prefix = prefix
+	t.Name = name
+	t.Params = params
+
+	return t, err
+}
+
+// Delete delete the saved view.
+func (t SavedViewModel) Delete() error {
+	return t.Table(t.TableName).Where("id", "=", t.Id).Delete()
+}
+
+// MapToModel get the saved view model from given map.
+func (t SavedViewModel) MapToModel(m map[string]interface{}) SavedViewModel {
+	t.Id, _ = m["id"].(int64)
+	t.UserId, _ = m["user_id"].(int64)
+	t.RoleId, _ = m["role_id"].(int64)
+	t.Prefix, _ = m["prefix"].(string)
+	t.Name, _ = m["name"].(string)
+	t.Params, _ = m["params"].(string)
+	t.CreatedAt, _ = m["created_at"].(string)
+	t.UpdatedAt, _ = m["updated_at"].(string)
+	return t
+}
@@ -107,6 +107,13 @@ func (t UserModel) CheckPermissionByUrlMethod(path, method string, formParams ur
 	path = strings.Replace(path, constant.DetailPKKey, "id", -1)
 
 	path, params := getParam(path)
+
+	// every user can manage their own two-factor authentication.
+	if twoFactorPath := config.Get().Url("/two_factor"); path == twoFactorPath ||
+		strings.HasPrefix(path, twoFactorPath+"/") {
+		return true
+	}
+
 	for key, value := range formParams {
 		if len(value) > 0 {
 			params.Add(key, value[0])
@@ -230,6 +237,17 @@ func (t UserModel) WithRoles() UserModel {
 	return t
 }
 
+// IsTwoFactorRequired check the user has a role which requires the
+// two-factor authentication.
+func (t UserModel) IsTwoFactorRequired() bool {
+	role, _ := t.Table("goadmin_role_users").
+		LeftJoin("goadmin_roles", "goadmin_roles.id", "=", "goadmin_role_users.role_id").
+		Where("user_id", "=", t.Id).
+		Where("goadmin_roles.two_factor_required", "=", 1).
+		First()
+	return role != nil
+}
+
 func (t UserModel) GetAllRoleId() []interface{} {
 
 	var ids = make([]interface{}, len(t.Roles))
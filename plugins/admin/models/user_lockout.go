@@ -0,0 +1,94 @@
+package models
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"time"
+)
+
+// UserLockoutModel is the lockout model structure of a user. Failures is
+// the number of the failed logins in a row, and LockedUntil is the unix
+// time until which the user can not login.
+type UserLockoutModel struct {
+	Base
+
+	Id          int64
+	UserId      int64
+	Failures    int64
+	LockedUntil int64
+	CreatedAt   string
+	UpdatedAt   string
+}
+
+// UserLockout return a default user lockout model.
+func UserLockout() UserLockoutModel {
+	return UserLockoutModel{Base: Base{TableName: "goadmin_user_lockouts"}}
+}
+
+func (t UserLockoutModel) SetConn(con db.Connection) UserLockoutModel {
+	t.Conn = con
+	return t
+}
+
+// FindByUserId return the user lockout model of given user id.
+func (t UserLockoutModel) FindByUserId(userId interface{}) UserLockoutModel {
+	item, _ := t.Table(t.TableName).Where("user_id", "=", userId).First()
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the user lockout model is empty or not.
+func (t UserLockoutModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// IsLocked check the user is locked now or not.
+func (t UserLockoutModel) IsLocked() bool {
+	return t.LockedUntil > time.Now().Unix()
+}
+
+// Fail count a failed login of the user, the user is locked for given
+// duration once the failures reach maxFailures.
+func (t UserLockoutModel) Fail(userId int64, maxFailures int, duration time.Duration) (UserLockoutModel, error) {
+
+	t.UserId = userId
+	t.Failures++
+	if t.Failures >= int64(maxFailures) {
+		t.Failures = 0
+		t.LockedUntil = time.Now().Add(duration).Unix()
+	}
+
+	if t.IsEmpty() {
+		id, err := t.Table(t.TableName).Insert(dialect.H{
+			"user_id":      t.UserId,
+			"failures":     t.Failures,
+			"locked_until": t.LockedUntil,
+		})
+		t.Id = id
+		return t, err
+	}
+
+	_, err := t.Table(t.TableName).
+		Where("id", "=", t.Id).
+		Update(dialect.H{
+			"failures":     t.Failures,
+			"locked_until": t.LockedUntil,
+			"updated_at":   time.Now().Format("2006-01-02 15:04:05"),
+		})
+	return t, err
+}
+
+// DeleteByUserId clear the failures and unlock given users.
+func (t UserLockoutModel) DeleteByUserId(userIds ...interface{}) error {
+	return t.Table(t.TableName).WhereIn("user_id", userIds).Delete()
+}
+
+// MapToModel get the user lockout model from given map.
+func (t UserLockoutModel) MapToModel(m map[string]interface{}) UserLockoutModel {
+	t.Id, _ = m["id"].(int64)
+	t.UserId, _ = m["user_id"].(int64)
+	t.Failures, _ = m["failures"].(int64)
+	t.LockedUntil, _ = m["locked_until"].(int64)
+	t.CreatedAt, _ = m["created_at"].(string)
+	t.UpdatedAt, _ = m["updated_at"].(string)
+	return t
+}
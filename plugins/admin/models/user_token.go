@@ -0,0 +1,148 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"strconv"
+	"time"
+)
+
+// userTokenUsedInterval is the interval of updating the last used time of
+// a user token, which saves a writing for every api request.
+const userTokenUsedInterval = time.Minute
+
+// userTokenKey signs the tokens generated by the server, so that a posted
+// token can be checked to be one of them.
+var userTokenKey = randomBytes(32)
+
+// UserTokenModel is personal api token model structure. Only the hash of
+// the token is stored.
+type UserTokenModel struct {
+	Base
+
+	Id         int64
+	UserId     int64
+	Name       string
+	Token      string
+	LastUsedAt string
+	CreatedAt  string
+	UpdatedAt  string
+}
+
+// UserToken return a default user token model.
+func UserToken() UserTokenModel {
+	return UserTokenModel{Base: Base{TableName: "goadmin_user_tokens"}}
+}
+
+func (t UserTokenModel) SetConn(con db.Connection) UserTokenModel {
+	t.Conn = con
+	return t
+}
+
+// Find return a default user token model of given id.
+func (t UserTokenModel) Find(id interface{}) UserTokenModel {
+	item, _ := t.Table(t.TableName).Find(id)
+	return t.MapToModel(item)
+}
+
+// FindByToken return the user token model of given plain token.
+func (t UserTokenModel) FindByToken(token string) UserTokenModel {
+	item, _ := t.Table(t.TableName).Where("token", "=", HashUserToken(token)).First()
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the user token model is empty or not.
+func (t UserTokenModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// New create a user token of given user and return the model with
+// the plain token, which can not be found again.
+func (t UserTokenModel) New(userId int64, name string) (UserTokenModel, string) {
+
+	token := NewUserToken()
+
+	id, _ := t.Table(t.TableName).Insert(dialect.H{
+		"user_id": userId,
+		"name":    name,
+		"token":   HashUserToken(token),
+	})
+
+	t.Id = id
+	t.UserId = userId
+	t.Name = name
+	t.Token = HashUserToken(token)
+
+	return t, token
+}
+
+// Used update the last used time of the user token, at most once in the
+// userTokenUsedInterval.
+func (t UserTokenModel) Used() {
+	now := time.Now()
+	if last, err := time.ParseInLocation("2006-01-02 15:04:05", t.LastUsedAt, time.Local); err == nil &&
+		now.Sub(last) < userTokenUsedInterval {
+		return
+	}
+	_, _ = t.Table(t.TableName).
+		Where("id", "=", t.Id).
+		WhereRaw("(last_used_at is null or last_used_at < ?)",
+			now.Add(-userTokenUsedInterval).Format("2006-01-02 15:04:05")).
+		Update(dialect.H{
+			"last_used_at": now.Format("2006-01-02 15:04:05"),
+		})
+}
+
+// Revoke delete the user token.
+func (t UserTokenModel) Revoke() error {
+	return t.Table(t.TableName).Where("id", "=", t.Id).Delete()
+}
+
+// MapToModel get the user token model from given map.
+func (t UserTokenModel) MapToModel(m map[string]interface{}) UserTokenModel {
+	t.Id, _ = m["id"].(int64)
+	t.UserId, _ = m["user_id"].(int64)
+	t.Name, _ = m["name"].(string)
+	t.Token, _ = m["token"].(string)
+	t.LastUsedAt, _ = m["last_used_at"].(string)
+	t.CreatedAt, _ = m["created_at"].(string)
+	t.UpdatedAt, _ = m["updated_at"].(string)
+	return t
+}
+
+// NewUserToken return a new random plain token.
+func NewUserToken() string {
+	return hex.EncodeToString(randomBytes(20))
+}
+
+// SignUserToken return the signature of the token generated by the server
+// for the user of given id.
+func SignUserToken(token string, userId int64) string {
+	mac := hmac.New(sha256.New, userTokenKey)
+	_, _ = mac.Write([]byte(token + ":" + strconv.FormatInt(userId, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CheckUserToken check the token is generated by the server for the user
+// of given id.
+func CheckUserToken(token, sign string, userId int64) bool {
+	return token != "" && hmac.Equal([]byte(SignUserToken(token, userId)), []byte(sign))
+}
+
+// HashUserToken return the hash of the plain token which is stored.
+func HashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"strings"
+	"time"
+)
+
+// RecoveryCodeCount is the number of the recovery codes generated when
+// the two-factor authentication is enabled.
+const RecoveryCodeCount = 10
+
+// UserTwoFactorModel is the two-factor authentication model structure of
+// a user. Secret is the secret of the time-based one-time passwords, and
+// only the hashes of the recovery codes are stored.
+type UserTwoFactorModel struct {
+	Base
+
+	Id            int64
+	UserId        int64
+	Secret        string
+	RecoveryCodes []string
+	LastCounter   int64
+	CreatedAt     string
+	UpdatedAt     string
+}
+
+// UserTwoFactor return a default user two-factor model.
+func UserTwoFactor() UserTwoFactorModel {
+	return UserTwoFactorModel{Base: Base{TableName: "goadmin_user_two_factors"}}
+}
+
+func (t UserTwoFactorModel) SetConn(con db.Connection) UserTwoFactorModel {
+	t.Conn = con
+	return t
+}
+
+// FindByUserId return the user two-factor model of given user id.
+func (t UserTwoFactorModel) FindByUserId(userId interface{}) UserTwoFactorModel {
+	item, _ := t.Table(t.TableName).Where("user_id", "=", userId).First()
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the user two-factor model is empty or not.
+func (t UserTwoFactorModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// New enable the two-factor authentication of the user with given secret
+// and return the model with the plain recovery codes, which can not be
+// found again.
+func (t UserTwoFactorModel) New(userId int64, secret string) (UserTwoFactorModel, []string, error) {
+
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		codes[i] = NewRecoveryCode()
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+
+	err := t.Table(t.TableName).Where("user_id", "=", userId).Delete()
+	if err != nil && err.Error() != "no affect row" {
+		return t, nil, err
+	}
+
+	id, err := t.Table(t.TableName).Insert(dialect.H{
+		"user_id":        userId,
+		"secret":         secret,
+		"recovery_codes": strings.Join(hashes, ","),
+	})
+
+	if err != nil {
+		return t, nil, err
+	}
+
+	t.Id = id
+	t.UserId = userId
+	t.Secret = secret
+	t.RecoveryCodes = hashes
+
+	return t, codes, nil
+}
+
+// UpdateLastCounter record the counter of the last used one-time password,
+// it returns false if a password of the counter or later has been used.
+func (t UserTwoFactorModel) UpdateLastCounter(counter int64) bool {
+	// no row is affected if the counter has been used, and an error of no
+	// affect row is returned.
+	_, err := t.Table(t.TableName).
+		Where("id", "=", t.Id).
+		Where("last_counter", "<", counter).
+		Update(dialect.H{
+			"last_counter": counter,
+			"updated_at":   time.Now().Format("2006-01-02 15:04:05"),
+		})
+	return err == nil
+}
+
+// UseRecoveryCode check the recovery code and remove it once it is used.
+func (t UserTwoFactorModel) UseRecoveryCode(code string) bool {
+	hash := HashRecoveryCode(code)
+	for i, h := range t.RecoveryCodes {
+		if h != hash {
+			continue
+		}
+		rest := append(append([]string{}, t.RecoveryCodes[:i]...), t.RecoveryCodes[i+1:]...)
+		// the codes are compared so that a code can not be used twice by
+		// the concurrent requests.
+		_, err := t.Table(t.TableName).
+			Where("id", "=", t.Id).
+			Where("recovery_codes", "=", strings.Join(t.RecoveryCodes, ",")).
+			Update(dialect.H{
+				"recovery_codes": strings.Join(rest, ","),
+				"updated_at":     time.Now().Format("2006-01-02 15:04:05"),
+			})
+		return err == nil
+	}
+	return false
+}
+
+// DeleteByUserId disable the two-factor authentication of given users.
+func (t UserTwoFactorModel) DeleteByUserId(userIds ...interface{}) error {
+	return t.Table(t.TableName).WhereIn("user_id", userIds).Delete()
+}
+
+// MapToModel get the user two-factor model from given map.
+func (t UserTwoFactorModel) MapToModel(m map[string]interface{}) UserTwoFactorModel {
+	t.Id, _ = m["id"].(int64)
+	t.UserId, _ = m["user_id"].(int64)
+	t.Secret, _ = m["secret"].(string)
+	t.LastCounter, _ = m["last_counter"].(int64)
+	t.CreatedAt, _ = m["created_at"].(string)
+	t.UpdatedAt, _ = m["updated_at"].(string)
+	t.RecoveryCodes = make([]string, 0)
+	if codes, _ := m["recovery_codes"].(string); codes != "" {
+		t.RecoveryCodes = strings.Split(codes, ",")
+	}
+	return t
+}
+
+// NewRecoveryCode return a new random plain recovery code.
+func NewRecoveryCode() string {
+	b := make([]byte, 5)
+	_, _ = rand.Read(b)
+	code := hex.EncodeToString(b)
+	return code[:5] + "-" + code[5:]
+}
+
+// HashRecoveryCode return the hash of the plain recovery code which is stored.
+func HashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
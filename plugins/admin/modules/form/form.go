@@ -1,14 +1,18 @@
 package form
 
 const (
-	PostTypeKey           = "__go_admin_post_type"
-	PostIsSingleUpdateKey = "__go_admin_is_single_update"
+	PostTypeKey            = "__go_admin_post_type"
+	PostIsSingleUpdateKey  = "__go_admin_is_single_update"
+	PostIsPartialUpdateKey = "__go_admin_is_partial_update"
 
 	PreviousKey = "__go_admin_previous_"
 	TokenKey    = "__go_admin_t_"
 	MethodKey   = "__go_admin_method_"
 
 	NoAnimationKey = "__go_admin_no_animation_"
+
+	ImportFileKey = "__go_admin_import_file_"
+	ImportRowsKey = "__go_admin_import_rows_"
 )
 
 // Values maps a string key to a list of values.
@@ -71,8 +75,47 @@ func (f Values) IsSingleUpdatePost() bool {
 	return f.Get(PostIsSingleUpdateKey) == "1"
 }
 
+// IsPartialUpdatePost check the values are only the changed ones of the
+// row, such as the ones of an inline edit or a json api update, so that
+// the fields not posted are left as they are.
+func (f Values) IsPartialUpdatePost() bool {
+	return f.IsSingleUpdatePost() || f.Get(PostIsPartialUpdateKey) == "1"
+}
+
 func (f Values) RemoveRemark() Values {
 	f.Delete(PostTypeKey)
 	f.Delete(PostIsSingleUpdateKey)
+	f.Delete(PostIsPartialUpdateKey)
 	return f
 }
+
+// FieldError is the validation error of a single form field. Validators
+// can return it, or FieldErrors, to tell which fields are wrong, and the
+// json api will respond them as structured errors.
+type FieldError struct {
+	Field string `json:"field"`
+	Msg   string `json:"msg"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Msg
+}
+
+// FieldErrors is a list of FieldError.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	msg := ""
+	for i, err := range e {
+		if i > 0 {
+			msg += "; "
+		}
+		msg += err.Error()
+	}
+	return msg
+}
+
+// Add adds a FieldError of the given field.
+func (e FieldErrors) Add(field, msg string) FieldErrors {
+	return append(e, FieldError{Field: field, Msg: msg})
+}
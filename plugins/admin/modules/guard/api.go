@@ -0,0 +1,297 @@
+package guard
+
+import (
+	"encoding/json"
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func (g *Guard) CheckApiPrefix(ctx *context.Context) {
+
+	prefix := ctx.Query(constant.PrefixKey)
+
+	if _, ok := g.tableList[prefix]; !ok {
+		response.NotFound(ctx, "table model not found")
+		ctx.Abort()
+		return
+	}
+
+	ctx.Next()
+}
+
+type ApiListParam struct {
+	Panel  table.Table
+	Prefix string
+	Param  parameter.Parameters
+}
+
+func (g *Guard) ApiList(ctx *context.Context) {
+	panel, prefix := g.table(ctx)
+
+	ctx.SetUserValue("api_list_param", &ApiListParam{
+		Panel:  panel,
+		Prefix: prefix,
+		Param: parameter.GetParam(ctx.Request.URL, panel.GetInfo().DefaultPageSize, panel.GetInfo().SortField,
+			panel.GetInfo().GetSort()),
+	})
+	ctx.Next()
+}
+
+func GetApiListParam(ctx *context.Context) *ApiListParam {
+	return ctx.UserValue["api_list_param"].(*ApiListParam)
+}
+
+type ApiDetailParam struct {
+	Panel  table.Table
+	Id     string
+	Prefix string
+	Param  parameter.Parameters
+}
+
+func (g *Guard) ApiDetail(ctx *context.Context) {
+	panel, prefix := g.table(ctx)
+
+	id := ctx.Query(constant.DetailPKKey)
+	if id == "" {
+		response.BadRequest(ctx, "wrong "+panel.GetPrimaryKey().Name)
+		ctx.Abort()
+		return
+	}
+
+	ctx.SetUserValue("api_detail_param", &ApiDetailParam{
+		Panel:  panel,
+		Id:     id,
+		Prefix: prefix,
+		Param: parameter.GetParam(ctx.Request.URL, panel.GetInfo().DefaultPageSize, panel.GetInfo().SortField,
+			panel.GetInfo().GetSort()).WithPKs(id),
+	})
+	ctx.Next()
+}
+
+func GetApiDetailParam(ctx *context.Context) *ApiDetailParam {
+	return ctx.UserValue["api_detail_param"].(*ApiDetailParam)
+}
+
+type ApiFormParam struct {
+	Panel     table.Table
+	Id        string
+	Prefix    string
+	Values    form.Values
+	MultiForm *multipart.Form
+}
+
+func (e ApiFormParam) HasFile() bool {
+	return e.MultiForm != nil && len(e.MultiForm.File) > 0
+}
+
+func (g *Guard) ApiCreate(ctx *context.Context) {
+	panel, prefix := g.table(ctx)
+
+	if !panel.GetCanAdd() {
+		response.Forbidden(ctx, "operation not allow")
+		ctx.Abort()
+		return
+	}
+
+	values, err := apiFormValues(ctx)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		ctx.Abort()
+		return
+	}
+
+	var errs form.FieldErrors
+	for _, field := range panel.GetForm().FieldList {
+		if field.Must && !field.NotAllowAdd && values.IsEmpty(field.Field) && values.IsEmpty(field.Field+"[]") {
+			errs = errs.Add(field.Field, "required")
+		}
+	}
+
+	if len(errs) > 0 {
+		response.ValidationError(ctx, errs)
+		ctx.Abort()
+		return
+	}
+
+	ctx.SetUserValue("api_create_param", &ApiFormParam{
+		Panel:     panel,
+		Prefix:    prefix,
+		Values:    values,
+		MultiForm: ctx.Request.MultipartForm,
+	})
+	ctx.Next()
+}
+
+func GetApiCreateParam(ctx *context.Context) *ApiFormParam {
+	return ctx.UserValue["api_create_param"].(*ApiFormParam)
+}
+
+func (g *Guard) ApiUpdate(ctx *context.Context) {
+	panel, prefix := g.table(ctx)
+
+	if !panel.GetEditable() {
+		response.Forbidden(ctx, "operation not allow")
+		ctx.Abort()
+		return
+	}
+
+	values, err := apiFormValues(ctx)
+	if err != nil {
+		response.BadRequest(ctx, err.Error())
+		ctx.Abort()
+		return
+	}
+
+	pk := panel.GetPrimaryKey().Name
+
+	if values.IsEmpty(pk) {
+		values.Add(pk, ctx.Query(constant.EditPKKey))
+	}
+
+	id := values.Get(pk)
+	if id == "" {
+		response.ValidationError(ctx, form.FieldErrors{}.Add(pk, "required"))
+		ctx.Abort()
+		return
+	}
+
+	var errs form.FieldErrors
+	for _, field := range panel.GetForm().FieldList {
+		if _, ok := values[field.Field]; ok && field.Must && field.Editable && values.IsEmpty(field.Field) {
+			errs = errs.Add(field.Field, "required")
+		}
+	}
+
+	if len(errs) > 0 {
+		response.ValidationError(ctx, errs)
+		ctx.Abort()
+		return
+	}
+
+	// only the posted fields of the row are updated.
+	values.Add(form.PostIsPartialUpdateKey, "1")
+
+	ctx.SetUserValue("api_update_param", &ApiFormParam{
+		Panel:     panel,
+		Id:        id,
+		Prefix:    prefix,
+		Values:    values,
+		MultiForm: ctx.Request.MultipartForm,
+	})
+	ctx.Next()
+}
+
+func GetApiUpdateParam(ctx *context.Context) *ApiFormParam {
+	return ctx.UserValue["api_update_param"].(*ApiFormParam)
+}
+
+func (g *Guard) ApiDelete(ctx *context.Context) {
+	panel, prefix := g.table(ctx)
+
+	if !panel.GetDeletable() {
+		response.Forbidden(ctx, "operation not allow")
+		ctx.Abort()
+		return
+	}
+
+	id := ctx.FormValue("id")
+	if id == "" {
+		values, err := apiFormValues(ctx)
+		if err != nil {
+			response.BadRequest(ctx, err.Error())
+			ctx.Abort()
+			return
+		}
+		id = strings.Join(values["id[]"], ",")
+		if id == "" {
+			id = values.Get("id")
+		}
+	}
+
+	if id == "" {
+		response.BadRequest(ctx, "wrong id")
+		ctx.Abort()
+		return
+	}
+
+	ctx.SetUserValue("api_delete_param", &DeleteParam{
+		Panel:  panel,
+		Id:     id,
+		Prefix: prefix,
+	})
+	ctx.Next()
+}
+
+func GetApiDeleteParam(ctx *context.Context) *DeleteParam {
+	return ctx.UserValue["api_delete_param"].(*DeleteParam)
+}
+
+// apiFormValues read the posted values from a json body or a form body.
+// The array values of a json body are put into the key with a "[]" suffix,
+// which is the same as a form posted by the pages.
+func apiFormValues(ctx *context.Context) (form.Values, error) {
+
+	if !strings.Contains(ctx.Headers("Content-Type"), "application/json") {
+		postForm := ctx.PostForm()
+		if ctx.Request.MultipartForm != nil {
+			return ctx.Request.MultipartForm.Value, nil
+		}
+		return form.Values(postForm), nil
+	}
+
+	var (
+		body   = make(map[string]interface{})
+		values = make(form.Values)
+	)
+
+	if ctx.Request.Body == nil || ctx.Request.Body == http.NoBody {
+		return values, nil
+	}
+
+	if err := ctx.BindJSON(&body); err != nil {
+		return nil, err
+	}
+
+	for key, value := range body {
+		switch v := value.(type) {
+		case nil:
+			values[key] = []string{""}
+		case []interface{}:
+			if !strings.HasSuffix(key, "[]") {
+				key += "[]"
+			}
+			values[key] = make([]string, len(v))
+			for i := 0; i < len(v); i++ {
+				values[key][i] = apiValueString(v[i])
+			}
+		default:
+			values[key] = []string{apiValueString(v)}
+		}
+	}
+
+	return values, nil
+}
+
+func apiValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
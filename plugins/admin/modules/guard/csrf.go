@@ -0,0 +1,56 @@
+package guard
+
+import (
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"net/http"
+	"strings"
+)
+
+// CheckToken is the csrf middleware of the routes of the login users. The
+// requests which may change the data must carry a token of the session,
+// either in the form or in the header, and the requests of the personal api
+// tokens are not checked as they are not sent by the browsers. The other
+// requests keep the token cookie of the session valid for the scripts.
+func (g *Guard) CheckToken(ctx *context.Context) {
+
+	srv := auth.GetTokenService(g.services.Get(auth.TokenServiceKey))
+
+	switch ctx.Method() {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		if auth.BearerToken(ctx) == "" {
+			srv.SetTokenCookie(ctx)
+		}
+		ctx.Next()
+		return
+	}
+
+	if auth.BearerToken(ctx) != "" {
+		ctx.Next()
+		return
+	}
+
+	token := ctx.Headers(constant.CSRFHeader)
+	if token == "" {
+		token = ctx.FormValue(form.TokenKey)
+	}
+
+	if srv.CheckToken(ctx, token) {
+		ctx.Next()
+		return
+	}
+
+	// the forms are posted with pjax or by the browser, which show the page
+	// of the alert, and the ajax requests get the json.
+	if strings.Contains(ctx.Headers("Accept"), "text/html") {
+		msg := language.Get("wrong token")
+		alertWithTitleAndDesc(ctx, language.Get("error"), msg, msg, g.conn)
+	} else {
+		response.Forbidden(ctx, "wrong token")
+	}
+	ctx.Abort()
+}
@@ -2,6 +2,8 @@ package guard
 
 import (
 	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
 	"strings"
 )
@@ -11,6 +13,7 @@ type ExportParam struct {
 	Id     []string
 	Prefix string
 	IsAll  bool
+	Format string
 }
 
 func (g *Guard) Export(ctx *context.Context) {
@@ -21,6 +24,15 @@ func (g *Guard) Export(ctx *context.Context) {
 		return
 	}
 
+	format := ctx.FormValue(constant.ExportFormatKey)
+	if format == "" {
+		format = panel.GetInfo().GetExportFormats()[0]
+	} else if !modules.InArray(panel.GetInfo().GetExportFormats(), format) {
+		alert(ctx, panel, "wrong export format", g.conn)
+		ctx.Abort()
+		return
+	}
+
 	idStr := make([]string, 0)
 	ids := ctx.FormValue("id")
 	if ids != "" {
@@ -32,6 +44,7 @@ func (g *Guard) Export(ctx *context.Context) {
 		Id:     idStr,
 		Prefix: prefix,
 		IsAll:  ctx.FormValue("is_all") == "true",
+		Format: format,
 	})
 	ctx.Next()
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
@@ -27,7 +28,12 @@ func New(s service.List, c db.Connection, t table.GeneratorList) *Guard {
 
 func (g *Guard) table(ctx *context.Context) (table.Table, string) {
 	prefix := ctx.Query(constant.PrefixKey)
-	return g.tableList[prefix](ctx), prefix
+	panel := g.tableList[prefix](ctx)
+	panel.SetAuditor(table.ContextAuditor(ctx, prefix))
+	if user, ok := ctx.User().(models.UserModel); ok {
+		panel.SetUser(user)
+	}
+	return panel, prefix
 }
 
 func (g *Guard) CheckPrefix(ctx *context.Context) {
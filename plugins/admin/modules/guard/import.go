@@ -0,0 +1,135 @@
+package guard
+
+import (
+	"encoding/json"
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"html/template"
+)
+
+type ShowImportParam struct {
+	Panel  table.Table
+	Prefix string
+}
+
+func (g *Guard) ShowImport(ctx *context.Context) {
+	panel, prefix := g.table(ctx)
+
+	if !panel.GetImportable() {
+		alert(ctx, panel, "operation not allow", g.conn)
+		ctx.Abort()
+		return
+	}
+
+	ctx.SetUserValue("show_import_param", &ShowImportParam{
+		Panel:  panel,
+		Prefix: prefix,
+	})
+	ctx.Next()
+}
+
+func GetShowImportParam(ctx *context.Context) *ShowImportParam {
+	return ctx.UserValue["show_import_param"].(*ShowImportParam)
+}
+
+type ImportPreviewParam struct {
+	Panel   table.Table
+	Prefix  string
+	Columns []table.ImportColumn
+	Rows    []table.ImportRow
+	Alert   template.HTML
+}
+
+func (e ImportPreviewParam) HasAlert() bool {
+	return e.Alert != template.HTML("")
+}
+
+func (g *Guard) ImportPreview(ctx *context.Context) {
+	panel, prefix := g.table(ctx)
+
+	if !panel.GetImportable() {
+		alert(ctx, panel, "operation not allow", g.conn)
+		ctx.Abort()
+		return
+	}
+
+	param := &ImportPreviewParam{
+		Panel:  panel,
+		Prefix: prefix,
+	}
+
+	if ctx.Request.MultipartForm == nil || len(ctx.Request.MultipartForm.File[form.ImportFileKey]) == 0 {
+		param.Alert = getAlert(language.Get("wrong import file"))
+		ctx.SetUserValue("import_preview_param", param)
+		ctx.Next()
+		return
+	}
+
+	header := ctx.Request.MultipartForm.File[form.ImportFileKey][0]
+
+	file, err := header.Open()
+	if err != nil {
+		param.Alert = getAlert(language.Get(err.Error()))
+		ctx.SetUserValue("import_preview_param", param)
+		ctx.Next()
+		return
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	columns, rows, err := table.ReadImportFile(header.Filename, file, panel.GetForm().FieldList)
+	if err != nil {
+		param.Alert = getAlert(language.Get(err.Error()))
+		ctx.SetUserValue("import_preview_param", param)
+		ctx.Next()
+		return
+	}
+
+	param.Columns = columns
+	param.Rows = panel.CheckImportData(rows)
+
+	ctx.SetUserValue("import_preview_param", param)
+	ctx.Next()
+}
+
+func GetImportPreviewParam(ctx *context.Context) *ImportPreviewParam {
+	return ctx.UserValue["import_preview_param"].(*ImportPreviewParam)
+}
+
+type ImportParam struct {
+	Panel  table.Table
+	Prefix string
+	Rows   []table.ImportRow
+}
+
+func (g *Guard) Import(ctx *context.Context) {
+	panel, prefix := g.table(ctx)
+
+	if !panel.GetImportable() {
+		alert(ctx, panel, "operation not allow", g.conn)
+		ctx.Abort()
+		return
+	}
+
+	var rows []table.ImportRow
+	if err := json.Unmarshal([]byte(ctx.FormValue(form.ImportRowsKey)), &rows); err != nil || len(rows) == 0 {
+		alert(ctx, panel, "wrong import file", g.conn)
+		ctx.Abort()
+		return
+	}
+
+	ctx.SetUserValue("import_param", &ImportParam{
+		Panel:  panel,
+		Prefix: prefix,
+		Rows:   rows,
+	})
+	ctx.Next()
+}
+
+func GetImportParam(ctx *context.Context) *ImportParam {
+	return ctx.UserValue["import_param"].(*ImportParam)
+}
@@ -2,8 +2,7 @@ package guard
 
 import (
 	"github.com/GoAdminGroup/go-admin/context"
-	"github.com/GoAdminGroup/go-admin/modules/auth"
-	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"html/template"
 	"strconv"
 )
@@ -32,16 +31,12 @@ func (g *Guard) MenuEdit(ctx *context.Context) {
 
 	var (
 		parentIdInt, _ = strconv.Atoi(parentId)
-		token          = ctx.FormValue(form.TokenKey)
-		alert          template.HTML
+		alert          = checkEmpty(ctx, "id", "title", "icon")
 	)
 
-	if !auth.GetTokenService(g.services.Get(auth.TokenServiceKey)).CheckToken(token) {
-		alert = getAlert("edit fail, wrong token")
-	}
-
-	if alert == "" {
-		alert = checkEmpty(ctx, "id", "title", "icon")
+	// a menu can not be moved under itself or its descendants.
+	if alert == "" && !models.MenuWithId(ctx.FormValue("id")).SetConn(g.conn).CheckParent(int64(parentIdInt)) {
+		alert = getAlert("wrong parent_id")
 	}
 
 	// TODO: check the user permission
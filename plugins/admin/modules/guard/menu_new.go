@@ -2,8 +2,7 @@ package guard
 
 import (
 	"github.com/GoAdminGroup/go-admin/context"
-	"github.com/GoAdminGroup/go-admin/modules/auth"
-	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"html/template"
 	"strconv"
 )
@@ -29,21 +28,14 @@ func (g *Guard) MenuNew(ctx *context.Context) {
 		parentId = "0"
 	}
 
-	var (
-		alert template.HTML
-		token = ctx.FormValue(form.TokenKey)
-	)
+	alert := checkEmpty(ctx, "title", "icon")
 
-	if !auth.GetTokenService(g.services.Get(auth.TokenServiceKey)).CheckToken(token) {
-		alert = getAlert("edit fail, wrong token")
-	}
+	parentIdInt, _ := strconv.Atoi(parentId)
 
-	if alert == "" {
-		alert = checkEmpty(ctx, "title", "icon")
+	if alert == "" && !models.Menu().SetConn(g.conn).CheckParent(int64(parentIdInt)) {
+		alert = getAlert("wrong parent_id")
 	}
 
-	parentIdInt, _ := strconv.Atoi(parentId)
-
 	ctx.SetUserValue("new_menu_param", &MenuNewParam{
 		Title:    ctx.FormValue("title"),
 		Header:   ctx.FormValue("header"),
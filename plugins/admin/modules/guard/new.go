@@ -2,7 +2,6 @@ package guard
 
 import (
 	"github.com/GoAdminGroup/go-admin/context"
-	"github.com/GoAdminGroup/go-admin/modules/auth"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
@@ -81,13 +80,6 @@ func (g *Guard) NewForm(ctx *context.Context) {
 		ctx.Abort()
 		return
 	}
-	token := ctx.FormValue(form.TokenKey)
-
-	if !auth.GetTokenService(g.services.Get(auth.TokenServiceKey)).CheckToken(token) {
-		alert(ctx, panel, "create fail, wrong token", conn)
-		ctx.Abort()
-		return
-	}
 
 	fromList := isInfoUrl(previous)
 
@@ -15,12 +15,35 @@ type Config struct {
 	Size         int
 	Param        parameter.Parameters
 	PageSizeList []string
+
+	// SizeEstimated tells the Size is an estimate, and a negative Size
+	// means the total is unknown.
+	SizeEstimated bool
+
+	// Cursor is set when the pages are not numbered.
+	Cursor *Cursor
+}
+
+// Cursor is the links of a page which is not numbered, such as the pages
+// of the keyset pagination or of an unknown total. The links of the keyset
+// pagination are made of the Previous and Next cursors, and the ones of
+// the offset pagination are made of the page numbers.
+type Cursor struct {
+	Rows        int
+	HasPrevious bool
+	HasNext     bool
+	Previous    string
+	Next        string
 }
 
 func Get(cfg Config) types.PaginatorAttribute {
 
 	paginator := template2.Default().Paginator().(*components.PaginatorAttribute)
 
+	if cfg.Cursor != nil {
+		return getCursor(paginator, cfg)
+	}
+
 	totalPage := int(math.Ceil(float64(cfg.Size) / float64(cfg.Param.PageSizeInt)))
 
 	if cfg.Param.PageInt == 1 {
@@ -41,18 +64,9 @@ func Get(cfg Config) types.PaginatorAttribute {
 	paginator.Url = cfg.Param.URLPath + cfg.Param.GetRouteParamStrWithoutPageSize() + "&" + form.NoAnimationKey + "=true"
 	paginator.CurPageEndIndex = strconv.Itoa((cfg.Param.PageInt) * cfg.Param.PageSizeInt)
 	paginator.CurPageStartIndex = strconv.Itoa((cfg.Param.PageInt - 1) * cfg.Param.PageSizeInt)
-	paginator.Total = strconv.Itoa(cfg.Size)
+	paginator.Total = total(cfg)
 
-	if len(cfg.PageSizeList) == 0 {
-		cfg.PageSizeList = []string{"10", "20", "50", "100"}
-	}
-
-	paginator.Option = make(map[string]template.HTML, len(cfg.PageSizeList))
-	for i := 0; i < len(cfg.PageSizeList); i++ {
-		paginator.Option[cfg.PageSizeList[i]] = template.HTML("")
-	}
-
-	paginator.Option[cfg.Param.PageSize] = template.HTML("selected")
+	setOption(paginator, &cfg)
 
 	paginator.Pages = []map[string]string{}
 
@@ -201,3 +215,72 @@ func Get(cfg Config) types.PaginatorAttribute {
 
 	return paginator.SetPageSizeList(cfg.PageSizeList)
 }
+
+// getCursor set the previous and next links of the cursor without the
+// page numbers.
+func getCursor(paginator *components.PaginatorAttribute, cfg Config) types.PaginatorAttribute {
+
+	c := cfg.Cursor
+
+	paginator.PreviousClass = ""
+	paginator.NextClass = ""
+
+	if !c.HasPrevious {
+		paginator.PreviousClass = "disabled"
+		paginator.PreviousUrl = cfg.Param.URLPath
+	} else if c.Previous != "" {
+		paginator.PreviousUrl = cfg.Param.URLPath + cfg.Param.GetPreviousCursorRouteParamStr(c.Previous)
+	} else {
+		paginator.PreviousUrl = cfg.Param.URLPath + cfg.Param.GetLastPageRouteParamStr()
+	}
+
+	if !c.HasNext {
+		paginator.NextClass = "disabled"
+		paginator.NextUrl = cfg.Param.URLPath
+	} else if c.Next != "" {
+		paginator.NextUrl = cfg.Param.URLPath + cfg.Param.GetNextCursorRouteParamStr(c.Next)
+	} else {
+		paginator.NextUrl = cfg.Param.URLPath + cfg.Param.GetNextPageRouteParamStr()
+	}
+
+	start := (cfg.Param.PageInt - 1) * cfg.Param.PageSizeInt
+	paginator.Url = cfg.Param.URLPath + cfg.Param.GetRouteParamStrWithoutPageSize() + "&" + form.NoAnimationKey + "=true"
+	paginator.CurPageStartIndex = strconv.Itoa(start)
+	paginator.CurPageEndIndex = strconv.Itoa(start + c.Rows)
+
+	if cfg.Size < 0 {
+		// the total is at least the rows till the end of current page.
+		paginator.Total = strconv.Itoa(start + c.Rows)
+		if c.HasNext {
+			paginator.Total += "+"
+		}
+	} else {
+		paginator.Total = total(cfg)
+	}
+
+	setOption(paginator, &cfg)
+
+	paginator.Pages = []map[string]string{}
+
+	return paginator.SetPageSizeList(cfg.PageSizeList)
+}
+
+func total(cfg Config) string {
+	if cfg.SizeEstimated {
+		return "~" + strconv.Itoa(cfg.Size)
+	}
+	return strconv.Itoa(cfg.Size)
+}
+
+func setOption(paginator *components.PaginatorAttribute, cfg *Config) {
+	if len(cfg.PageSizeList) == 0 {
+		cfg.PageSizeList = []string{"10", "20", "50", "100"}
+	}
+
+	paginator.Option = make(map[string]template.HTML, len(cfg.PageSizeList))
+	for i := 0; i < len(cfg.PageSizeList); i++ {
+		paginator.Option[cfg.PageSizeList[i]] = template.HTML("")
+	}
+
+	paginator.Option[cfg.Param.PageSize] = template.HTML("selected")
+}
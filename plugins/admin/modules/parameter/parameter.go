@@ -1,6 +1,8 @@
 package parameter
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
@@ -20,6 +22,14 @@ type Parameters struct {
 	Animation   bool
 	URLPath     string
 	Fields      map[string][]string
+
+	// After and Before are the cursors of the keyset pagination, the rows
+	// after or before the cursor are queried.
+	After  string
+	Before string
+
+	// GroupBy is the field by which the rows are collapsed into groups.
+	GroupBy string
 }
 
 const (
@@ -30,6 +40,9 @@ const (
 	Columns  = "__columns"
 	Prefix   = "__prefix"
 	Pjax     = "_pjax"
+	After    = "__after"
+	Before   = "__before"
+	GroupBy  = "__group_by"
 
 	sortTypeDesc = "desc"
 	sortTypeAsc  = "asc"
@@ -60,7 +73,8 @@ var operators = map[string]string{
 	"free": "free",
 }
 
-var keys = []string{Page, PageSize, Sort, Columns, Prefix, Pjax, form.NoAnimationKey}
+var keys = []string{Page, PageSize, Sort, Columns, Prefix, Pjax, After, Before, GroupBy, form.NoAnimationKey,
+	constant.ExportFormatKey}
 
 func BaseParam() Parameters {
 	return Parameters{Page: "1", PageSize: "10", Fields: make(map[string][]string)}
@@ -125,6 +139,9 @@ func GetParam(u *url.URL, defaultPageSize int, p ...string) Parameters {
 		Fields:      fields,
 		Animation:   animation,
 		Columns:     columnsArr,
+		After:       values.Get(After),
+		Before:      values.Get(Before),
+		GroupBy:     values.Get(GroupBy),
 	}
 }
 
@@ -247,6 +264,15 @@ func (param Parameters) SetPage(page string) Parameters {
 	return param
 }
 
+// WithPage return the parameters of given page and page size.
+func (param Parameters) WithPage(page, pageSize int) Parameters {
+	param.Page = strconv.Itoa(page)
+	param.PageInt = page
+	param.PageSize = strconv.Itoa(pageSize)
+	param.PageSizeInt = pageSize
+	return param
+}
+
 func (param Parameters) GetRouteParamStr() string {
 	p := param.GetFixedParamStr()
 	p.Add(Page, param.Page)
@@ -287,6 +313,71 @@ func (param Parameters) GetNextPageRouteParamStr() string {
 	return "?" + p.Encode()
 }
 
+// GetNextCursorRouteParamStr return the route parameters of the next page
+// of the keyset pagination.
+func (param Parameters) GetNextCursorRouteParamStr(cursor string) string {
+	p := param.GetFixedParamStr()
+	p.Add(Page, strconv.Itoa(param.PageInt+1))
+	p.Add(After, cursor)
+	return "?" + p.Encode()
+}
+
+// GetPreviousCursorRouteParamStr return the route parameters of the previous
+// page of the keyset pagination.
+func (param Parameters) GetPreviousCursorRouteParamStr(cursor string) string {
+	p := param.GetFixedParamStr()
+	p.Add(Page, strconv.Itoa(param.PageInt-1))
+	p.Add(Before, cursor)
+	return "?" + p.Encode()
+}
+
+// WithAfter return the parameters of the page after given cursor.
+func (param Parameters) WithAfter(cursor string) Parameters {
+	param.After = cursor
+	param.Before = ""
+	return param
+}
+
+// WithGroupBy return the parameters of the first page grouped by given
+// field, an empty field means no group.
+func (param Parameters) WithGroupBy(field string) Parameters {
+	param.GroupBy = field
+	param.After = ""
+	param.Before = ""
+	return param.SetPage("1")
+}
+
+// EncodeCursor encode the values of a row into a cursor.
+func EncodeCursor(values ...string) string {
+	b, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor decode the values of a cursor, it returns nil if the cursor
+// is invalid.
+func DecodeCursor(cursor string) []string {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil
+	}
+	var values []string
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil
+	}
+	return values
+}
+
+// ViewParamStr return the route parameters of the view of the list, which
+// are the filters, the sort, the visible columns, the page size and the
+// group by field, without the page and the primary keys.
+func (param Parameters) ViewParamStr() string {
+	p := param.GetFixedParamStr()
+	for _, key := range []string{IsAll, PrimaryKey, constant.EditPKKey, constant.DetailPKKey, form.NoAnimationKey} {
+		p.Del(key)
+	}
+	return p.Encode()
+}
+
 func (param Parameters) GetFixedParamStr() url.Values {
 	p := url.Values{}
 	p.Add(Sort, param.SortField)
@@ -295,6 +386,9 @@ func (param Parameters) GetFixedParamStr() url.Values {
 	if len(param.Columns) > 0 {
 		p.Add(Columns, strings.Join(param.Columns, ","))
 	}
+	if param.GroupBy != "" {
+		p.Add(GroupBy, param.GroupBy)
+	}
 	for key, value := range param.Fields {
 		p[key] = value
 	}
@@ -354,7 +448,7 @@ func (param Parameters) Statement(wheres, table, delimiter string, whereArgs []i
 		} else {
 			keys := strings.Split(key, FilterParamJoinInfix)
 			if len(keys) > 1 {
-				if joinTable := getJoinTable(keys[1]); joinTable != "" {
+				if joinTable := getJoinTable(key); joinTable != "" {
 					val := filterProcess(key, value[0], keyIndexSuffix)
 					if op == "in" {
 						qmark := ""
@@ -2,6 +2,7 @@ package parameter
 
 import (
 	"fmt"
+	"github.com/stretchr/testify/assert"
 	"testing"
 )
 
@@ -14,3 +15,46 @@ func TestParameters_PKs(t *testing.T) {
 	pks := BaseParam().PKs()
 	fmt.Println("pks", pks, "len", len(pks))
 }
+
+func TestParameters_WithPage(t *testing.T) {
+	param := BaseParam().WithPage(3, 100)
+	assert.Equal(t, param.Page, "3")
+	assert.Equal(t, param.PageInt, 3)
+	assert.Equal(t, param.PageSize, "100")
+	assert.Equal(t, param.PageSizeInt, 100)
+}
+
+func TestExportFormat(t *testing.T) {
+	param := GetParamFromURL("/admin/info/user?__page=2&__goadmin_export_format=csv&name=jane", 10, "desc", "id")
+	assert.Equal(t, param.GetFieldValue("name"), "jane")
+	assert.NotContains(t, param.GetRouteParamStr(), "__goadmin_export_format")
+}
+
+func TestCursor(t *testing.T) {
+	cursor := EncodeCursor("2020-01-02 15:04:05", "12")
+	assert.Equal(t, DecodeCursor(cursor), []string{"2020-01-02 15:04:05", "12"})
+	assert.Nil(t, DecodeCursor("not a cursor"))
+
+	param := GetParamFromURL("/admin/info/user?__page=2&__after="+cursor, 10, "desc", "id")
+	assert.Equal(t, param.After, cursor)
+	assert.Equal(t, param.GetFieldValue(After), "")
+	assert.Contains(t, param.GetNextCursorRouteParamStr("next"), "__after=next")
+	assert.Contains(t, param.GetPreviousCursorRouteParamStr("prev"), "__before=prev")
+}
+
+func TestGroupBy(t *testing.T) {
+	param := GetParamFromURL("/admin/info/order?__page=3&__group_by=region", 10, "desc", "id")
+	assert.Equal(t, param.GroupBy, "region")
+	assert.Equal(t, param.GetFieldValue(GroupBy), "")
+
+	param = param.WithGroupBy("status")
+	assert.Equal(t, param.Page, "1")
+	assert.Contains(t, param.GetRouteParamStr(), "__group_by=status")
+	assert.NotContains(t, param.WithGroupBy("").GetRouteParamStr(), "__group_by")
+}
+
+func TestViewParamStr(t *testing.T) {
+	param := GetParamFromURL("/admin/info/user?__page=3&__pageSize=20&__sort=name&__sort_type=asc&__columns=id,name&name=jane&__is_all=true",
+		10, "desc", "id")
+	assert.Equal(t, param.ViewParamStr(), "__columns=id%2Cname&__pageSize=20&__sort=name&__sort_type=asc&name=jane")
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/menu"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/template"
 	"github.com/GoAdminGroup/go-admin/template/types"
 	template2 "html/template"
@@ -36,6 +37,34 @@ func BadRequest(ctx *context.Context, msg string) {
 	})
 }
 
+func NotFound(ctx *context.Context, msg string) {
+	ctx.JSON(http.StatusNotFound, map[string]interface{}{
+		"code": 404,
+		"msg":  language.Get(msg),
+	})
+}
+
+func Forbidden(ctx *context.Context, msg string) {
+	ctx.JSON(http.StatusForbidden, map[string]interface{}{
+		"code": 403,
+		"msg":  language.Get(msg),
+	})
+}
+
+// ValidationError responds the validation errors of the form fields.
+func ValidationError(ctx *context.Context, errs form.FieldErrors) {
+	for i := range errs {
+		errs[i].Msg = language.Get(errs[i].Msg)
+	}
+	ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+		"code": 422,
+		"msg":  language.Get("validation failed"),
+		"data": map[string]interface{}{
+			"errors": errs,
+		},
+	})
+}
+
 func Alert(ctx *context.Context, config config.Config, desc, title, msg string, conn db.Connection) {
 	user := auth.Auth(ctx)
 
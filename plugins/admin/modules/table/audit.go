@@ -0,0 +1,130 @@
+package table
+
+import (
+	"fmt"
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"sort"
+	"strings"
+)
+
+// The operation types of the audit records.
+const (
+	AuditInsert       = "insert"
+	AuditUpdate       = "update"
+	AuditInlineUpdate = "inline update"
+	AuditDelete       = "delete"
+	AuditExport       = "export"
+	AuditLoginFailure = "login failure"
+)
+
+// AuditRedacted replaces the values of the sensitive fields in the audit records.
+const AuditRedacted = "******"
+
+const auditRecordsKey = "audit_records"
+
+// FieldChange is the value of a field before and after a writing.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// AuditRecord is a writing of a table row.
+type AuditRecord struct {
+	Prefix  string        `json:"prefix"`
+	PK      string        `json:"pk"`
+	Op      string        `json:"op"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// Auditor receives the audit records of the table writings after they
+// are committed.
+type Auditor func(record AuditRecord)
+
+// ContextAuditor return an Auditor which keeps the records of given table
+// in the request context. The records are stored with the operation log
+// at the end of the request.
+func ContextAuditor(ctx *context.Context, prefix string) Auditor {
+	return func(record AuditRecord) {
+		record.Prefix = prefix
+		records, _ := ctx.UserValue[auditRecordsKey].([]AuditRecord)
+		ctx.SetUserValue(auditRecordsKey, append(records, record))
+	}
+}
+
+// GetAuditRecords return the audit records of the request.
+func GetAuditRecords(ctx *context.Context) []AuditRecord {
+	records, _ := ctx.UserValue[auditRecordsKey].([]AuditRecord)
+	return records
+}
+
+// IsSensitiveField check the field should be redacted in the logs or not.
+func IsSensitiveField(field string) bool {
+	return strings.Contains(strings.ToLower(field), "password")
+}
+
+func (tb DefaultTable) isSensitiveField(field string) bool {
+	return IsSensitiveField(field) || tb.Form.FieldList.FindByFieldName(field).FormType == form.Password
+}
+
+// diff return the changes of the fields from the row before to the values
+// after, the fields not changed are ignored.
+func (tb DefaultTable) diff(before map[string]interface{}, after dialect.H) []FieldChange {
+	fields := make([]string, 0)
+	for field := range before {
+		if _, ok := after[field]; ok || after == nil {
+			fields = append(fields, field)
+		}
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := make([]FieldChange, 0)
+	for _, field := range fields {
+		change := FieldChange{Field: field}
+		if v, ok := before[field]; ok {
+			change.Before = auditValue(v)
+		}
+		if v, ok := after[field]; ok {
+			change.After = auditValue(v)
+		}
+		if change.Before == change.After {
+			continue
+		}
+		if tb.isSensitiveField(field) {
+			change.Before, change.After = redact(change.Before), redact(change.After)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func (tb DefaultTable) audit(record AuditRecord) {
+	if tb.auditor != nil {
+		tb.auditor(record)
+	}
+}
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return AuditRedacted
+}
+
+func auditValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
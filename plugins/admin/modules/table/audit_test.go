@@ -0,0 +1,39 @@
+package table
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDefaultTable_diff(t *testing.T) {
+	tb := NewDefaultTable(DefaultConfig()).(DefaultTable)
+	tb.GetForm().AddField("Secret", "secret", db.Varchar, form.Password)
+
+	changes := tb.diff(map[string]interface{}{
+		"id":       int64(1),
+		"name":     []byte("jack"),
+		"age":      int64(10),
+		"password": "a",
+		"secret":   "b",
+	}, dialect.H{
+		"name":     "rose",
+		"age":      "10",
+		"password": "c",
+		"secret":   "d",
+	})
+
+	assert.Equal(t, changes, []FieldChange{
+		{Field: "name", Before: "jack", After: "rose"},
+		{Field: "password", Before: AuditRedacted, After: AuditRedacted},
+		{Field: "secret", Before: AuditRedacted, After: AuditRedacted},
+	})
+
+	changes = tb.diff(map[string]interface{}{"id": int64(1), "name": "jack"}, nil)
+	assert.Equal(t, changes, []FieldChange{
+		{Field: "id", Before: "1"},
+		{Field: "name", Before: "jack"},
+	})
+}
@@ -11,9 +11,16 @@ type Config struct {
 	Editable   bool
 	Deletable  bool
 	Exportable bool
+	Importable bool
 	PrimaryKey PrimaryKey
 	SourceURL  string
 	GetDataFun GetDataFun
+
+	// ReadConnections are the replicas which the list and export queries
+	// are sent to, the Replicas of the connection config are used when it
+	// is empty. The single row of the detail and the form is always read
+	// from the primary, as it may be just written.
+	ReadConnections []string
 }
 
 func DefaultConfig() Config {
@@ -66,11 +73,21 @@ func (config Config) SetExportable(exportable bool) Config {
 	return config
 }
 
+func (config Config) SetImportable(importable bool) Config {
+	config.Importable = importable
+	return config
+}
+
 func (config Config) SetConnection(connection string) Config {
 	config.Connection = connection
 	return config
 }
 
+func (config Config) SetReadConnections(connections ...string) Config {
+	config.ReadConnections = connections
+	return config
+}
+
 func DefaultConfigWithDriver(driver string) Config {
 	return Config{
 		Driver:     driver,
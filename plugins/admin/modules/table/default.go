@@ -1,9 +1,12 @@
 package table
 
 import (
+	"context"
+	dbsql "database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/GoAdminGroup/go-admin/modules/cache"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
 	"github.com/GoAdminGroup/go-admin/modules/language"
@@ -13,8 +16,10 @@ import (
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/paginator"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
 	"github.com/GoAdminGroup/go-admin/template/types"
+	form2 "github.com/GoAdminGroup/go-admin/template/types/form"
 	"html/template"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -25,6 +30,7 @@ type DefaultTable struct {
 	*BaseTable
 	connectionDriver string
 	connection       string
+	readConnections  []string
 	sourceURL        string
 	getDataFun       GetDataFun
 }
@@ -50,25 +56,30 @@ func NewDefaultTable(cfgs ...Config) Table {
 			Editable:   cfg.Editable,
 			Deletable:  cfg.Deletable,
 			Exportable: cfg.Exportable,
+			Importable: cfg.Importable,
 			PrimaryKey: cfg.PrimaryKey,
 		},
 		connectionDriver: cfg.Driver,
 		connection:       cfg.Connection,
+		readConnections:  cfg.ReadConnections,
 		sourceURL:        cfg.SourceURL,
 		getDataFun:       cfg.GetDataFun,
 	}
 }
 
 func (tb DefaultTable) Copy() Table {
+	info := types.NewInfoPanel(tb.PrimaryKey.Name).SetTable(tb.Info.Table).
+		SetDescription(tb.Info.Description).
+		SetTitle(tb.Info.Title).
+		SetGetDataFn(tb.Info.GetDataFn)
+	// the copy reads the same rows as the user can.
+	info.RowPolicies = tb.Info.RowPolicies
 	return DefaultTable{
 		BaseTable: &BaseTable{
 			Form: types.NewFormPanel().SetTable(tb.Form.Table).
 				SetDescription(tb.Form.Description).
 				SetTitle(tb.Form.Title),
-			Info: types.NewInfoPanel(tb.PrimaryKey.Name).SetTable(tb.Info.Table).
-				SetDescription(tb.Info.Description).
-				SetTitle(tb.Info.Title).
-				SetGetDataFn(tb.Info.GetDataFn),
+			Info: info,
 			Detail: types.NewInfoPanel(tb.PrimaryKey.Name).SetTable(tb.Detail.Table).
 				SetDescription(tb.Detail.Description).
 				SetTitle(tb.Detail.Title).
@@ -77,10 +88,13 @@ func (tb DefaultTable) Copy() Table {
 			Editable:   tb.Editable,
 			Deletable:  tb.Deletable,
 			Exportable: tb.Exportable,
+			Importable: tb.Importable,
 			PrimaryKey: tb.PrimaryKey,
+			user:       tb.user,
 		},
 		connectionDriver: tb.connectionDriver,
 		connection:       tb.connection,
+		readConnections:  tb.readConnections,
 		sourceURL:        tb.sourceURL,
 		getDataFun:       tb.getDataFun,
 	}
@@ -88,6 +102,12 @@ func (tb DefaultTable) Copy() Table {
 
 // GetData query the data set.
 func (tb DefaultTable) GetData(params parameter.Parameters) (PanelInfo, error) {
+	return tb.GetDataWithContext(context.Background(), params)
+}
+
+// GetDataWithContext query the data set with the context, the queries are
+// canceled when the context is done.
+func (tb DefaultTable) GetDataWithContext(ctx context.Context, params parameter.Parameters) (PanelInfo, error) {
 
 	var (
 		data      []map[string]interface{}
@@ -98,13 +118,13 @@ func (tb DefaultTable) GetData(params parameter.Parameters) (PanelInfo, error) {
 	if tb.getDataFun != nil {
 		data, size = tb.getDataFun(params)
 	} else if tb.sourceURL != "" {
-		data, size = tb.getDataFromURL(params)
+		data, size = tb.getDataFromURL(ctx, params)
 	} else if tb.Info.GetDataFn != nil {
 		data, size = tb.Info.GetDataFn(params)
 	} else if params.IsAll() {
-		return tb.getAllDataFromDatabase(params)
+		return tb.getAllDataFromDatabase(ctx, params)
 	} else {
-		return tb.getDataFromDatabase(params)
+		return tb.getDataFromDatabase(ctx, params)
 	}
 
 	infoList := make(types.InfoList, 0)
@@ -120,12 +140,12 @@ func (tb DefaultTable) GetData(params parameter.Parameters) (PanelInfo, error) {
 	return PanelInfo{
 		Thead:    thead,
 		InfoList: infoList,
+		Total:    size,
 		Paginator: paginator.Get(paginator.Config{
 			Size:         size,
 			Param:        params,
 			PageSizeList: tb.Info.GetPageSizeList(),
-		}).SetExtraInfo(template.HTML(fmt.Sprintf("<b>" + language.Get("query time") + ": </b>" +
-			fmt.Sprintf("%.3fms", endTime.Sub(beginTime).Seconds()*1000)))),
+		}).SetExtraInfo(queryTime(beginTime, endTime)),
 		Title:          tb.Info.Title,
 		FilterFormData: filterForm,
 		Description:    tb.Info.Description,
@@ -137,7 +157,7 @@ type GetDataFromURLRes struct {
 	Size int
 }
 
-func (tb DefaultTable) getDataFromURL(params parameter.Parameters) ([]map[string]interface{}, int) {
+func (tb DefaultTable) getDataFromURL(ctx context.Context, params parameter.Parameters) ([]map[string]interface{}, int) {
 
 	u := ""
 	if strings.Contains(tb.sourceURL, "?") {
@@ -145,7 +165,13 @@ func (tb DefaultTable) getDataFromURL(params parameter.Parameters) ([]map[string
 	} else {
 		u = tb.sourceURL + "?" + params.Join()
 	}
-	res, err := http.Get(u + "&pk=" + strings.Join(params.PKs(), ","))
+	req, err := http.NewRequest(http.MethodGet, u+"&pk="+strings.Join(params.PKs(), ","), nil)
+
+	if err != nil {
+		return []map[string]interface{}{}, 0
+	}
+
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
 
 	if err != nil {
 		return []map[string]interface{}{}, 0
@@ -174,6 +200,12 @@ func (tb DefaultTable) getDataFromURL(params parameter.Parameters) ([]map[string
 
 // GetDataWithIds query the data set.
 func (tb DefaultTable) GetDataWithIds(params parameter.Parameters) (PanelInfo, error) {
+	return tb.GetDataWithIdsWithContext(context.Background(), params)
+}
+
+// GetDataWithIdsWithContext query the data set of the ids with the context,
+// the queries are canceled when the context is done.
+func (tb DefaultTable) GetDataWithIdsWithContext(ctx context.Context, params parameter.Parameters) (PanelInfo, error) {
 
 	var (
 		data      []map[string]interface{}
@@ -184,11 +216,11 @@ func (tb DefaultTable) GetDataWithIds(params parameter.Parameters) (PanelInfo, e
 	if tb.getDataFun != nil {
 		data, size = tb.getDataFun(params)
 	} else if tb.sourceURL != "" {
-		data, size = tb.getDataFromURL(params)
+		data, size = tb.getDataFromURL(ctx, params)
 	} else if tb.Info.GetDataFn != nil {
 		data, size = tb.Info.GetDataFn(params)
 	} else {
-		return tb.getDataFromDatabase(params)
+		return tb.getDataFromDatabase(ctx, params)
 	}
 
 	infoList := make([]map[string]types.InfoItem, 0)
@@ -204,13 +236,13 @@ func (tb DefaultTable) GetDataWithIds(params parameter.Parameters) (PanelInfo, e
 	return PanelInfo{
 		Thead:    thead,
 		InfoList: infoList,
+		Total:    size,
 		Paginator: paginator.Get(paginator.Config{
 			Size:         size,
 			Param:        params,
 			PageSizeList: tb.Info.GetPageSizeList(),
 		}).
-			SetExtraInfo(template.HTML(fmt.Sprintf("<b>" + language.Get("query time") + ": </b>" +
-				fmt.Sprintf("%.3fms", endTime.Sub(beginTime).Seconds()*1000)))),
+			SetExtraInfo(queryTime(beginTime, endTime)),
 		Title:          tb.Info.Title,
 		FilterFormData: filterForm,
 		Description:    tb.Info.Description,
@@ -226,11 +258,7 @@ func (tb DefaultTable) getTempModelData(res map[string]interface{}, params param
 
 	for _, field := range tb.Info.FieldList {
 
-		headField = field.Field
-
-		if field.Join.Valid() {
-			headField = field.Join.Table + parameter.FilterParamJoinInfix + field.Field
-		}
+		headField = field.HeadField()
 
 		if field.Hide {
 			continue
@@ -241,14 +269,14 @@ func (tb DefaultTable) getTempModelData(res map[string]interface{}, params param
 
 		typeName := field.TypeName
 
-		if field.Join.Valid() {
+		if field.JoinChain().Valid() {
 			typeName = db.Varchar
 		}
 
 		combineValue := db.GetValueFromDatabaseType(typeName, res[headField], len(columns) == 0).String()
 
 		var value interface{}
-		if len(columns) == 0 || modules.InArray(columns, headField) || field.Join.Valid() {
+		if len(columns) == 0 || modules.InArray(columns, headField) || field.JoinChain().Valid() {
 			value = field.ToDisplay(types.FieldModel{
 				ID:    primaryKeyValue.String(),
 				Value: combineValue,
@@ -281,10 +309,10 @@ func (tb DefaultTable) getTempModelData(res map[string]interface{}, params param
 	return tempModelData
 }
 
-func (tb DefaultTable) getAllDataFromDatabase(params parameter.Parameters) (PanelInfo, error) {
+func (tb DefaultTable) getAllDataFromDatabase(ctx context.Context, params parameter.Parameters) (PanelInfo, error) {
 	var (
 		connection     = tb.db()
-		queryStatement = "select %s from %s %s %s order by " + modules.Delimiter(connection.GetDelimiter(), "%s") + " %s"
+		queryStatement = "select %s from %s %s %s %s order by %s %s"
 	)
 
 	columns, _ := tb.getColumns(tb.Info.Table)
@@ -304,24 +332,40 @@ func (tb DefaultTable) getAllDataFromDatabase(params parameter.Parameters) (Pane
 		existKeys = make([]string, 0)
 	)
 
-	wheres, whereArgs, existKeys = params.Statement(wheres, tb.Info.Table, connection.GetDelimiter(), whereArgs, columns, existKeys,
+	wheres, whereArgs, existKeys = params.Statement(wheres, tb.Info.Table, connection.GetDelimiter(), whereArgs, tb.filterColumns(columns), existKeys,
 		tb.Info.FieldList.GetFieldFilterProcessValue, tb.Info.FieldList.GetFieldJoinTable)
 	wheres, whereArgs = tb.Info.Wheres.Statement(wheres, connection.GetDelimiter(), whereArgs, existKeys, columns)
 	wheres, whereArgs = tb.Info.WhereRaws.Statement(wheres, whereArgs)
+	wheres, whereArgs = tb.rowPolicyStatement(wheres, whereArgs)
 
 	if wheres != "" {
 		wheres = " where " + wheres
 	}
 
-	if !modules.InArray(columns, params.SortField) {
-		params.SortField = tb.PrimaryKey.Name
+	var (
+		group   = joins != "" && tb.Info.FieldList.IsGroupBy()
+		groupBy = ""
+	)
+
+	if group {
+		groupBy = " GROUP BY " + tb.Info.Table + "." + modules.FilterField(tb.PrimaryKey.Name, connection.GetDelimiter())
+		if connection.Name() == "mssql" {
+			// all the selected columns of the table should be grouped.
+			for _, field := range tb.Info.FieldList {
+				if field.Field != tb.PrimaryKey.Name && !field.JoinChain().Valid() && modules.InArray(columns, field.Field) {
+					groupBy += "," + tb.Info.Table + "." + modules.FilterField(field.Field, connection.GetDelimiter())
+				}
+			}
+		}
 	}
 
-	queryCmd := fmt.Sprintf(queryStatement, fields, tb.Info.Table, joins, wheres, params.SortField, params.SortType)
+	sortField := tb.sortField(&params, columns, group, false)
+
+	queryCmd := fmt.Sprintf(queryStatement, fields, tb.Info.Table, joins, wheres, groupBy, sortField, params.SortType)
 
 	logger.LogSQL(queryCmd, []interface{}{})
 
-	res, err := connection.QueryWithConnection(tb.connection, queryCmd, whereArgs...)
+	res, err := tb.queryRead(ctx, tb.readConnection(), queryCmd, whereArgs...)
 
 	if err != nil {
 		return PanelInfo{}, err
@@ -336,13 +380,14 @@ func (tb DefaultTable) getAllDataFromDatabase(params parameter.Parameters) (Pane
 	return PanelInfo{
 		InfoList:    infoList,
 		Thead:       thead,
+		Total:       len(infoList),
 		Title:       tb.Info.Title,
 		Description: tb.Info.Description,
 	}, nil
 }
 
 // TODO: refactor
-func (tb DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelInfo, error) {
+func (tb DefaultTable) getDataFromDatabase(ctx context.Context, params parameter.Parameters) (PanelInfo, error) {
 
 	var (
 		connection     = tb.db()
@@ -351,32 +396,33 @@ func (tb DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelIn
 		countStatement string
 		ids            = params.PKs()
 		pk             = tb.Info.Table + "." + modules.Delimiter(connection.GetDelimiter(), tb.PrimaryKey.Name)
+		keyset         = tb.Info.IsKeysetPagination && len(ids) == 0
 	)
 
 	beginTime := time.Now()
 
 	if len(ids) > 0 {
 		if connection.Name() == "mssql" {
-			// %s means: fields, table, join table, pk values, group by, order by field, order by type
-			queryStatement = "SELECT %s from " + placeholder + "%s where " + pk + " in (%s) %s ORDER BY %s." + placeholder + " %s"
-			// %s means: table, join table, pk values
-			countStatement = "select count(*) as [size] from " + placeholder + " %s where " + pk + " in (%s)"
+			// %s means: fields, table, join table, wheres of pk values, group by, order by
+			queryStatement = "SELECT %s from " + placeholder + "%s where %s %s ORDER BY %s"
+			// %s means: table, join table, wheres of pk values
+			countStatement = "select count(*) as [size] from " + placeholder + " %s where %s"
 		} else {
-			// %s means: fields, table, join table, pk values, group by, order by field,  order by type
-			queryStatement = "select %s from %s %s where " + pk + " in (%s) %s order by %s." + placeholder + " %s"
-			// %s means: table, join table, pk values
-			countStatement = "select count(*) from " + placeholder + " %s where " + pk + " in (%s)"
+			// %s means: fields, table, join table, wheres of pk values, group by, order by
+			queryStatement = "select %s from %s %s where %s %s order by %s"
+			// %s means: table, join table, wheres of pk values
+			countStatement = "select count(*) from " + placeholder + " %s where %s"
 		}
 	} else {
 		if connection.Name() == "mssql" {
-			// %s means: order by field, order by type, fields, table, join table, wheres, group by
-			queryStatement = "SELECT * FROM (SELECT ROW_NUMBER() OVER (ORDER BY %s." + placeholder + " %s) as ROWNUMBER_, %s from " +
+			// %s means: order by, fields, table, join table, wheres, group by
+			queryStatement = "SELECT * FROM (SELECT ROW_NUMBER() OVER (ORDER BY %s) as ROWNUMBER_, %s from " +
 				placeholder + "%s %s %s  ) as TMP_ WHERE TMP_.ROWNUMBER_ > ? AND TMP_.ROWNUMBER_ <= ?"
 			// %s means: table, join table, wheres
 			countStatement = "select count(*) as [size] from " + placeholder + " %s %s"
 		} else {
-			// %s means: fields, table, join table, wheres, group by, order by field, order by type
-			queryStatement = "select %s from " + placeholder + "%s %s %s order by %s." + placeholder + " %s LIMIT ? OFFSET ?"
+			// %s means: fields, table, join table, wheres, group by, order by
+			queryStatement = "select %s from " + placeholder + "%s %s %s order by %s LIMIT ? OFFSET ?"
 			// %s means: table, join table, wheres
 			countStatement = "select count(*) from " + placeholder + " %s %s"
 		}
@@ -384,8 +430,23 @@ func (tb DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelIn
 
 	columns, _ := tb.getColumns(tb.Info.Table)
 
+	if groupField, ok := tb.Info.FieldList.GetGroupableField(params.GroupBy, columns); ok && len(ids) == 0 {
+		return tb.getGroupedDataFromDatabase(ctx, params, groupField, columns)
+	}
+
 	thead, fields, joinFields, joins, joinTables, filterForm := tb.getTheadAndFilterForm(params, columns)
 
+	group := len(joinTables) > 0 && tb.Info.FieldList.IsGroupBy()
+
+	// the cursor condition can not be made of an aggregated joined field.
+	sortField := tb.sortField(&params, columns, group, keyset && group)
+
+	// the cursors are made of the sort column, which may be hidden.
+	if keyset && params.SortField != tb.PrimaryKey.Name && modules.InArray(columns, params.SortField) &&
+		!strings.Contains(fields, tb.Info.Table+"."+modules.FilterField(params.SortField, connection.GetDelimiter())+",") {
+		fields += tb.Info.Table + "." + modules.FilterField(params.SortField, connection.GetDelimiter()) + ","
+	}
+
 	fields += pk
 
 	allFields := fields
@@ -394,46 +455,105 @@ func (tb DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelIn
 		allFields += "," + joinFields[:len(joinFields)-1]
 	}
 
-	if !modules.InArray(columns, params.SortField) {
-		params.SortField = tb.PrimaryKey.Name
-	}
-
 	var (
-		wheres    = ""
-		whereArgs = make([]interface{}, 0)
-		args      = make([]interface{}, 0)
-		existKeys = make([]string, 0)
+		wheres      = ""
+		whereArgs   = make([]interface{}, 0)
+		args        = make([]interface{}, 0)
+		existKeys   = make([]string, 0)
+		countWheres = ""
+		cursor      []string
+		reverse     = keyset && params.Before != ""
+		limit       = params.PageSizeInt
+		offset      = (params.PageInt - 1) * params.PageSizeInt
 	)
 
+	// one more row is queried to know whether there is a next page.
+	if keyset || tb.Info.CountMode != types.CountExact {
+		limit++
+	}
+
+	if keyset {
+		offset = 0
+		if reverse {
+			cursor = parameter.DecodeCursor(params.Before)
+		} else if params.After != "" {
+			cursor = parameter.DecodeCursor(params.After)
+		}
+		if len(cursor) != 2 {
+			cursor = nil
+			reverse = false
+			params.Page = "1"
+			params.PageInt = 1
+		}
+	}
+
+	sortType := params.SortType
+	if reverse {
+		sortType = modules.AorB(sortType == "desc", "asc", "desc")
+	}
+
+	orderBy := sortField + " " + sortType
+	if keyset && params.SortField != tb.PrimaryKey.Name {
+		orderBy += ", " + pk + " " + sortType
+	}
+
 	if len(ids) > 0 {
 		for _, value := range ids {
 			if value != "" {
 				wheres += value + ","
 			}
 		}
-		wheres = wheres[:len(wheres)-1]
+		wheres = pk + " in (" + wheres[:len(wheres)-1] + ")"
+		wheres, whereArgs = tb.rowPolicyStatement(wheres, whereArgs)
+		countWheres = wheres
+		args = append(args, whereArgs...)
 	} else {
 
 		// parameter
-		wheres, whereArgs, existKeys = params.Statement(wheres, tb.Info.Table, connection.GetDelimiter(), whereArgs, columns, existKeys,
+		wheres, whereArgs, existKeys = params.Statement(wheres, tb.Info.Table, connection.GetDelimiter(), whereArgs, tb.filterColumns(columns), existKeys,
 			tb.Info.FieldList.GetFieldFilterProcessValue, tb.Info.FieldList.GetFieldJoinTable)
 		// pre query
 		wheres, whereArgs = tb.Info.Wheres.Statement(wheres, connection.GetDelimiter(), whereArgs, existKeys, columns)
 		wheres, whereArgs = tb.Info.WhereRaws.Statement(wheres, whereArgs)
+		wheres, whereArgs = tb.rowPolicyStatement(wheres, whereArgs)
+
+		if wheres != "" {
+			countWheres = " where " + wheres
+		}
+
+		args = append(args, whereArgs...)
+
+		if cursor != nil {
+			op := modules.AorB(sortType == "desc", "<", ">")
+			cond := pk + " " + op + " ?"
+			if params.SortField != tb.PrimaryKey.Name {
+				cond = "(" + sortField + " " + op + " ? or (" + sortField + " = ? and " + pk + " " + op + " ?))"
+				args = append(args, cursor[0], cursor[0])
+			}
+			args = append(args, cursor[1])
+			if wheres != "" {
+				wheres = "(" + wheres + ") and " + cond
+			} else {
+				wheres = cond
+			}
+		}
 
 		if wheres != "" {
 			wheres = " where " + wheres
 		}
 
 		if connection.Name() == "mssql" {
-			args = append(whereArgs, (params.PageInt-1)*params.PageSizeInt, params.PageInt*params.PageSizeInt)
+			args = append(args, offset, offset+limit)
 		} else {
-			args = append(whereArgs, params.PageSize, (params.PageInt-1)*params.PageSizeInt)
+			args = append(args, limit, offset)
 		}
 	}
 
 	groupBy := ""
-	if len(joinTables) > 0 {
+	if group {
+		if len(ids) == 0 {
+			countStatement = strings.Replace(countStatement, "count(*)", "count(distinct "+pk+")", 1)
+		}
 		if connection.Name() == "mssql" {
 			groupBy = " GROUP BY " + fields
 		} else {
@@ -443,68 +563,484 @@ func (tb DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelIn
 
 	queryCmd := ""
 	if connection.Name() == "mssql" && len(ids) == 0 {
-		queryCmd = fmt.Sprintf(queryStatement, tb.Info.Table, params.SortField, params.SortType,
-			allFields, tb.Info.Table, joins, wheres, groupBy)
+		queryCmd = fmt.Sprintf(queryStatement, orderBy, allFields, tb.Info.Table, joins, wheres, groupBy)
 	} else {
-		queryCmd = fmt.Sprintf(queryStatement, allFields, tb.Info.Table, joins, wheres, groupBy,
-			tb.Info.Table, params.SortField, params.SortType)
+		queryCmd = fmt.Sprintf(queryStatement, allFields, tb.Info.Table, joins, wheres, groupBy, orderBy)
 	}
 
 	logger.LogSQL(queryCmd, args)
 
-	res, err := connection.QueryWithConnection(tb.connection, queryCmd, args...)
+	readConn := tb.readConnection()
+
+	res, err := tb.queryRead(ctx, readConn, queryCmd, args...)
 
 	if err != nil {
 		return PanelInfo{}, err
 	}
 
+	hasMore := len(ids) == 0 && len(res) > params.PageSizeInt
+	if hasMore {
+		res = res[:params.PageSizeInt]
+	}
+
+	if reverse {
+		for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+			res[i], res[j] = res[j], res[i]
+		}
+	}
+
 	infoList := make([]map[string]types.InfoItem, 0)
 
 	for i := 0; i < len(res); i++ {
 		infoList = append(infoList, tb.getTempModelData(res[i], params, columns))
 	}
 
-	// TODO: use the dialect
+	var (
+		size      = -1
+		estimated = false
+	)
 
-	if len(ids) > 0 {
-		joins = ""
+	if len(ids) > 0 || tb.Info.CountMode == types.CountExact {
+		// TODO: use the dialect
+
+		if len(ids) > 0 {
+			joins = ""
+		}
+
+		countCmd := fmt.Sprintf(countStatement, tb.Info.Table, joins, countWheres)
+
+		total, err := tb.queryRead(ctx, readConn, countCmd, whereArgs...)
+
+		if err != nil {
+			return PanelInfo{}, err
+		}
+
+		logger.LogSQL(countCmd, nil)
+
+		// the count is the only column, whose name depends on the driver.
+		for _, count := range total[0] {
+			size = countValue(count)
+		}
+	} else if tb.Info.CountMode == types.CountEstimate && countWheres == "" {
+		// the estimate is of the whole table, so the total of the filtered
+		// rows is unknown.
+		size, estimated = tb.estimateCount(ctx, readConn), true
+	}
+
+	var aggregates map[string]template.HTML
+
+	if len(ids) == 0 && tb.Info.FieldList.HasAggregate(columns) {
+		aggregates, err = tb.getAggregates(ctx, readConn, tb.aggregateFrom(joins, countWheres, group), whereArgs, columns)
+		if err != nil {
+			return PanelInfo{}, err
+		}
+	}
+
+	endTime := time.Now()
+
+	info := PanelInfo{
+		Thead:          thead,
+		InfoList:       infoList,
+		Total:          size,
+		Title:          tb.Info.Title,
+		FilterFormData: filterForm,
+		Description:    tb.Info.Description,
+		Aggregates:     aggregates,
+	}
+
+	cfg := paginator.Config{
+		Size:          size,
+		Param:         params,
+		PageSizeList:  tb.Info.GetPageSizeList(),
+		SizeEstimated: estimated,
+	}
+
+	if keyset {
+		// the previous pages are run out, so it is the first page.
+		if reverse && !hasMore {
+			params.Page = "1"
+			params.PageInt = 1
+			cfg.Param = params
+		}
+		c := &paginator.Cursor{
+			Rows:        len(res),
+			HasPrevious: (reverse && hasMore) || (!reverse && cursor != nil),
+			HasNext:     reverse || hasMore,
+		}
+		if c.HasPrevious && len(res) > 0 {
+			c.Previous = tb.cursor(res[0], params.SortField)
+			info.PreviousCursor = c.Previous
+		}
+		if c.HasNext && len(res) > 0 {
+			c.Next = tb.cursor(res[len(res)-1], params.SortField)
+			info.NextCursor = c.Next
+		}
+		cfg.Cursor = c
+	} else if size < 0 && len(ids) == 0 {
+		cfg.Cursor = &paginator.Cursor{
+			Rows:        len(res),
+			HasPrevious: params.PageInt > 1,
+			HasNext:     hasMore,
+		}
 	}
 
-	countCmd := fmt.Sprintf(countStatement, tb.Info.Table, joins, wheres)
+	info.Paginator = paginator.Get(cfg).SetExtraInfo(queryTime(beginTime, endTime))
+
+	return info, nil
+}
+
+// groupCountAlias is the column alias of the row count of a group.
+const groupCountAlias = "__goadmin_group_count"
+
+// getGroupedDataFromDatabase query the subtotals of the filtered rows which
+// are grouped by the field, a page of the groups is shown.
+func (tb DefaultTable) getGroupedDataFromDatabase(ctx context.Context, params parameter.Parameters,
+	groupField types.Field, columns Columns) (PanelInfo, error) {
 
-	total, err := connection.QueryWithConnection(tb.connection, countCmd, whereArgs...)
+	var (
+		connection = tb.db()
+		delimiter  = connection.GetDelimiter()
+		column     = tb.Info.Table + "." + modules.FilterField(groupField.Field, delimiter)
+		beginTime  = time.Now()
+	)
+
+	thead, _, _, joins, joinTables, filterForm := tb.getTheadAndFilterForm(params, columns)
+
+	// only the group and the aggregates of the subtotals are sortable.
+	for i := range thead {
+		field := tb.Info.FieldList.GetFieldByFieldName(thead[i].Field)
+		thead[i].Editable = false
+		thead[i].Sortable = field.Field == groupField.Field || field.IsAggregated(columns)
+	}
+
+	var (
+		wheres    = ""
+		whereArgs = make([]interface{}, 0)
+		existKeys = make([]string, 0)
+	)
+
+	wheres, whereArgs, existKeys = params.Statement(wheres, tb.Info.Table, delimiter, whereArgs, tb.filterColumns(columns), existKeys,
+		tb.Info.FieldList.GetFieldFilterProcessValue, tb.Info.FieldList.GetFieldJoinTable)
+	wheres, whereArgs = tb.Info.Wheres.Statement(wheres, delimiter, whereArgs, existKeys, columns)
+	wheres, whereArgs = tb.Info.WhereRaws.Statement(wheres, whereArgs)
+	wheres, whereArgs = tb.rowPolicyStatement(wheres, whereArgs)
+
+	if wheres != "" {
+		wheres = " where " + wheres
+	}
+
+	from := tb.aggregateFrom(joins, wheres, len(joinTables) > 0 && tb.Info.FieldList.IsGroupBy())
+
+	fields := column + " as " + modules.FilterField(groupField.Field, delimiter) +
+		", count(*) as " + modules.FilterField(groupCountAlias, delimiter)
+	if aggregateFields := tb.Info.FieldList.AggregateFields(tb.Info.Table, delimiter, columns); aggregateFields != "" {
+		fields += "," + aggregateFields
+	}
+
+	orderBy, sortType := column, "asc"
+	if params.SortField == groupField.Field {
+		sortType = params.SortType
+	} else if field := tb.Info.FieldList.GetFieldByFieldName(params.SortField); field.IsAggregated(columns) {
+		orderBy = field.Aggregates[0].Statement(tb.Info.Table + "." + modules.FilterField(field.Field, delimiter))
+		sortType = params.SortType
+	}
+	orderBy += " " + sortType
+
+	var (
+		queryCmd string
+		args     = append([]interface{}{}, whereArgs...)
+		offset   = (params.PageInt - 1) * params.PageSizeInt
+	)
+
+	if connection.Name() == "mssql" {
+		queryCmd = "SELECT * FROM (SELECT ROW_NUMBER() OVER (ORDER BY " + orderBy + ") as ROWNUMBER_, " + fields +
+			" from " + from + " GROUP BY " + column + ") as TMP_ WHERE TMP_.ROWNUMBER_ > ? AND TMP_.ROWNUMBER_ <= ?"
+		args = append(args, offset, offset+params.PageSizeInt)
+	} else {
+		queryCmd = "select " + fields + " from " + from + " group by " + column + " order by " + orderBy + " LIMIT ? OFFSET ?"
+		args = append(args, params.PageSizeInt, offset)
+	}
+
+	logger.LogSQL(queryCmd, args)
+
+	readConn := tb.readConnection()
+
+	res, err := tb.queryRead(ctx, readConn, queryCmd, args...)
 
 	if err != nil {
 		return PanelInfo{}, err
 	}
 
-	logger.LogSQL(countCmd, nil)
+	countCmd := "select count(*) from (select " + column + " from " + from + " group by " + column + ") groups_"
 
-	var size int
-	if tb.connectionDriver == "postgresql" {
-		size = int(total[0]["count"].(int64))
-	} else if tb.connectionDriver == "mssql" {
-		size = int(total[0]["size"].(int64))
-	} else {
-		size = int(total[0]["count(*)"].(int64))
+	logger.LogSQL(countCmd, whereArgs)
+
+	total, err := tb.queryRead(ctx, readConn, countCmd, whereArgs...)
+
+	if err != nil {
+		return PanelInfo{}, err
+	}
+
+	size := 0
+	if len(total) > 0 {
+		for _, count := range total[0] {
+			size = countValue(count)
+		}
+	}
+
+	infoList := make([]map[string]types.InfoItem, len(res))
+
+	for i := 0; i < len(res); i++ {
+		infoList[i] = tb.getGroupModelData(res[i], params, groupField, columns)
+	}
+
+	var aggregates map[string]template.HTML
+
+	if tb.Info.FieldList.HasAggregate(columns) {
+		aggregates, err = tb.getAggregates(ctx, readConn, from, whereArgs, columns)
+		if err != nil {
+			return PanelInfo{}, err
+		}
 	}
 
 	endTime := time.Now()
 
 	return PanelInfo{
-		Thead:    thead,
-		InfoList: infoList,
-		Paginator: tb.GetPaginator(size, params,
-			template.HTML(fmt.Sprintf("<b>"+language.Get("query time")+": </b>"+
-				fmt.Sprintf("%.3fms", endTime.Sub(beginTime).Seconds()*1000)))),
+		Thead:          thead,
+		InfoList:       infoList,
+		Total:          size,
 		Title:          tb.Info.Title,
 		FilterFormData: filterForm,
 		Description:    tb.Info.Description,
+		Aggregates:     aggregates,
+		GroupBy:        groupField.Field,
+		Paginator: paginator.Get(paginator.Config{
+			Size:         size,
+			Param:        params,
+			PageSizeList: tb.Info.GetPageSizeList(),
+		}).SetExtraInfo(queryTime(beginTime, endTime)),
 	}, nil
 }
 
+// getGroupModelData return the subtotals of a group, the fields neither
+// grouped nor aggregated are empty. The subtotals have no primary key.
+func (tb DefaultTable) getGroupModelData(row map[string]interface{}, params parameter.Parameters,
+	groupField types.Field, columns Columns) map[string]types.InfoItem {
+
+	var (
+		data  = make(map[string]types.InfoItem)
+		cells = tb.aggregateCells(row, columns)
+	)
+
+	for _, field := range tb.Info.FieldList {
+
+		headField := field.HeadField()
+
+		if field.Hide || !modules.InArrayWithoutEmpty(params.Columns, headField) {
+			continue
+		}
+
+		if field.Field != groupField.Field {
+			data[headField] = types.InfoItem{Content: cells[headField]}
+			continue
+		}
+
+		value := db.GetValueFromDatabaseType(field.TypeName, row[field.Field], false).String()
+		var content template.HTML
+		switch display := field.ToDisplay(types.FieldModel{Value: value, Row: row}).(type) {
+		case string:
+			content = template.HTML(display)
+		case template.HTML:
+			content = display
+		}
+		data[headField] = types.InfoItem{
+			Content: content + template.HTML(` <span class="label label-default">`+cursorValue(row[groupCountAlias])+`</span>`),
+			Value:   value,
+		}
+	}
+
+	data[tb.PrimaryKey.Name] = types.InfoItem{}
+	return data
+}
+
+// aggregateFrom return the from clause of the aggregates over the filtered
+// rows. When the joins multiply the rows, the rows are queried by their
+// primary keys so that each of them is aggregated once.
+func (tb DefaultTable) aggregateFrom(joins, wheres string, group bool) string {
+	delimiter := tb.db().GetDelimiter()
+	table := modules.Delimiter(delimiter, tb.Info.Table)
+	if !group {
+		return table + " " + joins + " " + wheres
+	}
+	pk := tb.Info.Table + "." + modules.Delimiter(delimiter, tb.PrimaryKey.Name)
+	return table + " where " + pk + " in (select " + pk + " from " + table + " " + joins + " " + wheres + ")"
+}
+
+// getAggregates query the aggregates of the fields over the rows of the
+// from clause, which are the contents of the footer row.
+func (tb DefaultTable) getAggregates(ctx context.Context, conn, from string, args []interface{},
+	columns Columns) (map[string]template.HTML, error) {
+
+	queryCmd := "select " + tb.Info.FieldList.AggregateFields(tb.Info.Table, tb.db().GetDelimiter(), columns) + " from " + from
+
+	logger.LogSQL(queryCmd, args)
+
+	res, err := tb.queryRead(ctx, conn, queryCmd, args...)
+
+	if err != nil || len(res) == 0 {
+		return nil, err
+	}
+
+	return tb.aggregateCells(res[0], columns), nil
+}
+
+// aggregateCells return the contents of the aggregates of the row keyed by
+// the field, the aggregates of a field are shown one per line.
+func (tb DefaultTable) aggregateCells(row map[string]interface{}, columns Columns) map[string]template.HTML {
+	cells := make(map[string]template.HTML)
+	for _, field := range tb.Info.FieldList {
+		if !field.IsAggregated(columns) {
+			continue
+		}
+		lines := make([]string, len(field.Aggregates))
+		for i, aggregate := range field.Aggregates {
+			lines[i] = language.Get(string(aggregate)) + ": " +
+				template.HTMLEscapeString(aggregateValue(row[aggregate.Alias(field.Field)]))
+		}
+		cells[field.Field] = template.HTML(strings.Join(lines, "<br>"))
+	}
+	return cells
+}
+
+// aggregateValue format the value of an aggregate, the decimals are rounded
+// to two places.
+func aggregateValue(value interface{}) string {
+	str := cursorValue(value)
+	if strings.Contains(str, ".") {
+		if f, err := strconv.ParseFloat(str, 64); err == nil {
+			return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+		}
+	}
+	return str
+}
+
+// Search query the rows of which a searchable field contains the keyword,
+// the wheres of the info panel are applied too. The rows of the primary
+// key and the searchable fields are returned, at most limit rows. It returns
+// nothing if the data are not from the database.
+func (tb DefaultTable) Search(ctx context.Context, keyword string, limit int) ([]map[string]interface{}, error) {
+
+	if tb.getDataFun != nil || tb.sourceURL != "" || tb.Info.GetDataFn != nil || keyword == "" {
+		return nil, nil
+	}
+
+	var (
+		connection = tb.db()
+		delimiter  = connection.GetDelimiter()
+		fields     = tb.Info.Table + "." + modules.FilterField(tb.PrimaryKey.Name, delimiter)
+		wheres     = ""
+		whereArgs  = make([]interface{}, 0)
+	)
+
+	columns, _ := tb.getColumns(tb.Info.Table)
+
+	for _, field := range tb.Info.FieldList {
+		if !field.Searchable || field.JoinChain().Valid() || !modules.InArray(columns, field.Field) {
+			continue
+		}
+		column := tb.Info.Table + "." + modules.FilterField(field.Field, delimiter)
+		if field.Field != tb.PrimaryKey.Name {
+			fields += "," + column
+		}
+		if connection.Name() == "postgresql" {
+			wheres += "cast(" + column + " as text) ilike ? or "
+		} else {
+			wheres += column + " like ? or "
+		}
+		whereArgs = append(whereArgs, "%"+keyword+"%")
+	}
+
+	if wheres == "" {
+		return nil, nil
+	}
+
+	wheres = "(" + wheres[:len(wheres)-4] + ")"
+	wheres, whereArgs = tb.Info.Wheres.Statement(wheres, delimiter, whereArgs, []string{}, columns)
+	wheres, whereArgs = tb.Info.WhereRaws.Statement(wheres, whereArgs)
+	wheres, whereArgs = tb.rowPolicyStatement(wheres, whereArgs)
+
+	orderBy := tb.Info.Table + "." + modules.FilterField(tb.PrimaryKey.Name, delimiter) + " desc"
+
+	queryCmd := ""
+	if connection.Name() == "mssql" {
+		queryCmd = "select top " + strconv.Itoa(limit) + " " + fields + " from " + modules.Delimiter(delimiter, tb.Info.Table) +
+			" where " + wheres + " order by " + orderBy
+	} else {
+		queryCmd = "select " + fields + " from " + modules.Delimiter(delimiter, tb.Info.Table) +
+			" where " + wheres + " order by " + orderBy + " limit " + strconv.Itoa(limit)
+	}
+
+	logger.LogSQL(queryCmd, whereArgs)
+
+	return tb.queryRead(ctx, tb.readConnection(), queryCmd, whereArgs...)
+}
+
+// cursor return the keyset cursor of the row.
+func (tb DefaultTable) cursor(row map[string]interface{}, sortField string) string {
+	return parameter.EncodeCursor(cursorValue(row[sortField]), cursorValue(row[tb.PrimaryKey.Name]))
+}
+
+func cursorValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999999")
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// queryTime return the extra info of the paginator showing the time of
+// the queries.
+func queryTime(beginTime, endTime time.Time) template.HTML {
+	return template.HTML("<b>" + language.Get("query time") + ": </b>" +
+		fmt.Sprintf("%.3fms", endTime.Sub(beginTime).Seconds()*1000))
+}
+
+// countValue convert the result of a count statement, which is scanned
+// as an int64, a []byte or a string depending on the driver, to int.
+func countValue(value interface{}) int {
+	count, _ := strconv.ParseFloat(cursorValue(value), 64)
+	return int(count)
+}
+
+// estimateCount return the row count of the table estimated by the
+// database, or the exact count when there is no estimate.
+func (tb DefaultTable) estimateCount(ctx context.Context, conn string) int {
+	statement, args := dialect.GetDialectByDriver(tb.connectionDriver).EstimateCount(tb.Info.Table)
+	res, err := tb.queryRead(ctx, conn, statement, args...)
+	// there is no estimate of an empty sqlite table or a postgresql table
+	// never analyzed, but an estimate of 0 is kept.
+	if err == nil && len(res) > 0 && res[0]["estimate"] != nil {
+		estimate, err := strconv.ParseFloat(cursorValue(res[0]["estimate"]), 64)
+		if err == nil && estimate >= 0 {
+			return int(estimate)
+		}
+	}
+	count, _ := db.WithDriverAndConnection(conn, tb.db()).WithContext(ctx).Table(tb.Info.Table).Count()
+	return int(count)
+}
+
 // GetDataWithId query the single row of data.
 func (tb DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, error) {
+	return tb.GetDataWithIdWithContext(context.Background(), param)
+}
+
+// GetDataWithIdWithContext query the single row of data with the context,
+// the queries are canceled when the context is done.
+func (tb DefaultTable) GetDataWithIdWithContext(ctx context.Context, param parameter.Parameters) (FormInfo, error) {
 
 	var (
 		res     map[string]interface{}
@@ -520,7 +1056,7 @@ func (tb DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, erro
 		}
 		custom = true
 	} else if tb.sourceURL != "" {
-		list, _ := tb.getDataFromURL(param)
+		list, _ := tb.getDataFromURL(ctx, param)
 		if len(list) > 0 {
 			res = list[0]
 		}
@@ -539,6 +1075,10 @@ func (tb DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, erro
 		custom = true
 	} else {
 
+		if err := tb.checkScope(nil, id); err != nil {
+			return FormInfo{Title: tb.Form.Title, Description: tb.Form.Description}, err
+		}
+
 		columns, _ = tb.getColumns(tb.Form.Table)
 
 		var (
@@ -552,7 +1092,9 @@ func (tb DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, erro
 			}
 		}
 
-		res, err = tb.sql().
+		// the row is read from the primary, as a row just written may not
+		// be replicated yet, and the form is saved with the values of it.
+		res, err = tb.sql().WithContext(ctx).
 			Table(tb.Form.Table).Select(fields...).
 			Where(tb.PrimaryKey.Name, "=", id).
 			First()
@@ -560,6 +1102,14 @@ func (tb DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, erro
 		if err != nil {
 			return FormInfo{Title: tb.Form.Title, Description: tb.Form.Description}, err
 		}
+
+		if err = tb.getRelationValues(ctx, id, res); err != nil {
+			return FormInfo{Title: tb.Form.Title, Description: tb.Form.Description}, err
+		}
+
+		if err = tb.getHasManyValues(ctx, id, res); err != nil {
+			return FormInfo{Title: tb.Form.Title, Description: tb.Form.Description}, err
+		}
 	}
 
 	var (
@@ -601,39 +1151,98 @@ func (tb DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, erro
 // UpdateData update data.
 func (tb DefaultTable) UpdateData(dataList form.Values) error {
 
+	defer tb.invalidateCache()
+
 	dataList.Add(form.PostTypeKey, "0")
 
+	if err := tb.checkFieldAccess(dataList); err != nil {
+		return err
+	}
+
 	if tb.Form.Validator != nil {
 		if err := tb.Form.Validator(dataList); err != nil {
 			return err
 		}
 	}
 
+	if err := tb.validateHasMany(dataList); err != nil {
+		return err
+	}
+
+	record := AuditRecord{
+		PK: dataList.Get(tb.PrimaryKey.Name),
+		Op: modules.AorB(dataList.IsSingleUpdatePost(), AuditInlineUpdate, AuditUpdate),
+	}
+
+	if err := tb.checkScope(nil, record.PK); err != nil {
+		return err
+	}
+
 	if tb.Form.UpdateFn != nil {
 		dataList.Delete(form.PostTypeKey)
-		return tb.Form.UpdateFn(dataList)
+		if err := tb.Form.UpdateFn(dataList); err != nil {
+			return err
+		}
+		tb.audit(record)
+		return nil
 	}
 
 	if tb.Form.PreProcessFn != nil {
 		dataList = tb.Form.PreProcessFn(dataList)
 	}
 
-	_, err := tb.sql().Table(tb.Form.Table).
-		Where(tb.PrimaryKey.Name, "=", dataList.Get(tb.PrimaryKey.Name)).
-		Update(tb.getInjectValueFromFormValue(dataList))
+	_, err := tb.sql().WithTransaction(func(tx *dbsql.Tx) (error, map[string]interface{}) {
 
-	// TODO: some errors should be ignored.
-	if err != nil && !strings.Contains(err.Error(), "no affect") {
-		if tb.connectionDriver != db.DriverPostgresql && tb.connectionDriver != db.DriverMssql {
-			return err
+		var before map[string]interface{}
+		if tb.auditor != nil {
+			before, _ = tb.sql().WithTx(tx).Table(tb.Form.Table).
+				Where(tb.PrimaryKey.Name, "=", record.PK).
+				First()
 		}
-		if !strings.Contains(err.Error(), "LastInsertId is not supported") &&
-			!strings.Contains(err.Error(), "There is no generated identity value") {
-			return err
+
+		value := tb.getInjectValueFromFormValue(dataList, tx)
+
+		_, err := tb.sql().WithTx(tx).Table(tb.Form.Table).
+			Where(tb.PrimaryKey.Name, "=", record.PK).
+			Update(value)
+
+		// the row may be unchanged by the submitted values.
+		if err != nil && !strings.Contains(err.Error(), "no affect") {
+			return err, nil
+		}
+
+		// the row can not be moved out of the scope of the user either.
+		if err := tb.checkScope(tx, record.PK); err != nil {
+			return err, nil
 		}
+
+		if err := tb.syncRelations(tx, record.PK, dataList, false); err != nil {
+			return err, nil
+		}
+
+		if err := tb.syncHasMany(tx, record.PK, dataList, false); err != nil {
+			return err, nil
+		}
+
+		if tb.Form.TxPostHook != nil {
+			dataList.Add(form.PostTypeKey, "0")
+			if err := tb.Form.TxPostHook(tx, dataList); err != nil {
+				return err, nil
+			}
+		}
+
+		if before != nil {
+			record.Changes = tb.diff(before, value)
+		}
+
+		return nil, nil
+	})
+
+	if err != nil {
+		return err
 	}
 
-	// NOTE: Database Transaction may be considered here.
+	tb.audit(record)
 
 	if tb.Form.PostHook != nil {
 		go func() {
@@ -659,38 +1268,331 @@ func (tb DefaultTable) UpdateData(dataList form.Values) error {
 // InsertData insert data.
 func (tb DefaultTable) InsertData(dataList form.Values) error {
 
+	defer tb.invalidateCache()
+
 	dataList.Add(form.PostTypeKey, "1")
 
+	if err := tb.checkFieldAccess(dataList); err != nil {
+		return err
+	}
+
 	if tb.Form.Validator != nil {
 		if err := tb.Form.Validator(dataList); err != nil {
 			return err
 		}
 	}
 
+	if err := tb.validateHasMany(dataList); err != nil {
+		return err
+	}
+
+	record := AuditRecord{Op: AuditInsert}
+
 	if tb.Form.InsertFn != nil {
 		dataList.Delete(form.PostTypeKey)
-		return tb.Form.InsertFn(dataList)
+		if err := tb.Form.InsertFn(dataList); err != nil {
+			return err
+		}
+		record.PK = dataList.Get(tb.PrimaryKey.Name)
+		tb.audit(record)
+		return nil
 	}
 
 	if tb.Form.PreProcessFn != nil {
 		dataList = tb.Form.PreProcessFn(dataList)
 	}
 
-	id, err := tb.sql().Table(tb.Form.Table).Insert(tb.getInjectValueFromFormValue(dataList))
+	_, err := tb.sql().WithTransaction(func(tx *dbsql.Tx) (error, map[string]interface{}) {
+		var err error
+		record, err = tb.insert(tx, dataList)
+		return err, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	tb.audit(record)
+	tb.postInsertHook(dataList)
+
+	return nil
+}
+
+// insert insert the row of given values within the transaction, and run
+// the TxPostHook of the form.
+func (tb DefaultTable) insert(tx *dbsql.Tx, dataList form.Values) (AuditRecord, error) {
+
+	value := tb.getInjectValueFromFormValue(dataList, tx)
+
+	pk := tb.GetPrimaryKey().Name
+
+	row, err := tb.sql().WithTx(tx).Table(tb.Form.Table).InsertReturning(value, pk)
 
-	// TODO: some errors should be ignored.
 	if err != nil {
-		if tb.connectionDriver != db.DriverPostgresql && tb.connectionDriver != db.DriverMssql {
+		return AuditRecord{}, err
+	}
+
+	dataList.Add(pk, pkValue(row[pk]))
+
+	if err := tb.checkScope(tx, dataList.Get(pk)); err != nil {
+		return AuditRecord{}, err
+	}
+
+	if err := tb.syncRelations(tx, dataList.Get(pk), dataList, true); err != nil {
+		return AuditRecord{}, err
+	}
+
+	if err := tb.syncHasMany(tx, dataList.Get(pk), dataList, true); err != nil {
+		return AuditRecord{}, err
+	}
+
+	if tb.Form.TxPostHook != nil {
+		dataList.Add(form.PostTypeKey, "1")
+		if err := tb.Form.TxPostHook(tx, dataList); err != nil {
+			return AuditRecord{}, err
+		}
+	}
+
+	return AuditRecord{
+		PK:      dataList.Get(tb.PrimaryKey.Name),
+		Op:      AuditInsert,
+		Changes: tb.diff(nil, value),
+	}, nil
+}
+
+// syncRelations sync the links of the row in the pivot tables of the
+// relation fields with the submitted values. Only the removed links are
+// deleted and the new ones inserted, so the kept links are left as they
+// are. The links of a relation field which is not submitted, such as by a
+// single field update, are kept.
+func (tb DefaultTable) syncRelations(tx *dbsql.Tx, id string, dataList form.Values, insert bool) error {
+	for _, field := range tb.Form.FieldList {
+		if !field.IsRelation() || (insert && field.NotAllowAdd) || (!insert && !field.Editable) {
+			continue
+		}
+
+		values, ok := dataList[field.Field+"[]"]
+		if !ok {
+			values, ok = dataList[field.Field]
+		}
+		if !ok {
+			continue
+		}
+
+		linked := make([]string, 0)
+		if !insert {
+			rows, err := tb.sql().WithTx(tx).Table(field.Relation.Pivot).
+				Select(field.Relation.ForeignKey).
+				Where(field.Relation.LocalKey, "=", id).
+				All()
+			if err != nil {
+				return err
+			}
+			for _, row := range rows {
+				linked = append(linked, pkValue(row[field.Relation.ForeignKey]))
+			}
+		}
+
+		added, removed := diffLinks(linked, modules.RemoveBlankFromArray(values))
+
+		if len(removed) > 0 {
+			err := tb.sql().WithTx(tx).Table(field.Relation.Pivot).
+				Where(field.Relation.LocalKey, "=", id).
+				WhereIn(field.Relation.ForeignKey, interfaces(removed)).
+				Delete()
+			if err != nil && notNoAffectRow(err) {
+				return err
+			}
+		}
+
+		for _, value := range added {
+			_, err := tb.sql().WithTx(tx).Table(field.Relation.Pivot).Insert(dialect.H{
+				field.Relation.LocalKey:   id,
+				field.Relation.ForeignKey: value,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// diffLinks return the posted values which are not linked yet, and the
+// linked values which are not posted any more.
+func diffLinks(linked, posted []string) (added, removed []string) {
+	added, removed = make([]string, 0), make([]string, 0)
+	for _, value := range posted {
+		if !modules.InArray(linked, value) && !modules.InArray(added, value) {
+			added = append(added, value)
+		}
+	}
+	for _, value := range linked {
+		if !modules.InArray(posted, value) && !modules.InArray(removed, value) {
+			removed = append(removed, value)
+		}
+	}
+	return added, removed
+}
+
+// getRelationValues set the linked values of the relation fields to the row.
+func (tb DefaultTable) getRelationValues(ctx context.Context, id string, res map[string]interface{}) error {
+	for _, field := range tb.Form.FieldList {
+		if !field.IsRelation() {
+			continue
+		}
+
+		rows, err := tb.sql().WithContext(ctx).Table(field.Relation.Pivot).
+			Select(field.Relation.ForeignKey).
+			Where(field.Relation.LocalKey, "=", id).
+			All()
+		if err != nil {
 			return err
 		}
-		if !strings.Contains(err.Error(), "LastInsertId is not supported") &&
-			!strings.Contains(err.Error(), "There is no generated identity value") {
+
+		values := make([]string, len(rows))
+		for i, row := range rows {
+			values[i] = pkValue(row[field.Relation.ForeignKey])
+		}
+		res[field.Field] = values
+	}
+	return nil
+}
+
+// validateHasMany validate the posted child rows of the has many fields.
+func (tb DefaultTable) validateHasMany(dataList form.Values) error {
+	for _, field := range tb.Form.FieldList {
+		if !field.IsHasMany() {
+			continue
+		}
+		if rows, ok := field.HasMany.Rows(field.Field, dataList); ok {
+			if err := field.HasMany.Validate(field.Head, rows); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// syncHasMany save the posted child rows of the has many fields. The child
+// rows with a primary key are updated, the others are inserted, and the
+// existing child rows which are not posted are deleted.
+func (tb DefaultTable) syncHasMany(tx *dbsql.Tx, id string, dataList form.Values, insert bool) error {
+	for _, field := range tb.Form.FieldList {
+		if !field.IsHasMany() || (insert && field.NotAllowAdd) || (!insert && !field.Editable) {
+			continue
+		}
+
+		rows, ok := field.HasMany.Rows(field.Field, dataList)
+		if !ok {
+			continue
+		}
+
+		var (
+			child         = field.HasMany
+			columns, auto = tb.getColumnsWithTx(tx, child.Form.Table)
+			existing      = make(map[string]bool)
+			except        = []string{child.PrimaryKey, child.ForeignKey}
+		)
+
+		if !insert {
+			list, err := tb.sql().WithTx(tx).Table(child.Form.Table).
+				Select(child.PrimaryKey).
+				Where(child.ForeignKey, "=", id).
+				All()
+			if err != nil {
+				return err
+			}
+			for _, row := range list {
+				existing[pkValue(row[child.PrimaryKey])] = true
+			}
+		}
+
+		for i, row := range rows {
+			pk := row.Get(child.PrimaryKey)
+			if existing[pk] {
+				// the passwords are rendered blank, keep the saved ones
+				// unless the new ones are given.
+				for _, f := range child.Form.FieldList {
+					if f.FormType == form2.Password && row.Get(f.Field) == "" {
+						row.Delete(f.Field)
+					}
+				}
+			}
+			value := formValue(child.Form.FieldList, columns, except, pk, row, tx)
+			value[child.ForeignKey] = id
+			if child.OrderField != "" {
+				value[child.OrderField] = i + 1
+			}
+
+			if existing[pk] {
+				delete(existing, pk)
+				_, err := tb.sql().WithTx(tx).Table(child.Form.Table).
+					Where(child.PrimaryKey, "=", pk).
+					Update(value)
+				if err != nil && notNoAffectRow(err) {
+					return err
+				}
+				continue
+			}
+
+			if !auto && pk != "" {
+				value[child.PrimaryKey] = pk
+			}
+			if _, err := tb.sql().WithTx(tx).Table(child.Form.Table).Insert(value); err != nil {
+				return err
+			}
+		}
+
+		if len(existing) > 0 {
+			ids := make([]interface{}, 0, len(existing))
+			for pk := range existing {
+				ids = append(ids, pk)
+			}
+			err := tb.sql().WithTx(tx).Table(child.Form.Table).
+				Where(child.ForeignKey, "=", id).
+				WhereIn(child.PrimaryKey, ids).
+				Delete()
+			if err != nil && notNoAffectRow(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getHasManyValues set the child rows of the has many fields to the row.
+func (tb DefaultTable) getHasManyValues(ctx context.Context, id string, res map[string]interface{}) error {
+	for _, field := range tb.Form.FieldList {
+		if !field.IsHasMany() {
+			continue
+		}
+
+		child := field.HasMany
+		rows, err := tb.sql().WithContext(ctx).Table(child.Form.Table).
+			Where(child.ForeignKey, "=", id).
+			OrderBy(modules.AorB(child.OrderField != "", child.OrderField, child.PrimaryKey), "asc").
+			All()
+		if err != nil {
 			return err
 		}
+		res[field.Field] = rows
 	}
+	return nil
+}
 
-	dataList.Add(tb.GetPrimaryKey().Name, strconv.Itoa(int(id)))
+// pkValue format the primary key returned by the insertion.
+func pkValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	}
+	return fmt.Sprintf("%v", value)
+}
 
+func (tb DefaultTable) postInsertHook(dataList form.Values) {
 	if tb.Form.PostHook != nil {
 		go func() {
 
@@ -708,19 +1610,14 @@ func (tb DefaultTable) InsertData(dataList form.Values) error {
 			}
 		}()
 	}
-
-	return nil
 }
 
-func (tb DefaultTable) getInjectValueFromFormValue(dataList form.Values) dialect.H {
+func (tb DefaultTable) getInjectValueFromFormValue(dataList form.Values, tx *dbsql.Tx) dialect.H {
 
 	var (
-		value        = make(dialect.H)
 		exceptString = make([]string, 0)
 
-		columns, auto = tb.getColumns(tb.Form.Table)
-
-		fun types.PostFieldFilterFn
+		columns, auto = tb.getColumnsWithTx(tx, tb.Form.Table)
 	)
 
 	if auto {
@@ -729,9 +1626,10 @@ func (tb DefaultTable) getInjectValueFromFormValue(dataList form.Values) dialect
 		exceptString = []string{form.PreviousKey, form.MethodKey, form.TokenKey}
 	}
 
-	if !dataList.IsSingleUpdatePost() {
+	if !dataList.IsPartialUpdatePost() {
 		for _, field := range tb.Form.FieldList {
-			if field.FormType.IsMultiSelect() {
+			// the fields which the user can not change are not posted.
+			if field.FormType.IsMultiSelect() && field.Access == types.FieldEditable {
 				if _, ok := dataList[field.Field+"[]"]; !ok {
 					dataList[field.Field+"[]"] = []string{""}
 				}
@@ -741,22 +1639,37 @@ func (tb DefaultTable) getInjectValueFromFormValue(dataList form.Values) dialect
 
 	dataList = dataList.RemoveRemark()
 
+	return formValue(tb.Form.FieldList, columns, exceptString, dataList.Get(tb.PrimaryKey.Name), dataList, tx)
+}
+
+// formValue return the column values of the posted values, which are
+// processed by the PostFilterFn of the fields.
+func formValue(fields types.FormFields, columns Columns, exceptString []string, id string,
+	dataList form.Values, tx *dbsql.Tx) dialect.H {
+
+	var (
+		value = make(dialect.H)
+
+		fun types.PostFieldFilterFn
+	)
+
 	for k, v := range dataList {
 		k = strings.Replace(k, "[]", "", -1)
 		if !modules.InArray(exceptString, k) {
 			if modules.InArray(columns, k) {
 				delimiter := ","
-				for i := 0; i < len(tb.Form.FieldList); i++ {
-					if k == tb.Form.FieldList[i].Field {
-						fun = tb.Form.FieldList[i].PostFilterFn
-						delimiter = modules.SetDefault(tb.Form.FieldList[i].DefaultOptionDelimiter, ",")
+				for i := 0; i < len(fields); i++ {
+					if k == fields[i].Field {
+						fun = fields[i].PostFilterFn
+						delimiter = modules.SetDefault(fields[i].DefaultOptionDelimiter, ",")
 					}
 				}
 				vv := modules.RemoveBlankFromArray(v)
 				if fun != nil {
 					value[k] = fun(types.PostFieldModel{
-						ID:    dataList.Get(tb.PrimaryKey.Name),
+						ID:    id,
 						Value: vv,
+						Tx:    tx,
 					})
 				} else {
 					if len(vv) > 1 {
@@ -768,11 +1681,12 @@ func (tb DefaultTable) getInjectValueFromFormValue(dataList form.Values) dialect
 					}
 				}
 			} else {
-				fun := tb.Form.FieldList.FindByFieldName(k).PostFilterFn
+				fun := fields.FindByFieldName(k).PostFilterFn
 				if fun != nil {
 					fun(types.PostFieldModel{
-						ID:    dataList.Get(tb.PrimaryKey.Name),
+						ID:    id,
 						Value: modules.RemoveBlankFromArray(v),
+						Tx:    tx,
 					})
 				}
 			}
@@ -783,15 +1697,29 @@ func (tb DefaultTable) getInjectValueFromFormValue(dataList form.Values) dialect
 
 // DeleteData delete data.
 func (tb DefaultTable) DeleteData(id string) error {
+	defer tb.invalidateCache()
+
 	idArr := strings.Split(id, ",")
 
+	if err := tb.checkScope(nil, idArr...); err != nil {
+		return err
+	}
+
 	if tb.Info.DeleteFn != nil {
 
 		if len(idArr) == 0 {
 			return errors.New("wrong parameter")
 		}
 
-		return tb.Info.DeleteFn(idArr)
+		if err := tb.Info.DeleteFn(idArr); err != nil {
+			return err
+		}
+
+		for _, id := range idArr {
+			tb.audit(AuditRecord{PK: id, Op: AuditDelete})
+		}
+
+		return nil
 	}
 
 	if tb.Info.PreDeleteFn != nil && len(idArr) > 0 {
@@ -802,9 +1730,66 @@ func (tb DefaultTable) DeleteData(id string) error {
 
 	tableName := modules.AorB(tb.Info.Table == "", tb.Form.Table, tb.Info.Table)
 
-	// TODO: use where in
-	for _, id := range idArr {
-		tb.delete(tableName, tb.PrimaryKey.Name, id)
+	records := make([]AuditRecord, 0)
+
+	_, err := tb.sql().WithTransaction(func(tx *dbsql.Tx) (error, map[string]interface{}) {
+
+		if tb.auditor != nil {
+			rows, _ := tb.sql().WithTx(tx).Table(tableName).
+				WhereIn(tb.PrimaryKey.Name, interfaces(idArr)).
+				All()
+			for _, row := range rows {
+				records = append(records, AuditRecord{
+					PK:      auditValue(row[tb.PrimaryKey.Name]),
+					Op:      AuditDelete,
+					Changes: tb.diff(row, nil),
+				})
+			}
+		}
+
+		err := tb.sql().WithTx(tx).Table(tableName).
+			WhereIn(tb.PrimaryKey.Name, interfaces(idArr)).
+			Delete()
+
+		if err != nil && notNoAffectRow(err) {
+			return err, nil
+		}
+
+		for _, field := range tb.Form.FieldList {
+			var (
+				table string
+				key   string
+			)
+			if field.IsRelation() {
+				table, key = field.Relation.Pivot, field.Relation.LocalKey
+			} else if field.IsHasMany() {
+				table, key = field.HasMany.Form.Table, field.HasMany.ForeignKey
+			} else {
+				continue
+			}
+			err := tb.sql().WithTx(tx).Table(table).
+				WhereIn(key, interfaces(idArr)).
+				Delete()
+			if err != nil && notNoAffectRow(err) {
+				return err, nil
+			}
+		}
+
+		if tb.Info.TxDeleteHook != nil && len(idArr) > 0 {
+			if err := tb.Info.TxDeleteHook(tx, idArr); err != nil {
+				return err, nil
+			}
+		}
+
+		return nil, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		tb.audit(record)
 	}
 
 	if tb.Info.DeleteHook != nil && len(idArr) > 0 {
@@ -839,12 +1824,6 @@ func (tb DefaultTable) GetNewForm() FormInfo {
 // helper function for database operation
 // ***************************************
 
-func (tb DefaultTable) delete(table, key, id string) {
-	_ = tb.sql().Table(table).
-		Where(key, "=", id).
-		Delete()
-}
-
 func (tb DefaultTable) getTheadAndFilterForm(params parameter.Parameters, columns Columns) (types.Thead,
 	string, string, string, []string, []types.FormField) {
 	return tb.Info.FieldList.GetTheadAndFilterForm(types.TableInfo{
@@ -855,6 +1834,21 @@ func (tb DefaultTable) getTheadAndFilterForm(params parameter.Parameters, column
 	}, params, columns)
 }
 
+// invalidateCache drop the cached data read from the table and the tables
+// written with it, which is called whenever the table may be written.
+func (tb DefaultTable) invalidateCache() {
+	tables := []string{tb.Form.Table, tb.Info.Table}
+	for _, field := range tb.Form.FieldList {
+		if field.IsRelation() {
+			tables = append(tables, field.Relation.Pivot)
+		}
+		if field.IsHasMany() {
+			tables = append(tables, field.HasMany.Form.Table)
+		}
+	}
+	cache.Invalidate(tables...)
+}
+
 // db is a helper function return raw db connection.
 func (tb DefaultTable) db() db.Connection {
 	if tb.connectionDriver != "" && tb.getDataFromDB() {
@@ -882,11 +1876,80 @@ func (tb DefaultTable) sql() *db.SQL {
 	return nil
 }
 
+// sortField return the order by expression of the sort field of params,
+// which is a column of the table or a sortable joined field. The sort
+// field is reset to the primary key if it is neither of them, or it is a
+// joined field and noJoin is true.
+func (tb DefaultTable) sortField(params *parameter.Parameters, columns Columns, group, noJoin bool) string {
+	delimiter := tb.db().GetDelimiter()
+	if modules.InArray(tb.filterColumns(columns), params.SortField) {
+		return tb.Info.Table + "." + modules.Delimiter(delimiter, params.SortField)
+	}
+	field := tb.Info.FieldList.GetFieldByFieldName(params.SortField)
+	if !noJoin && field.Sortable && field.JoinChain().Valid() && field.HeadField() == params.SortField {
+		return field.JoinChain().SortField(types.TableInfo{
+			Table:      tb.Info.Table,
+			Delimiter:  delimiter,
+			Driver:     tb.connectionDriver,
+			PrimaryKey: tb.PrimaryKey.Name,
+		}, field.Field, group)
+	}
+	params.SortField = tb.PrimaryKey.Name
+	return tb.Info.Table + "." + modules.Delimiter(delimiter, params.SortField)
+}
+
+// readConnection return the connection name of the reads, which is one
+// of the healthy replicas or the primary connection.
+func (tb DefaultTable) readConnection() string {
+	return tb.db().ReadConnection(tb.connection, tb.readConnections...)
+}
+
+// queryRead execute the query on the given read connection. When a replica
+// fails and its health check fails too, the query is retried on the primary.
+func (tb DefaultTable) queryRead(ctx context.Context, conn, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	connection := tb.db()
+	res, err := connection.QueryWithConnectionAndContext(ctx, conn, query, args...)
+	if err != nil && conn != tb.connection && ctx.Err() == nil && !connection.CheckConnection(conn) {
+		return connection.QueryWithConnectionAndContext(ctx, tb.connection, query, args...)
+	}
+	return res, err
+}
+
 type Columns []string
 
+// filterColumns return the columns which can be filtered and sorted by, the
+// columns of the fields hidden from the user are left out except the
+// primary key.
+func (tb DefaultTable) filterColumns(columns Columns) Columns {
+	if len(tb.hiddenColumns) == 0 {
+		return columns
+	}
+	list := make(Columns, 0, len(columns))
+	for _, column := range columns {
+		if column == tb.PrimaryKey.Name || !modules.InArray(tb.hiddenColumns, column) {
+			list = append(list, column)
+		}
+	}
+	return list
+}
+
 func (tb DefaultTable) getColumns(table string) (Columns, bool) {
+	return tb.getColumnsWithTx(nil, table)
+}
+
+// getColumnsWithTx return the columns of the table, which are queried
+// within the transaction if tx is not nil, as another connection may wait
+// for the locks of it.
+func (tb DefaultTable) getColumnsWithTx(tx *dbsql.Tx, table string) (Columns, bool) {
+
+	query := func() *db.SQL {
+		if tx != nil {
+			return tb.sql().WithTx(tx)
+		}
+		return tb.sql()
+	}
 
-	columnsModel, _ := tb.sql().Table(table).ShowColumns()
+	columnsModel, _ := query().Table(table).ShowColumns()
 
 	columns := make(Columns, len(columnsModel))
 	switch tb.connectionDriver {
@@ -921,8 +1984,8 @@ func (tb DefaultTable) getColumns(table string) (Columns, bool) {
 			columns[key] = string(model["name"].(string))
 		}
 
-		num, _ := tb.sql().Table("sqlite_sequence").
-			Where("name", "=", tb.GetForm().Table).Count()
+		num, _ := query().Table("sqlite_sequence").
+			Where("name", "=", table).Count()
 
 		return columns, num > 0
 	case "mssql":
@@ -1 +1,441 @@
 package table
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	_ "github.com/GoAdminGroup/go-admin/modules/db/drivers/sqlite"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	template2 "github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/components"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/stretchr/testify/assert"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testDB create a sqlite database of given schema for the tables of the
+// tests, it returns the connection and the function to remove it.
+func testDB(t *testing.T, schema ...string) (db.Connection, func()) {
+	dir, err := ioutil.TempDir("", "table")
+	assert.NoError(t, err)
+
+	conn := db.GetConnectionByDriver(db.DriverSqlite).InitDB(map[string]config.Database{
+		"default": {Driver: db.DriverSqlite, File: filepath.Join(dir, "test.db")},
+	})
+	for _, statement := range schema {
+		_, err := conn.Exec(statement)
+		assert.NoError(t, err)
+	}
+
+	services = service.List{db.DriverSqlite: conn}
+
+	return conn, func() {
+		_ = conn.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func testUserTable() DefaultTable {
+	tb := NewDefaultTable(DefaultConfigWithDriver(db.DriverSqlite)).(DefaultTable)
+	info := tb.GetInfo()
+	info.AddField("ID", "id", db.Int)
+	info.AddField("Name", "name", db.Varchar)
+	info.AddField("Age", "age", db.Int)
+	info.SetTable("users")
+	formList := tb.GetForm()
+	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowAdd()
+	formList.AddField("Name", "name", db.Varchar, form.Text)
+	formList.AddField("Age", "age", db.Int, form.Number)
+	formList.AddField("Tags", "tags", db.Varchar, form.Select)
+	formList.AddField("Roles", "roles", db.Int, form.Select).FieldRelation("user_roles", "user_id", "role_id")
+	formList.SetTable("users")
+	return tb
+}
+
+var testUserSchema = []string{
+	`create table users (id integer primary key autoincrement, name varchar(50), age int, tags varchar(50))`,
+	`create table user_roles (id integer primary key autoincrement, user_id int, role_id int)`,
+	`insert into users (name, age, tags) values ('jack', 10, 'a,b')`,
+	`insert into user_roles (user_id, role_id) values (1, 1), (1, 2)`,
+}
+
+func TestDefaultTable_UpdateDataPartial(t *testing.T) {
+	conn, done := testDB(t, testUserSchema...)
+	defer done()
+
+	tb := testUserTable()
+
+	err := tb.UpdateData(form2.Values{
+		"id":                         {"1"},
+		"age":                        {"11"},
+		form2.PostIsPartialUpdateKey: {"1"},
+	})
+	assert.NoError(t, err)
+
+	user, err := db.WithDriver(conn).Table("users").Where("id", "=", 1).First()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), user["age"])
+	assert.Equal(t, "jack", user["name"])
+	assert.Equal(t, "a,b", user["tags"])
+
+	roles, err := db.WithDriver(conn).Table("user_roles").Where("user_id", "=", 1).All()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(roles))
+
+	// the multi-select fields not posted by a whole form are cleared.
+	err = tb.UpdateData(form2.Values{"id": {"1"}, "name": {"rose"}, "age": {"11"}})
+	assert.NoError(t, err)
+
+	user, err = db.WithDriver(conn).Table("users").Where("id", "=", 1).First()
+	assert.NoError(t, err)
+	assert.Equal(t, "rose", user["name"])
+	assert.Equal(t, "", user["tags"])
+}
+
+func TestDefaultTable_syncRelations(t *testing.T) {
+	conn, done := testDB(t, testUserSchema...)
+	defer done()
+
+	tb := testUserTable()
+
+	err := tb.UpdateData(form2.Values{"id": {"1"}, "name": {"jack"}, "roles[]": {"2", "3", "3"}})
+	assert.NoError(t, err)
+
+	// the kept link is not inserted again.
+	roles, err := db.WithDriver(conn).Table("user_roles").Where("user_id", "=", 1).OrderBy("id", "asc").All()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(roles))
+	assert.Equal(t, int64(2), roles[0]["id"])
+	assert.Equal(t, int64(2), roles[0]["role_id"])
+	assert.Equal(t, int64(3), roles[1]["role_id"])
+
+	err = tb.InsertData(form2.Values{"name": {"rose"}, "roles[]": {"1", ""}})
+	assert.NoError(t, err)
+
+	roles, err = db.WithDriver(conn).Table("user_roles").Where("user_id", "=", 2).All()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(roles))
+	assert.Equal(t, int64(1), roles[0]["role_id"])
+
+	// all the links are removed by a blank multi-select.
+	err = tb.UpdateData(form2.Values{"id": {"1"}, "name": {"jack"}, "roles[]": {""}})
+	assert.NoError(t, err)
+
+	count, err := db.WithDriver(conn).Table("user_roles").Where("user_id", "=", 1).Count()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestDiffLinks(t *testing.T) {
+	added, removed := diffLinks([]string{"1", "2"}, []string{"2", "3", "3"})
+	assert.Equal(t, []string{"3"}, added)
+	assert.Equal(t, []string{"1"}, removed)
+
+	added, removed = diffLinks([]string{}, []string{"1"})
+	assert.Equal(t, []string{"1"}, added)
+	assert.Equal(t, []string{}, removed)
+
+	added, removed = diffLinks([]string{"1"}, []string{})
+	assert.Equal(t, []string{}, added)
+	assert.Equal(t, []string{"1"}, removed)
+}
+
+func TestDefaultTable_TxPostHookRollback(t *testing.T) {
+	conn, done := testDB(t, testUserSchema...)
+	defer done()
+
+	tb := testUserTable()
+	tb.GetForm().SetTxPostHook(func(tx *sql.Tx, values form2.Values) error {
+		return errors.New("hook failed")
+	})
+
+	err := tb.InsertData(form2.Values{"name": {"rose"}, "age": {"12"}, "roles[]": {"3"}})
+	assert.EqualError(t, err, "hook failed")
+
+	count, err := db.WithDriver(conn).Table("users").Count()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = db.WithDriver(conn).Table("user_roles").Where("role_id", "=", 3).Count()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	err = tb.UpdateData(form2.Values{"id": {"1"}, "name": {"rose"}, "roles[]": {"3"}})
+	assert.EqualError(t, err, "hook failed")
+
+	user, err := db.WithDriver(conn).Table("users").Where("id", "=", 1).First()
+	assert.NoError(t, err)
+	assert.Equal(t, "jack", user["name"])
+
+	count, err = db.WithDriver(conn).Table("user_roles").Where("user_id", "=", 1).Count()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestDefaultTable_GetDataWithContext(t *testing.T) {
+	_, done := testDB(t, testUserSchema...)
+	defer done()
+
+	tb := testUserTable()
+
+	formInfo, err := tb.GetDataWithId(parameter.BaseParam().WithPKs("1"))
+	assert.NoError(t, err)
+	assert.Equal(t, template.HTML("jack"), formInfo.FieldList.FindByFieldName("name").Value)
+
+	// the queries of a done request are canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = tb.GetDataWithContext(ctx, parameter.BaseParam())
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = tb.GetDataWithIdsWithContext(ctx, parameter.BaseParam().WithPKs("1"))
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = tb.GetDataWithIdWithContext(ctx, parameter.BaseParam().WithPKs("1"))
+	assert.Equal(t, context.Canceled, err)
+}
+
+// testTheme is the theme of the tests, only the paginator is made.
+type testTheme struct {
+	template2.Template
+}
+
+func (testTheme) Paginator() types.PaginatorAttribute {
+	return new(components.PaginatorAttribute)
+}
+
+func init() {
+	template2.Add(config.Get().Theme, testTheme{})
+}
+
+var testAgeSchema = []string{
+	`create table users (id integer primary key autoincrement, name varchar(50), age int, tags varchar(50))`,
+	`insert into users (name, age) values ('a', 30), ('b', 20), ('c', 20), ('d', 10), ('e', 40)`,
+}
+
+func TestDefaultTable_KeysetPagination(t *testing.T) {
+	_, done := testDB(t, testAgeSchema...)
+	defer done()
+
+	tb := testUserTable()
+	tb.GetInfo().UseKeysetPagination()
+
+	names := func(info PanelInfo) []string {
+		list := make([]string, len(info.InfoList))
+		for i, row := range info.InfoList {
+			list[i] = string(row["name"].Content)
+		}
+		return list
+	}
+
+	params := parameter.GetParamFromURL("/admin/info/users?__pageSize=2&__sort=age&__sort_type=asc", 2, "desc", "id")
+
+	info, err := tb.GetData(params)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"d", "b"}, names(info))
+	assert.Equal(t, "", info.PreviousCursor)
+
+	// the rows of the same age are ordered by the primary key.
+	info, err = tb.GetData(params.WithAfter(info.NextCursor))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "a"}, names(info))
+
+	next := info.NextCursor
+	info, err = tb.GetData(params.WithAfter(next))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"e"}, names(info))
+	assert.Equal(t, "", info.NextCursor)
+
+	params.Before = info.PreviousCursor
+	info, err = tb.GetData(params)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "a"}, names(info))
+
+	// an invalid cursor turns to the first page.
+	info, err = tb.GetData(params.WithAfter("not a cursor"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"d", "b"}, names(info))
+}
+
+func TestDefaultTable_CountMode(t *testing.T) {
+	conn, done := testDB(t, testAgeSchema...)
+	defer done()
+
+	tb := testUserTable()
+
+	params := parameter.GetParamFromURL("/admin/info/users?__pageSize=2", 2, "desc", "id")
+
+	info, err := tb.GetData(params)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, info.Total)
+
+	tb.GetInfo().SkipCount()
+	info, err = tb.GetData(params)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, info.Total)
+	assert.Equal(t, 2, len(info.InfoList))
+
+	// the estimate of sqlite is the max rowid, which counts the deleted rows.
+	tb.GetInfo().EstimateCount()
+	_, err = conn.Exec("delete from users where id < 3")
+	assert.NoError(t, err)
+	info, err = tb.GetData(params)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, info.Total)
+
+	// the total of the filtered rows is unknown.
+	info, err = tb.GetData(parameter.GetParamFromURL("/admin/info/users?__pageSize=2&age=20", 2, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, -1, info.Total)
+	assert.Equal(t, 1, len(info.InfoList))
+
+	// an estimate of 0 is not taken as no estimate.
+	_, err = conn.Exec("delete from users")
+	assert.NoError(t, err)
+	_, err = conn.Exec("insert into users (id, name) values (0, 'zero')")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, tb.estimateCount(context.Background(), "default"))
+
+	// there is no estimate of an empty table.
+	_, err = conn.Exec("delete from users")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, tb.estimateCount(context.Background(), "default"))
+}
+
+func TestDefaultTable_GetDataJoins(t *testing.T) {
+	_, done := testDB(t, append(testUserSchema,
+		`create table roles (id integer primary key autoincrement, name varchar(50))`,
+		`insert into roles (name) values ('admin'), ('editor')`,
+		`insert into users (name, age) values ('rose', 20)`,
+		`insert into user_roles (user_id, role_id) values (2, 1)`)...)
+	defer done()
+
+	tb := testUserTable()
+	tb.GetInfo().AddField("Role", "name", db.Varchar).FieldJoins(types.Join{
+		Table: "user_roles", Alias: "ur", Field: "id", JoinField: "user_id",
+	}, types.Join{
+		Table: "roles", Alias: "r", Field: "role_id", JoinField: "id",
+	}).FieldSortable().FieldFilterable()
+
+	role := types.JoinField("r", "name")
+
+	// the rows are grouped by the primary key, so each user is listed once
+	// with all the roles.
+	info, err := tb.GetData(parameter.GetParamFromURL("/admin/info/users?__sort=id&__sort_type=asc", 10, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, info.Total)
+	assert.Equal(t, 2, len(info.InfoList))
+	assert.Equal(t, template.HTML("jack"), info.InfoList[0]["name"].Content)
+	assert.Equal(t, template.HTML("rose"), info.InfoList[1]["name"].Content)
+	assert.Contains(t, string(info.InfoList[0][role].Content), "admin")
+	assert.Contains(t, string(info.InfoList[0][role].Content), "editor")
+	assert.Equal(t, template.HTML("admin"), info.InfoList[1][role].Content)
+
+	// the joined field is filtered and sorted by the alias.
+	info, err = tb.GetData(parameter.GetParamFromURL("/admin/info/users?"+role+"=editor", 10, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, info.Total)
+	assert.Equal(t, template.HTML("jack"), info.InfoList[0]["name"].Content)
+
+	info, err = tb.GetData(parameter.GetParamFromURL("/admin/info/users?__sort="+role+"&__sort_type=asc", 10, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, template.HTML("rose"), info.InfoList[0]["name"].Content)
+}
+
+func TestDefaultTable_GetDataHiddenFields(t *testing.T) {
+	_, done := testDB(t, testAgeSchema...)
+	defer done()
+
+	tb := testUserTable()
+	tb.GetInfo().FieldList[2].Permissions = types.FieldPermissions{{Access: types.FieldEditable, Slugs: []string{"hr"}}}
+
+	info, err := tb.GetData(parameter.GetParamFromURL("/admin/info/users?age=20&__sort=age&__sort_type=asc", 10, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, info.Total)
+	assert.Equal(t, template.HTML("b"), info.InfoList[0]["name"].Content)
+
+	// the fields hidden from the user can not be filtered or sorted by.
+	tb.SetUser(models.UserModel{Id: 2})
+
+	info, err = tb.GetData(parameter.GetParamFromURL("/admin/info/users?age=20&__sort=age&__sort_type=asc", 10, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, info.Total)
+	assert.Equal(t, template.HTML("a"), info.InfoList[0]["name"].Content)
+
+	info, err = tb.GetData(parameter.GetParamFromURL("/admin/info/users?name=c", 10, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, info.Total)
+}
+
+func TestDefaultTable_Aggregates(t *testing.T) {
+	_, done := testDB(t,
+		`create table users (id integer primary key autoincrement, name varchar(50), age int, tags varchar(50))`,
+		`insert into users (name, age, tags) values ('a', 30, 'x'), ('b', 20, 'y'), ('c', 20, 'x'), ('d', 10, 'y'), ('e', 40, 'x')`,
+		`create table user_roles (id integer primary key autoincrement, user_id int, role_id int)`,
+		`insert into user_roles (user_id, role_id) values (1, 1), (1, 2)`)
+	defer done()
+
+	tb := testUserTable()
+	info := tb.GetInfo()
+	info.FieldList[2].Aggregates = []types.Aggregate{types.AggregateSum, types.AggregateAvg, types.AggregateCount}
+	info.FieldList[2].Filterable = true
+	info.AddField("Tags", "tags", db.Varchar).FieldGroupable()
+
+	aggregates := func(sum, avg, count string) template.HTML {
+		return template.HTML(language.Get("sum") + ": " + sum + "<br>" +
+			language.Get("avg") + ": " + avg + "<br>" + language.Get("count") + ": " + count)
+	}
+
+	panel, err := tb.GetData(parameter.GetParamFromURL("/admin/info/users?__pageSize=2", 2, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, aggregates("120", "24", "5"), panel.Aggregates["age"])
+
+	// the aggregates are of the filtered rows rather than the page.
+	panel, err = tb.GetData(parameter.GetParamFromURL("/admin/info/users?age=20", 10, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, aggregates("40", "20", "2"), panel.Aggregates["age"])
+
+	// the rows multiplied by the joins are aggregated once.
+	info.AddField("Role", "role_id", db.Int).FieldJoin(types.Join{
+		Table: "user_roles", Field: "id", JoinField: "user_id",
+	})
+	panel, err = tb.GetData(parameter.GetParamFromURL("/admin/info/users", 10, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, panel.Total)
+	assert.Equal(t, aggregates("120", "24", "5"), panel.Aggregates["age"])
+
+	// the groups are the subtotals of the rows.
+	panel, err = tb.GetData(parameter.GetParamFromURL("/admin/info/users?__group_by=tags", 10, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, "tags", panel.GroupBy)
+	assert.Equal(t, 2, panel.Total)
+	assert.Equal(t, template.HTML(`x <span class="label label-default">3</span>`), panel.InfoList[0]["tags"].Content)
+	assert.Equal(t, aggregates("90", "30", "3"), panel.InfoList[0]["age"].Content)
+	assert.Equal(t, template.HTML(`y <span class="label label-default">2</span>`), panel.InfoList[1]["tags"].Content)
+	assert.Equal(t, aggregates("30", "15", "2"), panel.InfoList[1]["age"].Content)
+	assert.Equal(t, template.HTML(""), panel.InfoList[1]["name"].Content)
+	assert.Equal(t, aggregates("120", "24", "5"), panel.Aggregates["age"])
+
+	panel, err = tb.GetData(parameter.GetParamFromURL("/admin/info/users?__group_by=tags&__sort=age&__sort_type=asc", 10, "desc", "id"))
+	assert.NoError(t, err)
+	assert.Equal(t, "y", panel.InfoList[0]["tags"].Value)
+}
+
+func TestAggregateValue(t *testing.T) {
+	assert.Equal(t, "26.67", aggregateValue(26.666666))
+	assert.Equal(t, "26", aggregateValue(int64(26)))
+	assert.Equal(t, "1.5", aggregateValue([]byte("1.5000")))
+	assert.Equal(t, "", aggregateValue(nil))
+}
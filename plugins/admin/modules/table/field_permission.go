@@ -0,0 +1,31 @@
+package table
+
+import (
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+// checkFieldAccess check the posted values are all of the fields which the
+// user can change. A value of a hidden or read-only field is rejected
+// rather than dropped, so it is never written silently either. It must be
+// called before the PreProcessFn, which may set any field on purpose.
+func (tb DefaultTable) checkFieldAccess(dataList form.Values) error {
+
+	var errs form.FieldErrors
+
+	for _, field := range tb.Form.FieldList {
+		if field.Access == types.FieldEditable {
+			continue
+		}
+		_, posted := dataList[field.Field]
+		_, postedMulti := dataList[field.Field+"[]"]
+		if posted || postedMulti {
+			errs = errs.Add(field.Field, "permission denied")
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
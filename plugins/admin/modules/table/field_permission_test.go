@@ -0,0 +1,26 @@
+package table
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	form2 "github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDefaultTable_checkFieldAccess(t *testing.T) {
+	tb := NewDefaultTable(DefaultConfig()).(DefaultTable)
+	tb.GetForm().AddField("Name", "name", db.Varchar, form2.Text)
+	tb.GetForm().AddField("Salary", "salary", db.Int, form2.Number).
+		FieldPermission(types.FieldEditable, "hr")
+	tb.GetForm().AddField("Tags", "tags", db.Varchar, form2.Select).
+		FieldPermission(types.FieldReadOnly, "staff")
+
+	tb.SetUser(models.UserModel{Id: 2, Roles: []models.RoleModel{{Slug: "staff"}}})
+
+	assert.Nil(t, tb.checkFieldAccess(form.Values{"id": {"1"}, "name": {"jack"}}))
+	assert.Equal(t, form.FieldErrors{}.Add("salary", "permission denied").Add("tags", "permission denied"),
+		tb.checkFieldAccess(form.Values{"name": {"jack"}, "salary": {"10"}, "tags[]": {"a"}}))
+}
@@ -2,9 +2,11 @@ package table
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
 	"github.com/GoAdminGroup/go-admin/modules/collection"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
@@ -43,9 +45,16 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (ManagerTable Table)
 		All()
 	labelCollection := collection.Collection(labelModels)
 
+	twoFactorModels, _ := s.table("goadmin_user_two_factors").Select("user_id").All()
+	twoFactorCollection := collection.Collection(twoFactorModels)
+
+	lockoutModels, _ := s.table("goadmin_user_lockouts").Select("user_id").
+		Where("locked_until", ">", time.Now().Unix()).All()
+	lockoutCollection := collection.Collection(lockoutModels)
+
 	info.AddField("ID", "id", db.Int).FieldSortable()
-	info.AddField(lg("Name"), "username", db.Varchar).FieldFilterable()
-	info.AddField(lg("Nickname"), "name", db.Varchar).FieldFilterable()
+	info.AddField(lg("Name"), "username", db.Varchar).FieldFilterable().FieldSearchable()
+	info.AddField(lg("Nickname"), "name", db.Varchar).FieldFilterable().FieldSearchable()
 	info.AddField(lg("role"), "roles", db.Varchar).
 		FieldDisplay(func(model types.FieldModel) interface{} {
 			uid, _ := strconv.Atoi(model.ID)
@@ -68,9 +77,50 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (ManagerTable Table)
 
 			return labels
 		})
+	info.AddField(lg("two-factor authentication"), "two_factor", db.Varchar).
+		FieldDisplay(func(model types.FieldModel) interface{} {
+			uid, _ := strconv.Atoi(model.ID)
+			if len(twoFactorCollection.Where("user_id", int64(uid))) > 0 {
+				return label().SetType("success").SetContent(language.GetFromHtml("enabled")).GetContent()
+			}
+			return label().SetType("default").SetContent(language.GetFromHtml("disabled")).GetContent()
+		})
+	info.AddField(lg("status"), "locked", db.Varchar).
+		FieldDisplay(func(model types.FieldModel) interface{} {
+			uid, _ := strconv.Atoi(model.ID)
+			if len(lockoutCollection.Where("user_id", int64(uid))) > 0 {
+				return label().SetType("danger").SetContent(language.GetFromHtml("locked")).GetContent()
+			}
+			return label().SetType("success").SetContent(language.GetFromHtml("normal")).GetContent()
+		})
 	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
 	info.AddField(lg("updatedAt"), "updated_at", db.Timestamp)
 
+	info.AddActionButton(language.GetFromHtml("api tokens"), action.Jump(config.Get().Url("/info/user_tokens?user_id={%id}")))
+	info.AddActionButton(language.GetFromHtml("reset two-factor authentication"),
+		action.Ajax(config.Get().Url("/manager/two_factor/reset"),
+			func(ctx *context.Context) (success bool, msg string, data interface{}) {
+				err := models.UserTwoFactor().SetConn(s.conn).DeleteByUserId(ctx.FormValue("id"))
+				if err != nil && notNoAffectRow(err) {
+					return false, err.Error(), ""
+				}
+				return true, language.Get("two-factor authentication has been reset"), ""
+			}).WithAlert())
+	info.AddActionButton(language.GetFromHtml("unlock"),
+		action.Ajax(config.Get().Url("/manager/unlock"),
+			func(ctx *context.Context) (success bool, msg string, data interface{}) {
+				user := models.User().SetConn(s.conn).Find(ctx.FormValue("id"))
+				if user.IsEmpty() {
+					return false, language.Get("user not found"), ""
+				}
+				err := models.UserLockout().SetConn(s.conn).DeleteByUserId(user.Id)
+				if err != nil && notNoAffectRow(err) {
+					return false, err.Error(), ""
+				}
+				auth.GetLoginLimiter().Reset(user.UserName)
+				return true, language.Get("user has been unlocked"), ""
+			}).WithAlert())
+
 	info.SetTable("goadmin_users").
 		SetTitle(lg("Managers")).
 		SetDescription(lg("Managers")).
@@ -98,6 +148,33 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (ManagerTable Table)
 					return deleteUserPermissionErr, map[string]interface{}{}
 				}
 
+				deleteUserTokenErr := s.connection().WithTx(tx).
+					Table("goadmin_user_tokens").
+					WhereIn("user_id", ids).
+					Delete()
+
+				if deleteUserTokenErr != nil && notNoAffectRow(deleteUserTokenErr) {
+					return deleteUserTokenErr, map[string]interface{}{}
+				}
+
+				deleteUserTwoFactorErr := s.connection().WithTx(tx).
+					Table("goadmin_user_two_factors").
+					WhereIn("user_id", ids).
+					Delete()
+
+				if deleteUserTwoFactorErr != nil && notNoAffectRow(deleteUserTwoFactorErr) {
+					return deleteUserTwoFactorErr, map[string]interface{}{}
+				}
+
+				deleteUserLockoutErr := s.connection().WithTx(tx).
+					Table("goadmin_user_lockouts").
+					WhereIn("user_id", ids).
+					Delete()
+
+				if deleteUserLockoutErr != nil && notNoAffectRow(deleteUserLockoutErr) {
+					return deleteUserLockoutErr, map[string]interface{}{}
+				}
+
 				deleteUserErr := s.connection().WithTx(tx).
 					Table("goadmin_users").
 					WhereIn("id", ids).
@@ -123,37 +200,15 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (ManagerTable Table)
 	formList.AddField(lg("Avatar"), "avatar", db.Varchar, form.File)
 	formList.AddField(lg("role"), "role_id", db.Varchar, form.Select).
 		FieldOptionsFromTable("goadmin_roles", "slug", "id").
-		FieldDisplay(func(model types.FieldModel) interface{} {
-			var roles []string
-
-			if model.ID == "" {
-				return roles
-			}
-			roleModel, _ := s.table("goadmin_role_users").Select("role_id").
-				Where("user_id", "=", model.ID).All()
-			for _, v := range roleModel {
-				roles = append(roles, strconv.FormatInt(v["role_id"].(int64), 10))
-			}
-			return roles
-		}).FieldHelpMsg(template.HTML(lg("no corresponding options?")) +
-		link("/admin/info/roles/new", "Create here."))
+		FieldRelation("goadmin_role_users", "user_id", "role_id").
+		FieldHelpMsg(template.HTML(lg("no corresponding options?")) +
+			link("/admin/info/roles/new", "Create here."))
 
 	formList.AddField(lg("permission"), "permission_id", db.Varchar, form.Select).
 		FieldOptionsFromTable("goadmin_permissions", "slug", "id").
-		FieldDisplay(func(model types.FieldModel) interface{} {
-			var permissions []string
-
-			if model.ID == "" {
-				return permissions
-			}
-			permissionModel, _ := s.table("goadmin_user_permissions").
-				Select("permission_id").Where("user_id", "=", model.ID).All()
-			for _, v := range permissionModel {
-				permissions = append(permissions, strconv.FormatInt(v["permission_id"].(int64), 10))
-			}
-			return permissions
-		}).FieldHelpMsg(template.HTML(lg("no corresponding options?")) +
-		link("/admin/info/permission/new", "Create here."))
+		FieldRelation("goadmin_user_permissions", "user_id", "permission_id").
+		FieldHelpMsg(template.HTML(lg("no corresponding options?")) +
+			link("/admin/info/permission/new", "Create here."))
 
 	formList.AddField(lg("password"), "password", db.Varchar, form.Password).
 		FieldDisplay(func(value types.FieldModel) interface{} {
@@ -165,65 +220,42 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (ManagerTable Table)
 		})
 
 	formList.SetTable("goadmin_users").SetTitle(lg("Managers")).SetDescription(lg("Managers"))
-	formList.SetUpdateFn(func(values form2.Values) error {
+	formList.SetPostValidator(func(values form2.Values) error {
 
-		if values.IsEmpty("name", "username") {
+		if !values.IsPartialUpdatePost() && values.IsEmpty("name", "username") {
 			return errors.New("username and password can not be empty")
 		}
 
-		user := models.UserWithId(values.Get("id")).SetConn(s.conn)
-
-		password := values.Get("password")
-
-		if password != "" {
-
-			if password != values.Get("password_again") {
-				return errors.New("password does not match")
-			}
-
-			password = encodePassword([]byte(values.Get("password")))
-		}
-
-		user.Update(values.Get("username"), password, values.Get("name"), values.Get("avatar"))
-
-		user.DeleteRoles()
-		for i := 0; i < len(values["role_id[]"]); i++ {
-			user.AddRole(values["role_id[]"][i])
+		if values.IsInsertPost() && values.IsEmpty("password") {
+			return errors.New("username and password can not be empty")
 		}
 
-		user.DeletePermissions()
-		for i := 0; i < len(values["permission_id[]"]); i++ {
-			user.AddPermission(values["permission_id[]"][i])
+		if values.Get("password") != values.Get("password_again") {
+			return errors.New("password does not match")
 		}
 
 		return nil
 	})
-	formList.SetInsertFn(func(values form2.Values) error {
-		if values.IsEmpty("name", "username", "password") {
-			return errors.New("username and password can not be empty")
+	// the roles and permissions are synced by the relation fields within
+	// the transaction of the user.
+	formList.SetPreProcessFn(func(values form2.Values) form2.Values {
+
+		// the password and the avatar are kept when they are not changed.
+		if values.IsEmpty("password") {
+			values.Delete("password")
+		} else {
+			values.Add("password", encodePassword([]byte(values.Get("password"))))
 		}
 
-		password := values.Get("password")
-
-		if password != values.Get("password_again") {
-			return errors.New("password does not match")
+		if values.IsEmpty("avatar") {
+			values.Delete("avatar")
 		}
 
-		user := models.User().SetConn(s.conn).New(values.Get("username"),
-			encodePassword([]byte(values.Get("password"))),
-			values.Get("name"),
-			values.Get("avatar"))
-
-		// TODO: Add transaction support.
-
-		for i := 0; i < len(values["role_id[]"]); i++ {
-			user.AddRole(values["role_id[]"][i])
+		if values.IsUpdatePost() {
+			values.Add("updated_at", time.Now().Format("2006-01-02 15:04:05"))
 		}
 
-		for i := 0; i < len(values["permission_id[]"]); i++ {
-			user.AddPermission(values["permission_id[]"][i])
-		}
-		return nil
+		return values
 	})
 
 	detail := ManagerTable.GetDetail()
@@ -305,8 +337,8 @@ func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (ManagerTable
 	labelCollection := collection.Collection(labelModels)
 
 	info.AddField("ID", "id", db.Int).FieldSortable()
-	info.AddField(lg("Name"), "username", db.Varchar).FieldFilterable()
-	info.AddField(lg("Nickname"), "name", db.Varchar).FieldFilterable()
+	info.AddField(lg("Name"), "username", db.Varchar).FieldFilterable().FieldSearchable()
+	info.AddField(lg("Nickname"), "name", db.Varchar).FieldFilterable().FieldSearchable()
 	info.AddField(lg("role"), "roles", db.Varchar).
 		FieldDisplay(func(model types.FieldModel) interface{} {
 			labelCol := labelCollection.Where("user_id", model.ID)
@@ -358,6 +390,33 @@ func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (ManagerTable
 					return deleteUserPermissionErr, map[string]interface{}{}
 				}
 
+				deleteUserTokenErr := s.connection().WithTx(tx).
+					Table("goadmin_user_tokens").
+					WhereIn("user_id", ids).
+					Delete()
+
+				if deleteUserTokenErr != nil && notNoAffectRow(deleteUserTokenErr) {
+					return deleteUserTokenErr, map[string]interface{}{}
+				}
+
+				deleteUserTwoFactorErr := s.connection().WithTx(tx).
+					Table("goadmin_user_two_factors").
+					WhereIn("user_id", ids).
+					Delete()
+
+				if deleteUserTwoFactorErr != nil && notNoAffectRow(deleteUserTwoFactorErr) {
+					return deleteUserTwoFactorErr, map[string]interface{}{}
+				}
+
+				deleteUserLockoutErr := s.connection().WithTx(tx).
+					Table("goadmin_user_lockouts").
+					WhereIn("user_id", ids).
+					Delete()
+
+				if deleteUserLockoutErr != nil && notNoAffectRow(deleteUserLockoutErr) {
+					return deleteUserLockoutErr, map[string]interface{}{}
+				}
+
 				deleteUserErr := s.connection().WithTx(tx).
 					Table("goadmin_users").
 					WhereIn("id", ids).
@@ -379,6 +438,11 @@ func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (ManagerTable
 	formList.AddField(lg("Name"), "username", db.Varchar, form.Text).FieldHelpMsg(template.HTML(lg("use for login"))).FieldMust()
 	formList.AddField(lg("Nickname"), "name", db.Varchar, form.Text).FieldHelpMsg(template.HTML(lg("use to display"))).FieldMust()
 	formList.AddField(lg("Avatar"), "avatar", db.Varchar, form.File)
+	formList.AddField(lg("two-factor authentication"), "two_factor", db.Varchar, form.Default).
+		FieldNotAllowAdd().
+		FieldDisplay(func(value types.FieldModel) interface{} {
+			return link(config.Get().Url("/two_factor"), "setting")
+		})
 	formList.AddField(lg("password"), "password", db.Varchar, form.Password).
 		FieldDisplay(func(value types.FieldModel) interface{} {
 			return ""
@@ -448,8 +512,8 @@ func (s *SystemTable) GetPermissionTable(ctx *context.Context) (PermissionTable
 	info := PermissionTable.GetInfo().AddXssJsFilter().HideFilterArea()
 
 	info.AddField("ID", "id", db.Int).FieldSortable()
-	info.AddField(lg("permission"), "name", db.Varchar).FieldFilterable()
-	info.AddField(lg("slug"), "slug", db.Varchar).FieldFilterable()
+	info.AddField(lg("permission"), "name", db.Varchar).FieldFilterable().FieldSearchable()
+	info.AddField(lg("slug"), "slug", db.Varchar).FieldFilterable().FieldSearchable()
 	info.AddField(lg("method"), "http_method", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
 		if value.Value == "" {
 			return "All methods"
@@ -571,8 +635,15 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (RolesTable Table) {
 	info := RolesTable.GetInfo().AddXssJsFilter().HideFilterArea()
 
 	info.AddField("ID", "id", db.Int).FieldSortable()
-	info.AddField(lg("role"), "name", db.Varchar).FieldFilterable()
-	info.AddField(lg("slug"), "slug", db.Varchar).FieldFilterable()
+	info.AddField(lg("role"), "name", db.Varchar).FieldFilterable().FieldSearchable()
+	info.AddField(lg("slug"), "slug", db.Varchar).FieldFilterable().FieldSearchable()
+	info.AddField(lg("two-factor authentication required"), "two_factor_required", db.Tinyint).
+		FieldDisplay(func(model types.FieldModel) interface{} {
+			if model.Value == "1" {
+				return label().SetType("success").SetContent(language.GetFromHtml("enabled")).GetContent()
+			}
+			return label().SetType("default").SetContent(language.GetFromHtml("disabled")).GetContent()
+		})
 	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
 	info.AddField(lg("updatedAt"), "updated_at", db.Timestamp)
 
@@ -650,6 +721,13 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (RolesTable Table) {
 			return permissions
 		}).FieldHelpMsg(template.HTML(lg("no corresponding options?")) +
 		link("/admin/info/permission/new", "Create here."))
+	formList.AddField(lg("two-factor authentication required"), "two_factor_required", db.Tinyint, form.Radio).
+		FieldOptions(types.FieldOptions{
+			{Text: lg("disabled"), Value: "0"},
+			{Text: lg("enabled"), Value: "1"},
+		}).
+		FieldDefault("0").
+		FieldHelpMsg(template.HTML(lg("the users of the role have to login with the two-factor authentication")))
 
 	formList.AddField(lg("updatedAt"), "updated_at", db.Timestamp, form.Default).FieldNotAllowAdd()
 	formList.AddField(lg("createdAt"), "created_at", db.Timestamp, form.Default).FieldNotAllowAdd()
@@ -667,6 +745,7 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (RolesTable Table) {
 		role := models.RoleWithId(values.Get("id")).SetConn(s.conn)
 
 		role.Update(values.Get("name"), values.Get("slug"))
+		role.SetTwoFactorRequired(values.Get("two_factor_required") == "1")
 
 		role.DeletePermissions()
 		for i := 0; i < len(values["permission_id[]"]); i++ {
@@ -682,7 +761,8 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (RolesTable Table) {
 			return errors.New("slug exists")
 		}
 
-		role := models.Role().SetConn(s.conn).New(values.Get("name"), values.Get("slug"))
+		role := models.Role().SetConn(s.conn).New(values.Get("name"), values.Get("slug")).
+			SetTwoFactorRequired(values.Get("two_factor_required") == "1")
 
 		for i := 0; i < len(values["permission_id[]"]); i++ {
 			role.AddPermission(values["permission_id[]"][i])
@@ -715,6 +795,21 @@ func (s *SystemTable) GetOpTable(ctx *context.Context) (OpTable Table) {
 	info.AddField(lg("path"), "path", db.Varchar).FieldFilterable()
 	info.AddField(lg("method"), "method", db.Varchar).FieldFilterable()
 	info.AddField(lg("ip"), "ip", db.Varchar).FieldFilterable()
+	info.AddField(lg("table"), "prefix", db.Varchar).FieldFilterable()
+	info.AddField(lg("primary key"), "pk", db.Varchar).FieldFilterable()
+	info.AddField(lg("operation"), "op", db.Varchar).FieldFilterable(types.FilterType{
+		FormType: form.SelectSingle,
+	}).FieldFilterOptions(auditOpOptions()).
+		FieldDisplay(func(model types.FieldModel) interface{} {
+			if model.Value == "" {
+				return ""
+			}
+			return lg(model.Value)
+		})
+	info.AddField(lg("changes"), "diff", db.Text).FieldWidth(300).
+		FieldDisplay(func(model types.FieldModel) interface{} {
+			return auditChangesHTML(model.Value)
+		})
 	info.AddField(lg("content"), "input", db.Varchar).FieldWidth(230)
 	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
 
@@ -733,6 +828,7 @@ func (s *SystemTable) GetOpTable(ctx *context.Context) (OpTable Table) {
 		{Value: "HEAD", Text: "HEAD"},
 		{Value: "DELETE", Text: "DELETE"},
 	}, action.FieldFilter("method"))
+	info.AddSelectBox(lg("operation"), auditOpOptions(), action.FieldFilter("op"))
 
 	info.SetTable("goadmin_operation_log").
 		SetTitle(lg("operation log")).
@@ -746,6 +842,10 @@ func (s *SystemTable) GetOpTable(ctx *context.Context) (OpTable Table) {
 	formList.AddField(lg("method"), "method", db.Varchar, form.Text)
 	formList.AddField(lg("ip"), "ip", db.Varchar, form.Text)
 	formList.AddField(lg("content"), "input", db.Varchar, form.Text)
+	formList.AddField(lg("table"), "prefix", db.Varchar, form.Text)
+	formList.AddField(lg("primary key"), "pk", db.Varchar, form.Text)
+	formList.AddField(lg("operation"), "op", db.Varchar, form.Text)
+	formList.AddField(lg("changes"), "diff", db.Text, form.TextArea)
 	formList.AddField(lg("updatedAt"), "updated_at", db.Timestamp, form.Default).FieldNotAllowAdd()
 	formList.AddField(lg("createdAt"), "created_at", db.Timestamp, form.Default).FieldNotAllowAdd()
 
@@ -756,6 +856,120 @@ func (s *SystemTable) GetOpTable(ctx *context.Context) (OpTable Table) {
 	return
 }
 
+func auditOpOptions() types.FieldOptions {
+	return types.FieldOptions{
+		{Value: AuditInsert, Text: lg(AuditInsert)},
+		{Value: AuditUpdate, Text: lg(AuditUpdate)},
+		{Value: AuditInlineUpdate, Text: lg(AuditInlineUpdate)},
+		{Value: AuditDelete, Text: lg(AuditDelete)},
+		{Value: AuditExport, Text: lg(AuditExport)},
+		{Value: AuditLoginFailure, Text: lg(AuditLoginFailure)},
+	}
+}
+
+// auditChangesHTML render the changes of an audit record as a table.
+func auditChangesHTML(diff string) tmpl.HTML {
+	var changes []FieldChange
+	if diff == "" || json.Unmarshal([]byte(diff), &changes) != nil || len(changes) == 0 {
+		return ""
+	}
+
+	content := "<table class=\"table table-condensed\"><tr><th>" + lg("field") + "</th><th>" +
+		lg("before") + "</th><th>" + lg("after") + "</th></tr>"
+	for _, change := range changes {
+		content += "<tr><td>" + tmpl.HTMLEscapeString(change.Field) + "</td><td>" +
+			tmpl.HTMLEscapeString(change.Before) + "</td><td>" +
+			tmpl.HTMLEscapeString(change.After) + "</td></tr>"
+	}
+
+	return tmpl.HTML(content + "</table>")
+}
+
+func (s *SystemTable) GetUserTokenTable(ctx *context.Context) (UserTokenTable Table) {
+	UserTokenTable = NewDefaultTable(Config{
+		Driver:     config.Get().Databases.GetDefault().Driver,
+		CanAdd:     true,
+		Editable:   false,
+		Deletable:  true,
+		Exportable: false,
+		Connection: "default",
+		PrimaryKey: PrimaryKey{
+			Type: db.Int,
+			Name: DefaultPrimaryKeyName,
+		},
+	})
+
+	info := UserTokenTable.GetInfo().AddXssJsFilter().HideFilterArea().HideEditButton().HideDetailButton()
+
+	info.AddField("ID", "id", db.Int).FieldSortable()
+	info.AddField(lg("userID"), "user_id", db.Int).FieldFilterable().FieldHide()
+	info.AddField(lg("user"), "username", db.Varchar).FieldJoin(types.Join{
+		Table:     "goadmin_users",
+		Field:     "user_id",
+		JoinField: "id",
+		Single:    true,
+	})
+	info.AddField(lg("Name"), "name", db.Varchar).FieldFilterable()
+	info.AddField(lg("last used at"), "last_used_at", db.Timestamp)
+	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
+
+	info.SetTable("goadmin_user_tokens").
+		SetTitle(lg("api tokens")).
+		SetDescription(lg("api tokens"))
+
+	// the admins other than the super admin can only manage their own tokens,
+	// which restricts the list, the detail and the deletions.
+	info.AddRowPolicy(types.EveryRole, "user_id", "=", types.CurrentUserId)
+
+	user := auth.Auth(ctx)
+
+	formList := UserTokenTable.GetForm().AddXssJsFilter()
+
+	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit().FieldNotAllowAdd()
+	formList.AddField(lg("user"), "user_id", db.Int, form.SelectSingle).FieldMust()
+	if user.IsSuperAdmin() {
+		formList.FieldOptionsFromTable("goadmin_users", "username", "id").
+			FieldDefault(ctx.Query("user_id"))
+	} else {
+		formList.FieldOptions(types.FieldOptions{
+			{Text: user.UserName, Value: strconv.FormatInt(user.Id, 10)},
+		}).FieldDefault(strconv.FormatInt(user.Id, 10))
+	}
+
+	// the token is generated by the server, the signature of it is posted
+	// back with it, so that a token chosen by the client is rejected.
+	token := models.NewUserToken()
+
+	formList.AddField(lg("Name"), "name", db.Varchar, form.Text).FieldMust()
+	formList.AddField(lg("token"), "token", db.Varchar, form.Text).
+		FieldDefault(token).
+		FieldHelpMsg(template.HTML(lg("copy the token now, it can not be found again"))).
+		FieldPostFilterFn(func(value types.PostFieldModel) interface{} {
+			return models.HashUserToken(value.Value.Value())
+		}).FieldMust()
+	formList.AddField("", "token_sign", db.Varchar, form.Text).
+		FieldDefault(models.SignUserToken(token, user.Id)).
+		FieldHide()
+
+	formList.SetTable("goadmin_user_tokens").
+		SetTitle(lg("api tokens")).
+		SetDescription(lg("api tokens")).
+		SetPostValidator(func(values form2.Values) error {
+			if values.IsEmpty("user_id", "name") {
+				return errors.New("user and name can not be empty")
+			}
+			if !user.IsSuperAdmin() && values.Get("user_id") != strconv.FormatInt(user.Id, 10) {
+				return errors.New("permission denied")
+			}
+			if !models.CheckUserToken(values.Get("token"), values.Get("token_sign"), user.Id) {
+				return errors.New("the token should be generated by the server")
+			}
+			return nil
+		})
+
+	return
+}
+
 func (s *SystemTable) GetMenuTable(ctx *context.Context) (MenuTable Table) {
 	MenuTable = NewDefaultTable(DefaultConfigWithDriver(config.Get().Databases.GetDefault().Driver))
 
@@ -808,7 +1022,7 @@ func (s *SystemTable) GetMenuTable(ctx *context.Context) (MenuTable Table) {
 	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit().FieldNotAllowAdd()
 	formList.AddField(lg("parent"), "parent_id", db.Int, form.SelectSingle).
 		FieldOptionsFromTable("goadmin_menu", "title", "id", func(sql *db.SQL) *db.SQL {
-			return sql.Where("parent_id", "=", 0).OrderBy("order", "asc")
+			return sql.OrderBy("order", "asc")
 		}).
 		FieldOptionsTableProcessFn(func(options types.FieldOptions) types.FieldOptions {
 			return append([]types.FieldOption{{
@@ -856,7 +1070,14 @@ func (s *SystemTable) GetMenuTable(ctx *context.Context) (MenuTable Table) {
 
 	formList.SetTable("goadmin_menu").
 		SetTitle(lg("Menus Manage")).
-		SetDescription(lg("Menus Manage"))
+		SetDescription(lg("Menus Manage")).
+		SetPostValidator(func(values form2.Values) error {
+			parentId, _ := strconv.ParseInt(values.Get("parent_id"), 10, 64)
+			if !models.MenuWithId(values.Get("id")).SetConn(s.conn).CheckParent(parentId) {
+				return errors.New("wrong parent_id")
+			}
+			return nil
+		})
 
 	return
 }
@@ -0,0 +1,302 @@
+package table
+
+import (
+	dbsql "database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// ImportBatchSize is the number of rows inserted within a transaction
+// when importing.
+var ImportBatchSize = 100
+
+// ImportRow is a row of the imported file, Line is the line number of
+// the row in the file.
+type ImportRow struct {
+	Line   int         `json:"line"`
+	Values form.Values `json:"values"`
+	Errors []string    `json:"errors,omitempty"`
+}
+
+// ImportColumn is a column of the imported file and the form field it
+// is mapped to, Field is empty if the column is not mapped.
+type ImportColumn struct {
+	Head  string
+	Field string
+}
+
+// ImportReport is the result of an import.
+type ImportReport struct {
+	Total    int
+	Inserted int
+	Failed   []ImportRow
+}
+
+// ReadImportFile read the rows of a csv or xlsx file. The first line of the
+// file is the head, each column of it is mapped to the form field of the same
+// field name or head.
+func ReadImportFile(filename string, r io.Reader, fields types.FormFields) ([]ImportColumn, []ImportRow, error) {
+
+	var (
+		lines [][]string
+		err   error
+	)
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		lines, err = reader.ReadAll()
+	case ".xlsx":
+		var f *excelize.File
+		if f, err = excelize.OpenReader(r); err == nil {
+			lines = f.GetRows(f.GetSheetName(f.GetActiveSheetIndex()))
+		}
+	default:
+		return nil, nil, errors.New("wrong import file type")
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(lines) == 0 {
+		return nil, nil, errors.New("empty import file")
+	}
+
+	columns := make([]ImportColumn, len(lines[0]))
+	for i, head := range lines[0] {
+		head = strings.TrimSpace(strings.TrimPrefix(head, "\ufeff"))
+		columns[i].Head = head
+		for _, field := range fields {
+			if !field.NotAllowAdd && (strings.EqualFold(field.Field, head) || strings.EqualFold(field.Head, head)) {
+				columns[i].Field = field.Field
+				break
+			}
+		}
+	}
+
+	rows := make([]ImportRow, 0, len(lines)-1)
+	for i, line := range lines[1:] {
+		if isBlankLine(line) {
+			continue
+		}
+		values := make(form.Values)
+		for j, column := range columns {
+			if column.Field == "" || j >= len(line) {
+				continue
+			}
+			value := strings.TrimSpace(line[j])
+			if fields.FindByFieldName(column.Field).FormType.IsMultiSelect() {
+				values[column.Field+"[]"] = strings.Split(value, ",")
+			} else {
+				values[column.Field] = []string{value}
+			}
+		}
+		rows = append(rows, ImportRow{Line: i + 2, Values: values})
+	}
+
+	return columns, rows, nil
+}
+
+func isBlankLine(line []string) bool {
+	for _, value := range line {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckImportData check the required fields and run the Validator and the
+// PreProcessFn of the form for every row, the errors are kept in the rows. The values of a row are reduced to the
+// fields which can be imported by the user, as the rows to import are
+// posted back by the client after the preview.
+func (tb DefaultTable) CheckImportData(rows []ImportRow) []ImportRow {
+	for i := range rows {
+		rows[i].Values = tb.importValues(rows[i].Values)
+		rows[i].Errors = tb.checkImportRow(rows[i].Values)
+	}
+	return rows
+}
+
+// importValues return the values of the fields which the user can add.
+func (tb DefaultTable) importValues(values form.Values) form.Values {
+	res := make(form.Values)
+	for _, field := range tb.Form.FieldList {
+		if field.NotAllowAdd || field.Access != types.FieldEditable {
+			continue
+		}
+		for _, key := range []string{field.Field, field.Field + "[]"} {
+			if value, ok := values[key]; ok {
+				res[key] = append([]string{}, value...)
+			}
+		}
+	}
+	return res
+}
+
+func (tb DefaultTable) checkImportRow(values form.Values) []string {
+	errs := make([]string, 0)
+
+	for _, field := range tb.Form.FieldList {
+		if field.Must && !field.NotAllowAdd && values.IsEmpty(field.Field) && values.IsEmpty(field.Field+"[]") {
+			errs = append(errs, fmt.Sprintf("%s: %s", field.Head, language.Get("required")))
+		}
+	}
+
+	if err := tb.checkFieldAccess(values); err != nil {
+		return append(errs, err.Error())
+	}
+
+	dataList := copyValues(values)
+	dataList.Add(form.PostTypeKey, "1")
+
+	if tb.Form.Validator != nil {
+		if err := tb.Form.Validator(dataList); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if err := previewInsert(tb.Form, dataList); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	return errs
+}
+
+// previewInsert run the PreProcessFn of the form on the copy of the values
+// as inserting does, so that a row failing it is reported by the preview.
+// The PostFilterFn of the fields is not run, as it may write within the
+// transaction of the insert, which only runs it once.
+func previewInsert(f *types.FormPanel, dataList form.Values) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	if f.PreProcessFn != nil {
+		f.PreProcessFn(copyValues(dataList))
+	}
+
+	return nil
+}
+
+// ImportData insert the rows which pass the checking. The rows are inserted
+// in batches of ImportBatchSize, each batch is inserted within a transaction
+// and the rows of a failed batch are all reported as failed. The PreProcessFn
+// of the form runs before inserting and the PostFilterFn of the fields runs
+// within the transaction.
+func (tb DefaultTable) ImportData(rows []ImportRow) ImportReport {
+
+	defer tb.invalidateCache()
+
+	report := ImportReport{Total: len(rows), Failed: make([]ImportRow, 0)}
+
+	valid := make([]ImportRow, 0, len(rows))
+	for _, row := range tb.CheckImportData(rows) {
+		if len(row.Errors) > 0 {
+			report.Failed = append(report.Failed, row)
+		} else {
+			valid = append(valid, row)
+		}
+	}
+
+	// the rows inserted by the InsertFn can not be rolled back, so they
+	// are inserted one by one.
+	size := ImportBatchSize
+	if tb.Form.InsertFn != nil {
+		size = 1
+	}
+
+	for start := 0; start < len(valid); start += size {
+		end := start + size
+		if end > len(valid) {
+			end = len(valid)
+		}
+		batch := valid[start:end]
+
+		if err := tb.importBatch(batch); err != nil {
+			for _, row := range batch {
+				row.Errors = []string{err.Error()}
+				report.Failed = append(report.Failed, row)
+			}
+			continue
+		}
+
+		report.Inserted += len(batch)
+	}
+
+	return report
+}
+
+// importBatch insert the rows of the batch, a panic of the hooks fails the
+// batch, whose rows are rolled back by the transaction.
+func (tb DefaultTable) importBatch(batch []ImportRow) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	dataLists := make([]form.Values, len(batch))
+	for i, row := range batch {
+		dataLists[i] = tb.importValues(row.Values)
+		dataLists[i].Add(form.PostTypeKey, "1")
+		if tb.Form.PreProcessFn != nil {
+			dataLists[i] = tb.Form.PreProcessFn(dataLists[i])
+		}
+	}
+
+	if tb.Form.InsertFn != nil {
+		for _, dataList := range dataLists {
+			dataList.Delete(form.PostTypeKey)
+			if err := tb.Form.InsertFn(dataList); err != nil {
+				return err
+			}
+			tb.audit(AuditRecord{PK: dataList.Get(tb.PrimaryKey.Name), Op: AuditInsert})
+		}
+		return nil
+	}
+
+	records := make([]AuditRecord, len(dataLists))
+
+	_, err = tb.sql().WithTransaction(func(tx *dbsql.Tx) (error, map[string]interface{}) {
+		for i, dataList := range dataLists {
+			record, err := tb.insert(tx, dataList)
+			if err != nil {
+				return err, nil
+			}
+			records[i] = record
+		}
+		return nil, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for i, dataList := range dataLists {
+		tb.audit(records[i])
+		tb.postInsertHook(dataList)
+	}
+
+	return nil
+}
+
+func copyValues(values form.Values) form.Values {
+	res := make(form.Values, len(values))
+	for key, value := range values {
+		res[key] = append([]string{}, value...)
+	}
+	return res
+}
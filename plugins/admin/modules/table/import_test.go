@@ -0,0 +1,116 @@
+package table
+
+import (
+	"errors"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestReadImportFile(t *testing.T) {
+	tb := NewDefaultTable(DefaultConfig()).(DefaultTable)
+	tb.GetForm().AddField("Name", "name", db.Varchar, form.Text).FieldMust()
+	tb.GetForm().AddField("Roles", "roles", db.Varchar, form.Select)
+	tb.GetForm().SetPostValidator(func(values form2.Values) error {
+		if values.Get("name") == "admin" {
+			return errors.New("name exists")
+		}
+		return nil
+	})
+
+	columns, rows, err := ReadImportFile("users.csv", strings.NewReader("name,Roles,age\njack,\"1,2\",10\n,,\n,1,\nadmin,,"),
+		tb.GetForm().FieldList)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, columns, []ImportColumn{{Head: "name", Field: "name"}, {Head: "Roles", Field: "roles"}, {Head: "age"}})
+	assert.Equal(t, len(rows), 3)
+	assert.Equal(t, rows[0].Line, 2)
+	assert.Equal(t, rows[0].Values, form2.Values{"name": {"jack"}, "roles[]": {"1", "2"}})
+
+	rows = tb.CheckImportData(rows)
+	assert.Equal(t, len(rows[0].Errors), 0)
+	assert.Equal(t, rows[1].Line, 4)
+	assert.Equal(t, len(rows[1].Errors), 1)
+	assert.Equal(t, rows[2].Errors, []string{"name exists"})
+
+	_, _, err = ReadImportFile("users.txt", strings.NewReader(""), tb.GetForm().FieldList)
+	assert.NotEqual(t, err, nil)
+}
+
+func TestCheckImportDataOfPostedRows(t *testing.T) {
+	filtered := 0
+	tb := NewDefaultTable(DefaultConfig()).(DefaultTable)
+	tb.GetForm().AddField("ID", "id", db.Int, form.Default).FieldNotAllowAdd()
+	tb.GetForm().AddField("Name", "name", db.Varchar, form.Text).FieldMust().
+		FieldPostFilterFn(func(value types.PostFieldModel) interface{} {
+			filtered++
+			return value.Value.Value()
+		})
+	tb.GetForm().AddField("Role", "role", db.Varchar, form.Text).
+		FieldPermission(types.FieldReadOnly, "administrator")
+	tb.GetForm().SetPreProcessFn(func(values form2.Values) form2.Values {
+		if values.Get("name") == "panic" {
+			panic("wrong name")
+		}
+		values.Add("name", "changed")
+		return values
+	})
+	tb.Form.FieldList = tb.Form.FieldList.ForUser(models.UserModel{})
+
+	rows := tb.CheckImportData([]ImportRow{
+		{Line: 2, Values: form2.Values{"id": {"1"}, "name": {"jack"}, "role": {"admin"}, "age": {"1"}}},
+		{Line: 3, Values: form2.Values{"name": {"panic"}}},
+	})
+
+	// the PreProcessFn runs on the copy of the values, and the PostFilterFn
+	// only runs when the rows are inserted.
+	assert.Equal(t, rows[0].Values, form2.Values{"name": {"jack"}})
+	assert.Equal(t, len(rows[0].Errors), 0)
+	assert.Equal(t, rows[1].Errors, []string{"wrong name"})
+	assert.Equal(t, filtered, 0)
+}
+
+func TestDefaultTable_ImportData(t *testing.T) {
+	_, done := testDB(t, `create table users (id integer primary key autoincrement, name varchar(50), age int)`)
+	defer done()
+
+	size := ImportBatchSize
+	ImportBatchSize = 1
+	defer func() {
+		ImportBatchSize = size
+	}()
+
+	filtered := 0
+	tb := NewDefaultTable(DefaultConfigWithDriver(db.DriverSqlite)).(DefaultTable)
+	tb.GetForm().AddField("ID", "id", db.Int, form.Default).FieldNotAllowAdd()
+	tb.GetForm().AddField("Name", "name", db.Varchar, form.Text).FieldMust().
+		FieldPostFilterFn(func(value types.PostFieldModel) interface{} {
+			filtered++
+			if value.Tx == nil || value.Value.Value() == "panic" {
+				panic("wrong name")
+			}
+			return value.Value.Value()
+		})
+	tb.GetForm().AddField("Age", "age", db.Int, form.Number)
+	tb.GetForm().SetTable("users")
+	tb.GetInfo().SetTable("users")
+
+	// the PostFilterFn runs once for each row within the transaction.
+	report := tb.ImportData([]ImportRow{
+		{Line: 2, Values: form2.Values{"name": {"jack"}, "age": {"10"}}},
+		{Line: 3, Values: form2.Values{"name": {"panic"}}},
+	})
+	assert.Equal(t, 1, report.Inserted)
+	assert.Equal(t, 1, len(report.Failed))
+	assert.Equal(t, []string{"wrong name"}, report.Failed[0].Errors)
+	assert.Equal(t, 2, filtered)
+
+	count, err := tb.sql().Table("users").Count()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
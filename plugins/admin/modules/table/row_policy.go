@@ -0,0 +1,68 @@
+package table
+
+import (
+	dbsql "database/sql"
+	"errors"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+)
+
+// ErrOutOfScope is returned when the rows to be read or written are out of
+// the row-level policies of the user.
+var ErrOutOfScope = errors.New("permission denied")
+
+// restricted check the rows of the table are restricted by the row-level
+// policies of the user, which only apply to the data from the database.
+func (tb DefaultTable) restricted() bool {
+	return tb.getDataFromDB() && tb.Info.RowPolicies.Restricted(tb.user)
+}
+
+// rowPolicyStatement add the condition of the row-level policies of the
+// user to the wheres of the queries of the info table.
+func (tb DefaultTable) rowPolicyStatement(wheres string, whereArgs []interface{}) (string, []interface{}) {
+	if !tb.restricted() {
+		return wheres, whereArgs
+	}
+	return tb.Info.RowPolicies.Statement(wheres, whereArgs, tb.user, tb.Info.Table, tb.delimiter())
+}
+
+// checkScope check the existing rows of given ids are all in the scope of
+// the user, so that the rows out of it can not be read or written by
+// guessing the primary keys. The rows are queried within the transaction
+// if tx is not nil, which checks the rows just written.
+func (tb DefaultTable) checkScope(tx *dbsql.Tx, ids ...string) error {
+
+	if !tb.restricted() || len(ids) == 0 {
+		return nil
+	}
+
+	policy, args := tb.Info.RowPolicies.Statement("", nil, tb.user, tb.Info.Table, tb.delimiter())
+
+	query := func() *db.SQL {
+		if tx != nil {
+			return tb.sql().WithTx(tx)
+		}
+		return tb.sql()
+	}
+
+	all, err := query().Table(tb.Info.Table).
+		WhereIn(tb.PrimaryKey.Name, interfaces(ids)).
+		Count()
+
+	if err != nil {
+		return err
+	}
+
+	scoped, err := query().Table(tb.Info.Table).
+		WhereIn(tb.PrimaryKey.Name, interfaces(ids)).
+		WhereRaw(policy, args...).
+		Count()
+
+	if err != nil {
+		return err
+	}
+
+	if scoped < all {
+		return ErrOutOfScope
+	}
+	return nil
+}
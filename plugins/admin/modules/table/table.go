@@ -1,11 +1,13 @@
 package table
 
 import (
+	stdctx "context"
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/auth"
 	"github.com/GoAdminGroup/go-admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/paginator"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
@@ -21,22 +23,21 @@ type Generator func(ctx *context.Context) Table
 
 type GeneratorList map[string]Generator
 
-func (g GeneratorList) InjectRoutes(app *context.App, srv service.List) {
-	authHandler := auth.Middleware(db.GetConnection(srv))
+// InjectRoutes add the routes of the callbacks of the tables, such as the
+// ajax actions. The callbacks which need auth are run after the auth
+// middleware and the given middlewares, which are the csrf check of the
+// admin plugin.
+func (g GeneratorList) InjectRoutes(app *context.App, srv service.List, authMiddlewares ...context.Handler) {
+	authHandlers := append([]context.Handler{auth.Middleware(db.GetConnection(srv))}, authMiddlewares...)
 	for _, gen := range g {
 		table := gen(context.NewContext(&http.Request{
 			URL: &url.URL{},
 		}))
-		for _, cb := range table.GetInfo().Callbacks {
+		callbacks := append(append(types.Callbacks{}, table.GetInfo().Callbacks...), table.GetForm().Callbacks...)
+		for _, cb := range callbacks {
 			if cb.Value[constant.ContextNodeNeedAuth] == 1 {
-				app.AppendReqAndResp(cb.Path, cb.Method, append([]context.Handler{authHandler}, cb.Handlers...))
-			} else {
-				app.AppendReqAndResp(cb.Path, cb.Method, cb.Handlers)
-			}
-		}
-		for _, cb := range table.GetForm().Callbacks {
-			if cb.Value[constant.ContextNodeNeedAuth] == 1 {
-				app.AppendReqAndResp(cb.Path, cb.Method, append([]context.Handler{authHandler}, cb.Handlers...))
+				handlers := make([]context.Handler, 0, len(authHandlers)+len(cb.Handlers))
+				app.AppendReqAndResp(cb.Path, cb.Method, append(append(handlers, authHandlers...), cb.Handlers...))
 			} else {
 				app.AppendReqAndResp(cb.Path, cb.Method, cb.Handlers)
 			}
@@ -77,6 +78,7 @@ type Table interface {
 	GetEditable() bool
 	GetDeletable() bool
 	GetExportable() bool
+	GetImportable() bool
 	IsShowDetail() bool
 
 	GetPrimaryKey() PrimaryKey
@@ -84,12 +86,21 @@ type Table interface {
 	GetData(params parameter.Parameters) (PanelInfo, error)
 	GetDataWithIds(params parameter.Parameters) (PanelInfo, error)
 	GetDataWithId(params parameter.Parameters) (FormInfo, error)
+	GetDataWithContext(ctx stdctx.Context, params parameter.Parameters) (PanelInfo, error)
+	GetDataWithIdsWithContext(ctx stdctx.Context, params parameter.Parameters) (PanelInfo, error)
+	GetDataWithIdWithContext(ctx stdctx.Context, params parameter.Parameters) (FormInfo, error)
+	Search(ctx stdctx.Context, keyword string, limit int) ([]map[string]interface{}, error)
 	UpdateData(dataList form.Values) error
 	InsertData(dataList form.Values) error
 	DeleteData(id string) error
+	CheckImportData(rows []ImportRow) []ImportRow
+	ImportData(rows []ImportRow) ImportReport
 
 	GetNewForm() FormInfo
 
+	SetAuditor(auditor Auditor)
+	SetUser(user models.UserModel)
+
 	Copy() Table
 }
 
@@ -101,7 +112,30 @@ type BaseTable struct {
 	Editable   bool
 	Deletable  bool
 	Exportable bool
+	Importable bool
 	PrimaryKey PrimaryKey
+
+	auditor       Auditor
+	user          models.UserModel
+	hiddenColumns []string
+}
+
+// SetAuditor set the Auditor which receives the records of the writings.
+func (base *BaseTable) SetAuditor(auditor Auditor) {
+	base.auditor = auditor
+}
+
+// SetUser set the user of the request, whose row-level policies restrict
+// the rows read and written, and whose field permissions restrict the
+// fields of the panels. The columns of the fields hidden from the user
+// can not be filtered or sorted by either.
+func (base *BaseTable) SetUser(user models.UserModel) {
+	base.user = user
+	base.hiddenColumns = append(base.Info.FieldList.HiddenColumns(user), base.Detail.FieldList.HiddenColumns(user)...)
+	base.hiddenColumns = append(base.hiddenColumns, base.Form.FieldList.HiddenColumns(user)...)
+	base.Info.FieldList = base.Info.FieldList.ForUser(user)
+	base.Detail.FieldList = base.Detail.FieldList.ForUser(user)
+	base.Form.FieldList = base.Form.FieldList.ForUser(user)
 }
 
 func (base *BaseTable) GetInfo() *types.InfoPanel {
@@ -140,6 +174,10 @@ func (base *BaseTable) GetExportable() bool {
 	return base.Exportable && !base.Info.IsHideExportButton
 }
 
+func (base *BaseTable) GetImportable() bool {
+	return base.Importable && base.CanAdd
+}
+
 func (base *BaseTable) GetPaginator(size int, params parameter.Parameters, extraHtml ...template.HTML) types.PaginatorAttribute {
 
 	var eh template.HTML
@@ -158,10 +196,22 @@ func (base *BaseTable) GetPaginator(size int, params parameter.Parameters, extra
 type PanelInfo struct {
 	Thead          types.Thead
 	InfoList       types.InfoList
+	Total          int
 	FilterFormData types.FormFields
 	Paginator      types.PaginatorAttribute
 	Title          string
 	Description    string
+
+	// NextCursor and PreviousCursor are the cursors of the keyset
+	// pagination, an empty cursor means no more page.
+	NextCursor     string
+	PreviousCursor string
+
+	// Aggregates are the contents of the footer row keyed by the field,
+	// GroupBy is the field by which the rows are grouped, the rows are the
+	// subtotals of the groups if it is not empty.
+	Aggregates map[string]template.HTML
+	GroupBy    string
 }
 
 type FormInfo struct {
@@ -0,0 +1,80 @@
+package table
+
+import (
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGeneratorList_InjectRoutes(t *testing.T) {
+	conn, done := testDB(t)
+	defer done()
+
+	csrf := func(ctx *context.Context) {
+		ctx.Abort()
+	}
+
+	app := context.NewApp()
+	srv := service.List{config.Get().Databases.GetDefault().Driver: conn}
+	GeneratorList{"manager": NewSystemTable(conn).GetManagerTable}.InjectRoutes(app, srv, csrf)
+
+	// the actions of the managers are checked against csrf right after the
+	// auth, and before the actions.
+	for _, path := range []string{"/manager/two_factor/reset", "/manager/unlock"} {
+		handlers, ok := app.Handlers[context.Path{URL: config.Get().Url(path), Method: "post"}]
+		assert.True(t, ok, path)
+		assert.Equal(t, 3, len(handlers), path)
+		assert.Equal(t, reflect.ValueOf(csrf).Pointer(), reflect.ValueOf(handlers[1]).Pointer(), path)
+	}
+
+	// the callbacks are injected without the check if it is not given.
+	app = context.NewApp()
+	GeneratorList{"manager": NewSystemTable(conn).GetManagerTable}.InjectRoutes(app, srv)
+	handlers := app.Handlers[context.Path{URL: config.Get().Url("/manager/unlock"), Method: "post"}]
+	assert.Equal(t, 2, len(handlers))
+}
+
+func TestSystemTable_GetUserTokenTable(t *testing.T) {
+	conn, done := testDB(t,
+		`create table goadmin_users (id integer primary key autoincrement, username varchar(100))`,
+		`create table goadmin_user_tokens (id integer primary key autoincrement, user_id int, name varchar(100),
+			token varchar(64), last_used_at timestamp, created_at timestamp, updated_at timestamp)`,
+		`insert into goadmin_users (username) values ('admin'), ('jack')`,
+		`insert into goadmin_user_tokens (user_id, name, token) values (1, 'a', 'a'), (2, 'b', 'b')`)
+	defer done()
+
+	user := models.UserModel{Id: 2, UserName: "jack", Roles: []models.RoleModel{{Slug: "operator"}}}
+	ctx := context.NewContext(httptest.NewRequest("GET", "/", nil))
+	ctx.SetUserValue("user", user)
+
+	tb := NewSystemTable(conn).GetUserTokenTable(ctx).(DefaultTable)
+	tb.connectionDriver = db.DriverSqlite
+	tb.SetUser(user)
+
+	// the tokens of the other users are neither listed, read nor deleted.
+	info, err := tb.GetData(parameter.BaseParam())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, info.Total)
+
+	_, err = tb.GetDataWithId(parameter.BaseParam().WithPKs("1"))
+	assert.Equal(t, ErrOutOfScope, err)
+
+	assert.Equal(t, ErrOutOfScope, tb.DeleteData("1"))
+	assert.Equal(t, ErrOutOfScope, tb.DeleteData("1,2"))
+
+	count, err := tb.sql().Table("goadmin_user_tokens").Count()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	assert.NoError(t, tb.DeleteData("2"))
+	count, err = tb.sql().Table("goadmin_user_tokens").Count()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
@@ -13,13 +13,15 @@ func (admin *Admin) initRouter(prefix string) *Admin {
 
 	route := app.Group(prefix, admin.globalErrorHandler)
 
-	// auth
+	// auth, the pages before login post with the anonymous csrf tokens.
 	route.GET("/login", admin.handler.ShowLogin)
-	route.POST("/signin", admin.handler.Auth)
+	route.POST("/signin", admin.guardian.CheckToken, admin.handler.Auth)
+	route.GET("/login/two_factor", admin.handler.ShowTwoFactorLogin)
+	route.POST("/signin/two_factor", admin.guardian.CheckToken, admin.handler.TwoFactorLogin)
 
 	// auto install
 	route.GET("/install", admin.handler.ShowInstall)
-	route.POST("/install/database/check", admin.handler.CheckDatabase)
+	route.POST("/install/database/check", admin.guardian.CheckToken, admin.handler.CheckDatabase)
 
 	for _, path := range template.Get(config.Get().Theme).GetAssetList() {
 		route.GET("/assets"+path, admin.handler.Assets)
@@ -29,22 +31,32 @@ func (admin *Admin) initRouter(prefix string) *Admin {
 		route.GET("/assets"+path, admin.handler.Assets)
 	}
 
-	authRoute := route.Group("/", auth.Middleware(admin.conn))
+	// every route of the login users is checked against csrf, see guard.CheckToken.
+	authRoute := route.Group("/", auth.Middleware(admin.conn), admin.guardian.CheckToken)
 
 	// auth
 	authRoute.GET("/logout", admin.handler.Logout)
 
-	authPrefixRoute := route.Group("/", auth.Middleware(admin.conn), admin.guardian.CheckPrefix)
+	// two-factor authentication of the current user
+	authRoute.GET("/two_factor", admin.handler.ShowTwoFactor)
+	authRoute.POST("/two_factor/enable", admin.handler.EnableTwoFactor)
+	authRoute.POST("/two_factor/disable", admin.handler.DisableTwoFactor)
+
+	authPrefixRoute := route.Group("/", auth.Middleware(admin.conn), admin.guardian.CheckToken, admin.guardian.CheckPrefix)
 
 	// menus
 	authRoute.POST("/menu/delete", admin.guardian.MenuDelete, admin.handler.DeleteMenu)
 	authRoute.POST("/menu/new", admin.guardian.MenuNew, admin.handler.NewMenu)
 	authRoute.POST("/menu/edit", admin.guardian.MenuEdit, admin.handler.EditMenu)
 	authRoute.POST("/menu/order", admin.handler.MenuOrder)
+
 	authRoute.GET("/menu", admin.handler.ShowMenu)
 	authRoute.GET("/menu/edit/show", admin.handler.ShowEditMenu)
 	authRoute.GET("/menu/new", admin.handler.ShowNewMenu)
 
+	// global search
+	authRoute.GET("/search", admin.handler.Search).Name("search")
+
 	// add delete modify query
 	authPrefixRoute.GET("/info/:__prefix/detail", admin.handler.ShowDetail).Name("detail")
 	authPrefixRoute.GET("/info/:__prefix/edit", admin.guardian.ShowForm, admin.handler.ShowForm).Name("show_edit")
@@ -53,10 +65,25 @@ func (admin *Admin) initRouter(prefix string) *Admin {
 	authPrefixRoute.POST("/new/:__prefix", admin.guardian.NewForm, admin.handler.NewForm).Name("new")
 	authPrefixRoute.POST("/delete/:__prefix", admin.guardian.Delete, admin.handler.Delete).Name("delete")
 	authPrefixRoute.POST("/export/:__prefix", admin.guardian.Export, admin.handler.Export).Name("export")
+	authPrefixRoute.GET("/info/:__prefix/import", admin.guardian.ShowImport, admin.handler.ShowImport).Name("show_import")
+	authPrefixRoute.POST("/import/:__prefix/preview", admin.guardian.ImportPreview, admin.handler.ImportPreview).Name("import_preview")
+	authPrefixRoute.POST("/import/:__prefix", admin.guardian.Import, admin.handler.Import).Name("import")
 	authPrefixRoute.GET("/info/:__prefix", admin.handler.ShowInfo).Name("info")
+	authPrefixRoute.POST("/info/:__prefix/views", admin.handler.SaveView).Name("save_view")
+	authPrefixRoute.POST("/info/:__prefix/views/delete", admin.handler.DeleteView).Name("delete_view")
 
 	authPrefixRoute.POST("/update/:__prefix", admin.guardian.Update, admin.handler.Update).Name("update")
 
+	// json api, the routes mirror the pages above so that they share the same permissions.
+	apiRoute := route.Group(config.Get().ApiPrefix(), auth.ApiMiddleware(admin.conn), admin.guardian.CheckToken,
+		admin.guardian.CheckApiPrefix)
+
+	apiRoute.GET("/info/:__prefix", admin.guardian.ApiList, admin.handler.ApiList).Name("api_info")
+	apiRoute.GET("/info/:__prefix/detail", admin.guardian.ApiDetail, admin.handler.ApiDetail).Name("api_detail")
+	apiRoute.POST("/new/:__prefix", admin.guardian.ApiCreate, admin.handler.ApiCreate).Name("api_new")
+	apiRoute.POST("/edit/:__prefix", admin.guardian.ApiUpdate, admin.handler.ApiUpdate).Name("api_edit")
+	apiRoute.POST("/delete/:__prefix", admin.guardian.ApiDelete, admin.handler.ApiDelete).Name("api_delete")
+
 	admin.app = app
 	return admin
 }
@@ -3,6 +3,7 @@ package components
 import (
 	"github.com/GoAdminGroup/go-admin/template/types"
 	"html/template"
+	"strings"
 )
 
 type TableAttribute struct {
@@ -21,6 +22,7 @@ type TableAttribute struct {
 	Layout     string
 	IsTab      bool
 	ExportUrl  string
+	Footer     template.HTML
 	types.Attribute
 }
 
@@ -49,11 +51,38 @@ func (compo *TableAttribute) SetLayout(value string) types.TableAttribute {
 	return compo
 }
 
+// SetFooter set the cells of the footer row of the table, one for each of
+// the shown fields.
+func (compo *TableAttribute) SetFooter(value template.HTML) types.TableAttribute {
+	compo.Footer = value
+	return compo
+}
+
 func (compo *TableAttribute) GetContent() template.HTML {
 	if compo.MinWidth == 0 {
 		compo.MinWidth = 1000
 	}
-	return ComposeHtml(compo.TemplateList, *compo, "table")
+	return withFooter(ComposeHtml(compo.TemplateList, *compo, "table"), compo.Footer, false, !compo.NoAction)
+}
+
+// withFooter add the footer row to the end of the table rendered by the
+// theme. The row is padded with the cells of the row selector, which is
+// the first column, and of the actions, which is the last one.
+func withFooter(table, footer template.HTML, hasRowSelector, hasAction bool) template.HTML {
+	end := strings.LastIndex(string(table), "</table>")
+	if footer == "" || end < 0 {
+		return table
+	}
+	row := `<tfoot class="table-footer"><tr>`
+	if hasRowSelector {
+		row += "<td></td>"
+	}
+	row += string(footer)
+	if hasAction {
+		row += "<td></td>"
+	}
+	row += "</tr></tfoot>"
+	return table[:end] + template.HTML(row) + table[end:]
 }
 
 type DataTableAttribute struct {
@@ -171,6 +200,13 @@ func (compo *DataTableAttribute) SetNewUrl(value string) types.DataTableAttribut
 	return compo
 }
 
+// SetFooter set the cells of the footer row of the table, one for each of
+// the shown fields.
+func (compo *DataTableAttribute) SetFooter(value template.HTML) types.DataTableAttribute {
+	compo.Footer = value
+	return compo
+}
+
 func (compo *DataTableAttribute) GetContent() template.HTML {
 	if compo.MinWidth == 0 {
 		compo.MinWidth = 1000
@@ -178,5 +214,6 @@ func (compo *DataTableAttribute) GetContent() template.HTML {
 	if compo.EditUrl == "" && compo.DeleteUrl == "" && compo.DetailUrl == "" && compo.Action == "" {
 		compo.NoAction = true
 	}
-	return ComposeHtml(compo.TemplateList, *compo, "table")
+	return withFooter(ComposeHtml(compo.TemplateList, *compo, "table"), compo.Footer,
+		!compo.IsHideRowSelector, !compo.NoAction)
 }
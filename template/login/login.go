@@ -20,7 +20,17 @@ func GetLoginComponent() *Login {
 }
 
 func (l *Login) GetTemplate() (*template.Template, string) {
-	tmpl, err := template.New("login_theme1").
+	return parse("login_theme1", "login/theme1")
+}
+
+// GetTwoFactorTemplate return the template of the second step of login,
+// which checks the two-factor authentication code.
+func (l *Login) GetTwoFactorTemplate() (*template.Template, string) {
+	return parse("login_two_factor", "login/two_factor")
+}
+
+func parse(name, key string) (*template.Template, string) {
+	tmpl, err := template.New(name).
 		Funcs(template.FuncMap{
 			"lang":     language.Get,
 			"langHtml": language.GetFromHtml,
@@ -43,13 +53,13 @@ func (l *Login) GetTemplate() (*template.Template, string) {
 				return a / b
 			},
 		}).
-		Parse(List["login/theme1"])
+		Parse(List[key])
 
 	if err != nil {
 		logger.Error("Login GetTemplate Error: ", err)
 	}
 
-	return tmpl, "login_theme1"
+	return tmpl, name
 }
 
 func (l *Login) GetAssetList() []string {
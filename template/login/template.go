@@ -76,7 +76,8 @@ var List = map[string]string{"login/theme1": `{{define "login_theme1"}}
                 async: 'true',
                 data: {
                     'username': $("#username").val(),
-                    'password': $("#password").val()
+                    'password': $("#password").val(),
+                    '__go_admin_t_': '{{.Token}}'
                 },
                 success: function (data) {
                     location.href = data.data.url
@@ -88,6 +89,93 @@ var List = map[string]string{"login/theme1": `{{define "login_theme1"}}
         });
     </script>
 
+    </body>
+    </html>
+{{end}}`,
+	"login/two_factor": `{{define "login_two_factor"}}
+    <!DOCTYPE html>
+    <html class="no-js">
+    <head>
+        <meta charset="utf-8">
+        <meta http-equiv="X-UA-Compatible" content="IE=edge">
+        <title>{{.Title}}</title>
+        <meta name="viewport" content="width=device-width, initial-scale=1">
+
+        <link rel="stylesheet" href="{{link .CdnUrl .UrlPrefix "/assets/login/dist/all.min.css"}}">
+
+    </head>
+    <body>
+
+    <div class="container">
+        <div class="row" style="margin-top: 80px;">
+            <div class="col-md-4 col-md-offset-4">
+                <form action="{{.UrlPrefix}}/signin/two_factor" method="post" id="two-factor-form"
+                      class="fh5co-form animate-box" data-animate-effect="fadeIn">
+                    <h2>{{lang "two-factor authentication"}}</h2>
+                    {{if .QRCode}}
+                        <p>{{lang "scan the qr code with the authenticator app, or enter the secret: "}}</p>
+                        <p class="text-center"><img src="{{.QRCode}}" width="200" height="200" alt="qr code"></p>
+                        <p class="text-center"><code>{{.Secret}}</code></p>
+                    {{else}}
+                        <p>{{lang "enter the code of the authenticator app or a recovery code"}}</p>
+                    {{end}}
+                    <div class="form-group">
+                        <label for="code" class="sr-only">Code</label>
+                        <input type="text" class="form-control" id="code" placeholder="{{lang "code"}}"
+                               autocomplete="off" autofocus>
+                    </div>
+                    <div class="form-group">
+                        <button class="btn btn-primary">{{lang "verify"}}</button>
+                    </div>
+                    <div id="recovery-codes" style="display: none;">
+                        <p>{{lang "save the recovery codes, each of them can be used once to login without the authenticator app"}}</p>
+                        <pre></pre>
+                        <a class="btn btn-primary" href="javascript:;">{{lang "continue"}}</a>
+                    </div>
+                </form>
+            </div>
+        </div>
+    </div>
+
+    <div id="particles-js">
+        <canvas class="particles-js-canvas-el" width="1606" height="1862" style="width: 100%; height: 100%;"></canvas>
+    </div>
+
+    <script src="{{link .CdnUrl .UrlPrefix "/assets/login/dist/all.min.js"}}"></script>
+
+    <script>
+        $("#two-factor-form").submit(function (e) {
+            e.preventDefault();
+            $.ajax({
+                dataType: 'json',
+                type: 'POST',
+                url: '{{.UrlPrefix}}/signin/two_factor',
+                async: 'true',
+                data: {
+                    'code': $("#code").val(),
+                    '__go_admin_t_': '{{.Token}}'
+                },
+                success: function (data) {
+                    if (data.data.recovery_codes) {
+                        $("#two-factor-form .form-group").hide();
+                        $("#recovery-codes pre").text(data.data.recovery_codes.join("\n"));
+                        $("#recovery-codes a").attr("href", data.data.url);
+                        $("#recovery-codes").show();
+                        return
+                    }
+                    location.href = data.data.url
+                },
+                error: function (data) {
+                    if (data.responseJSON && data.responseJSON.data && data.responseJSON.data.url) {
+                        location.href = data.responseJSON.data.url;
+                        return
+                    }
+                    alert(data.responseJSON ? data.responseJSON.msg : '{{lang "login fail"}}');
+                }
+            });
+        });
+    </script>
+
     </body>
     </html>
 {{end}}`}
@@ -0,0 +1,159 @@
+package types
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
+)
+
+// Aggregate is an aggregate function of a field over the filtered rows
+// of the info table.
+type Aggregate string
+
+const (
+	AggregateSum   Aggregate = "sum"
+	AggregateAvg   Aggregate = "avg"
+	AggregateMin   Aggregate = "min"
+	AggregateMax   Aggregate = "max"
+	AggregateCount Aggregate = "count"
+)
+
+// Alias return the column alias of the aggregate of the field.
+func (a Aggregate) Alias(field string) string {
+	return "__goadmin_" + string(a) + "_" + field
+}
+
+// Statement return the aggregate expression of the column.
+func (a Aggregate) Statement(column string) string {
+	return string(a) + "(" + column + ")"
+}
+
+// FieldAggregate add the aggregates of the field, which are shown in the
+// footer row of the data table and as the subtotals of the group by mode.
+// The default aggregate is the sum.
+func (i *InfoPanel) FieldAggregate(aggregates ...Aggregate) *InfoPanel {
+	if len(aggregates) == 0 {
+		aggregates = []Aggregate{AggregateSum}
+	}
+	i.FieldList[i.curFieldListIndex].Aggregates = append(i.FieldList[i.curFieldListIndex].Aggregates, aggregates...)
+	return i
+}
+
+// FieldGroupable allow the rows to be collapsed by the field, the group by
+// field is chosen in the header of the data table.
+func (i *InfoPanel) FieldGroupable() *InfoPanel {
+	i.FieldList[i.curFieldListIndex].Groupable = true
+	return i
+}
+
+// IsAggregated check the field has aggregates which can be queried, only
+// the columns of the table can be aggregated.
+func (f Field) IsAggregated(columns []string) bool {
+	return len(f.Aggregates) > 0 && !f.JoinChain().Valid() && modules.InArray(columns, f.Field)
+}
+
+// HasAggregate check the list has any aggregated field.
+func (f FieldList) HasAggregate(columns []string) bool {
+	for _, field := range f {
+		if field.IsAggregated(columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// AggregateFields return the aggregate expressions of the fields to be
+// selected, separated by comma.
+func (f FieldList) AggregateFields(table, delimiter string, columns []string) string {
+	fields := make([]string, 0)
+	for _, field := range f {
+		if !field.IsAggregated(columns) {
+			continue
+		}
+		column := table + "." + modules.FilterField(field.Field, delimiter)
+		for _, aggregate := range field.Aggregates {
+			fields = append(fields, aggregate.Statement(column)+" as "+
+				modules.FilterField(aggregate.Alias(field.Field), delimiter))
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// GetGroupableField return the groupable field of given name, only the
+// columns of the table can be grouped by.
+func (f FieldList) GetGroupableField(name string, columns []string) (Field, bool) {
+	if name == "" {
+		return Field{}, false
+	}
+	for _, field := range f {
+		if field.Field == name && field.Groupable && !field.JoinChain().Valid() && modules.InArray(columns, field.Field) {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+type groupByItem struct {
+	Head   string
+	URL    string
+	Active bool
+}
+
+var groupBySelectorTmpl = template.Must(template.New("group_by").Funcs(template.FuncMap{"lang": language.Get}).Parse(`
+<div class="btn-group pull-right" style="margin-right: 10px;">
+<button type="button" class="btn btn-sm btn-default dropdown-toggle" data-toggle="dropdown">
+<i class="fa fa-object-group"></i> {{lang "group by"}}{{if .Current}}: {{.Current}}{{end}} <span class="caret"></span>
+</button>
+<ul class="dropdown-menu" role="menu">
+<li{{if not .Current}} class="active"{{end}}><a href="{{.None}}">{{lang "no group"}}</a></li>
+{{- range .Items}}<li{{if .Active}} class="active"{{end}}><a href="{{.URL}}">{{.Head}}</a></li>{{end}}
+</ul>
+</div>`))
+
+// GroupBySelector render the dropdown to choose the group by field, url
+// return the link of the field, and of no group when the field is empty.
+// It returns empty when there is no groupable field.
+func (f FieldList) GroupBySelector(current string, url func(field string) string) template.HTML {
+	var (
+		items = make([]groupByItem, 0)
+		head  = ""
+	)
+	for _, field := range f {
+		if field.Groupable {
+			items = append(items, groupByItem{Head: field.Head, URL: url(field.Field), Active: field.Field == current})
+			if field.Field == current {
+				head = field.Head
+			}
+		}
+	}
+	if len(items) == 0 {
+		return ""
+	}
+	buf := new(bytes.Buffer)
+	_ = groupBySelectorTmpl.Execute(buf, map[string]interface{}{
+		"Current": head,
+		"None":    url(""),
+		"Items":   items,
+	})
+	return template.HTML(buf.String())
+}
+
+var aggregateFooterTmpl = template.Must(template.New("aggregate_footer").Parse(
+	`{{range .}}<td><b>{{.}}</b></td>{{end}}`))
+
+// AggregateFooter render the cells of the footer row of the data table,
+// values are the contents of the cells keyed by the field.
+func (t Thead) AggregateFooter(values map[string]template.HTML) template.HTML {
+	cells := make([]template.HTML, 0, len(t))
+	for _, item := range t {
+		if !item.Hide {
+			cells = append(cells, values[item.Field])
+		}
+	}
+	buf := new(bytes.Buffer)
+	_ = aggregateFooterTmpl.Execute(buf, cells)
+	return template.HTML(buf.String())
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/stretchr/testify/assert"
+	"html/template"
+	"testing"
+)
+
+func TestFieldList_AggregateFields(t *testing.T) {
+	info := NewInfoPanel("id")
+	info.AddField("ID", "id", db.Int)
+	info.AddField("Price", "price", db.Int).FieldAggregate()
+	info.AddField("Amount", "amount", db.Int).FieldAggregate(AggregateMin, AggregateMax)
+	info.AddField("Region", "region", db.Varchar).FieldGroupable()
+	info.AddField("Author", "name", db.Varchar).FieldAggregate(AggregateCount).
+		FieldJoin(Join{Table: "users", Field: "author_id", JoinField: "id"})
+
+	columns := []string{"id", "price", "amount", "region", "author_id"}
+
+	assert.True(t, info.FieldList.HasAggregate(columns))
+	assert.False(t, info.FieldList.HasAggregate([]string{"id", "region"}))
+
+	// the joined fields are not aggregated.
+	assert.Equal(t, "sum(orders.`price`) as `__goadmin_sum_price`,"+
+		"min(orders.`amount`) as `__goadmin_min_amount`,"+
+		"max(orders.`amount`) as `__goadmin_max_amount`",
+		info.FieldList.AggregateFields("orders", "`", columns))
+
+	field, ok := info.FieldList.GetGroupableField("region", columns)
+	assert.True(t, ok)
+	assert.Equal(t, "region", field.Field)
+	_, ok = info.FieldList.GetGroupableField("price", columns)
+	assert.False(t, ok)
+	_, ok = info.FieldList.GetGroupableField("region", []string{"id"})
+	assert.False(t, ok)
+}
+
+func TestThead_AggregateFooter(t *testing.T) {
+	thead := Thead{{Field: "id"}, {Field: "price"}, {Field: "secret", Hide: true}, {Field: "amount"}}
+
+	assert.Equal(t, template.HTML("<td><b></b></td><td><b>Sum: 10</b></td><td><b>Min: 1<br>Max: 3</b></td>"),
+		thead.AggregateFooter(map[string]template.HTML{
+			"price":  "Sum: 10",
+			"secret": "Sum: 1",
+			"amount": "Min: 1<br>Max: 3",
+		}))
+}
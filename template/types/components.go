@@ -102,6 +102,7 @@ type TableAttribute interface {
 	SetType(value string) TableAttribute
 	SetMinWidth(value int) TableAttribute
 	SetLayout(value string) TableAttribute
+	SetFooter(value template.HTML) TableAttribute
 	GetContent() template.HTML
 }
 
@@ -125,6 +126,7 @@ type DataTableAttribute interface {
 	SetHasFilter(hasFilter bool) DataTableAttribute
 	SetExportUrl(value string) DataTableAttribute
 	SetUpdateUrl(value string) DataTableAttribute
+	SetFooter(value template.HTML) DataTableAttribute
 	GetContent() template.HTML
 }
 
@@ -0,0 +1,143 @@
+package types
+
+import (
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+// FieldAccess is the access of a user to a field of the panels.
+type FieldAccess uint8
+
+const (
+	// FieldEditable fields are shown and can be changed.
+	FieldEditable FieldAccess = iota
+	// FieldReadOnly fields are shown but can not be changed.
+	FieldReadOnly
+	// FieldHidden fields are neither shown nor changed.
+	FieldHidden
+)
+
+// FieldPermission grants the access to the users who have any of the
+// slugs, which are the slugs of the roles or the permissions.
+type FieldPermission struct {
+	Access FieldAccess
+	Slugs  []string
+}
+
+type FieldPermissions []FieldPermission
+
+// Access return the access of the user to the field. The field is editable
+// for every user when there is no permission, and for the super admin.
+// Otherwise the user has the widest access granted to it, and the field
+// is hidden when nothing is granted.
+func (f FieldPermissions) Access(user models.UserModel) FieldAccess {
+	if len(f) == 0 || user.IsSuperAdmin() {
+		return FieldEditable
+	}
+	access := FieldHidden
+	for _, permission := range f {
+		if permission.Access < access && permission.granted(user) {
+			access = permission.Access
+		}
+	}
+	return access
+}
+
+func (p FieldPermission) granted(user models.UserModel) bool {
+	for _, slug := range p.Slugs {
+		if user.CheckRole(slug) || user.CheckPermission(slug) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldPermission grant the access to the current field to the roles or
+// permissions of given slugs.
+func (i *InfoPanel) FieldPermission(access FieldAccess, slugs ...string) *InfoPanel {
+	i.FieldList[i.curFieldListIndex].Permissions = append(i.FieldList[i.curFieldListIndex].Permissions,
+		FieldPermission{Access: access, Slugs: slugs})
+	return i
+}
+
+// FieldPermission grant the access to the current field to the roles or
+// permissions of given slugs.
+func (f *FormPanel) FieldPermission(access FieldAccess, slugs ...string) *FormPanel {
+	f.FieldList[f.curFieldListIndex].Permissions = append(f.FieldList[f.curFieldListIndex].Permissions,
+		FieldPermission{Access: access, Slugs: slugs})
+	return f
+}
+
+// ForUser return the fields which the user can see, the read-only ones can
+// not be edited in the list.
+func (f FieldList) ForUser(user models.UserModel) FieldList {
+	list := make(FieldList, 0, len(f))
+	for _, field := range f {
+		switch field.Permissions.Access(user) {
+		case FieldHidden:
+			continue
+		case FieldReadOnly:
+			field.EditAble = false
+		}
+		list = append(list, field)
+	}
+	return list
+}
+
+// HiddenColumns return the columns of the fields hidden from the user, the
+// joined fields are left out as they are not the columns of the table.
+func (f FieldList) HiddenColumns(user models.UserModel) []string {
+	columns := make([]string, 0)
+	for _, field := range f {
+		if !field.JoinChain().Valid() && field.Permissions.Access(user) == FieldHidden {
+			columns = append(columns, field.Field)
+		}
+	}
+	return columns
+}
+
+// ForUser return the fields with the access of the user. The hidden fields
+// are kept for checking the posted values but never rendered, and neither
+// of them nor the read-only ones can be added or edited. The access to the
+// fields of the child rows of the has many fields is set too.
+func (f FormFields) ForUser(user models.UserModel) FormFields {
+	list := make(FormFields, len(f))
+	copy(list, f)
+	for i := range list {
+		list[i].Access = list[i].Permissions.Access(user)
+		if list[i].Access != FieldEditable {
+			list[i].Editable = false
+			list[i].NotAllowAdd = true
+		}
+		if list[i].Access == FieldHidden {
+			list[i].Hide = true
+		}
+		if list[i].IsHasMany() {
+			child := *list[i].HasMany.Form
+			child.FieldList = child.FieldList.ForUser(user)
+			list[i].HasMany.Form = &child
+		}
+	}
+	return list
+}
+
+// HiddenColumns return the columns of the fields hidden from the user.
+func (f FormFields) HiddenColumns(user models.UserModel) []string {
+	columns := make([]string, 0)
+	for _, field := range f {
+		if field.Permissions.Access(user) == FieldHidden {
+			columns = append(columns, field.Field)
+		}
+	}
+	return columns
+}
+
+// Visible return the fields except the hidden ones of the user.
+func (f FormFields) Visible() FormFields {
+	list := make(FormFields, 0, len(f))
+	for _, field := range f {
+		if field.Access != FieldHidden {
+			list = append(list, field)
+		}
+	}
+	return list
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFieldPermissions_Access(t *testing.T) {
+	info := NewInfoPanel("id")
+	info.AddField("Name", "name", db.Varchar).FieldEditAble()
+	info.AddField("Salary", "salary", db.Int).FieldPermission(FieldEditable, "hr")
+	info.AddField("Level", "level", db.Varchar).FieldEditAble().
+		FieldPermission(FieldReadOnly, "staff").
+		FieldPermission(FieldEditable, "hr", "level_edit")
+
+	staff := models.UserModel{Id: 2, Roles: []models.RoleModel{{Slug: "staff"}}}
+	editor := models.UserModel{Id: 3, Roles: []models.RoleModel{{Slug: "staff"}},
+		Permissions: []models.PermissionModel{{Slug: "level_edit"}}}
+	admin := models.UserModel{Id: 1, Permissions: []models.PermissionModel{{HttpPath: []string{"*"}}}}
+
+	assert.Equal(t, FieldHidden, info.FieldList[1].Permissions.Access(staff))
+	assert.Equal(t, FieldReadOnly, info.FieldList[2].Permissions.Access(staff))
+	assert.Equal(t, FieldEditable, info.FieldList[2].Permissions.Access(editor))
+	assert.Equal(t, FieldEditable, info.FieldList[1].Permissions.Access(admin))
+	assert.Equal(t, FieldEditable, info.FieldList[0].Permissions.Access(models.UserModel{}))
+
+	list := info.FieldList.ForUser(staff)
+	assert.Equal(t, 2, len(list))
+	assert.True(t, list[0].EditAble)
+	assert.False(t, list[1].EditAble)
+	assert.True(t, info.FieldList[2].EditAble, "the fields of the panel are not changed")
+
+	f := NewFormPanel()
+	f.AddField("Name", "name", db.Varchar, form.Text)
+	f.AddField("Salary", "salary", db.Int, form.Number).FieldPermission(FieldEditable, "hr")
+	f.AddField("Level", "level", db.Varchar, form.Text).FieldPermission(FieldReadOnly, "staff")
+
+	f.FieldList = f.FieldList.ForUser(staff)
+	assert.Equal(t, FieldHidden, f.FieldList[1].Access)
+	assert.Equal(t, FieldReadOnly, f.FieldList[2].Access)
+	assert.False(t, f.FieldList[2].Editable)
+
+	fields := f.FieldsWithValue("1", []string{"name", "salary", "level"},
+		map[string]interface{}{"name": "jack", "salary": 10, "level": "a"})
+	assert.Equal(t, 2, len(fields))
+	assert.Equal(t, "level", fields[1].Field)
+	assert.Equal(t, 1, len(f.FieldsWithDefaultValue()))
+}
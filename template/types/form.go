@@ -2,6 +2,7 @@ package types
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"github.com/GoAdminGroup/go-admin/context"
@@ -94,6 +95,15 @@ type OptionTableQueryProcessFn func(sql *db.SQL) *db.SQL
 
 type OptionProcessFn func(options FieldOptions) FieldOptions
 
+// Relation is a many-to-many relation of the form field. The values of the
+// field are the ForeignKey of the rows in the Pivot table whose LocalKey is
+// the primary key of the form row.
+type Relation struct {
+	Pivot      string
+	LocalKey   string
+	ForeignKey string
+}
+
 // FormField is the form field with different options.
 type FormField struct {
 	Field    string
@@ -119,6 +129,9 @@ type FormField struct {
 	Must        bool
 	Hide        bool
 
+	Permissions FieldPermissions
+	Access      FieldAccess
+
 	Width int
 
 	HelpMsg template.HTML
@@ -126,11 +139,18 @@ type FormField struct {
 	OptionExt    template.JS
 	OptionInitFn OptionInitFn
 	OptionTable  OptionTable
+	Relation     Relation
+	HasMany      HasMany
 
 	FieldDisplay
 	PostFilterFn PostFieldFilterFn
 }
 
+// IsRelation check the field is a many-to-many relation or not.
+func (f FormField) IsRelation() bool {
+	return f.Relation.Pivot != ""
+}
+
 func (f FormField) UpdateValue(id, val string, res map[string]interface{}, sqls ...*db.SQL) FormField {
 	if f.FormType.IsSelect() {
 		if len(f.Options) == 0 && f.OptionInitFn != nil {
@@ -233,6 +253,11 @@ func (f FormField) UpdateDefaultValue(sqls ...*db.SQL) FormField {
 }
 
 func (f FormField) FillCustomContent() FormField {
+	// the content of the has many field is rendered with the row values,
+	// which are not templates.
+	if f.IsHasMany() {
+		return f
+	}
 	// TODO: optimize
 	if f.CustomContent != "" {
 		f.CustomContent = template.HTML(f.fillCustom(string(f.CustomContent)))
@@ -269,6 +294,7 @@ type FormPanel struct {
 
 	Validator    FormPostFn
 	PostHook     FormPostFn
+	TxPostHook   FormTxPostFn
 	PreProcessFn FormPreProcessFn
 
 	Callbacks Callbacks
@@ -525,6 +551,28 @@ func (f *FormPanel) FieldOptionsFromTable(table, textFieldName, valueFieldName s
 	return f
 }
 
+// FieldRelation make the field a many-to-many relation of the pivot table,
+// which renders as a multi-select of the options set by FieldOptionsFromTable
+// or FieldOptions. The field should not be a column of the form table.
+func (f *FormPanel) FieldRelation(pivot, localKey, foreignKey string) *FormPanel {
+	field := f.FieldList[f.curFieldListIndex].Field
+	f.FieldList[f.curFieldListIndex].Relation = Relation{
+		Pivot:      pivot,
+		LocalKey:   localKey,
+		ForeignKey: foreignKey,
+	}
+	if !f.FieldList[f.curFieldListIndex].FormType.IsMultiSelect() {
+		f.FieldList[f.curFieldListIndex].FormType = form2.Select
+	}
+	f.FieldList[f.curFieldListIndex].Display = func(value FieldModel) interface{} {
+		if values, ok := value.Row[field].([]string); ok {
+			return values
+		}
+		return []string{}
+	}
+	return f
+}
+
 func (f *FormPanel) FieldOptionsTableProcessFn(fn OptionProcessFn) *FormPanel {
 	f.FieldList[f.curFieldListIndex].OptionTable.ProcessFn = fn
 	return f
@@ -884,11 +932,19 @@ func (f *FormPanel) SetPreProcessFn(fn FormPreProcessFn) *FormPanel {
 	return f
 }
 
+// SetPostHook set the hook which is called in a goroutine after the insertion or updating.
 func (f *FormPanel) SetPostHook(fn FormPostFn) *FormPanel {
 	f.PostHook = fn
 	return f
 }
 
+// SetTxPostHook set the hook which is called within the transaction of the insertion
+// or updating.
+func (f *FormPanel) SetTxPostHook(fn FormTxPostFn) *FormPanel {
+	f.TxPostHook = fn
+	return f
+}
+
 func (f *FormPanel) SetUpdateFn(fn FormPostFn) *FormPanel {
 	f.UpdateFn = fn
 	return f
@@ -907,20 +963,23 @@ func (f *FormPanel) GroupFieldWithValue(id string, columns []string, res map[str
 
 	if len(f.TabGroups) > 0 {
 		for key, value := range f.TabGroups {
-			list := make(FormFields, len(value))
+			list := make(FormFields, 0, len(value))
 			for j := 0; j < len(value); j++ {
-				for _, field := range f.FieldList {
+				for _, field := range f.FieldList.Visible() {
 					if value[j] == field.Field {
 						rowValue := modules.AorB(modules.InArray(columns, field.Field) || len(columns) == 0,
 							db.GetValueFromDatabaseType(field.TypeName, res[field.Field], len(columns) == 0).String(), "")
-						if len(sql) > 0 {
-							list[j] = field.UpdateValue(id, rowValue, res, sql[0]())
+						if field.IsHasMany() {
+							field = field.updateHasManyValue(res, sql...)
+						} else if len(sql) > 0 {
+							field = field.UpdateValue(id, rowValue, res, sql[0]())
 						} else {
-							list[j] = field.UpdateValue(id, rowValue, res)
+							field = field.UpdateValue(id, rowValue, res)
 						}
-						if list[j].FormType == form2.File && list[j].Value != template.HTML("") {
-							list[j].Value2 = config.Get().Store.URL(string(list[j].Value))
+						if field.FormType == form2.File && field.Value != template.HTML("") {
+							field.Value2 = config.Get().Store.URL(string(field.Value))
 						}
+						list = append(list, field)
 						break
 					}
 				}
@@ -948,7 +1007,9 @@ func (f *FormPanel) GroupField(sql ...func() *db.SQL) ([]FormFields, []string) {
 					if v.Field == value[i] {
 						if !v.NotAllowAdd {
 							v.Editable = true
-							if len(sql) > 0 {
+							if v.IsHasMany() {
+								list = append(list, v.updateHasManyValue(nil, sql...))
+							} else if len(sql) > 0 {
 								list = append(list, v.UpdateDefaultValue(sql[0]()).FillCustomContent())
 							} else {
 								list = append(list, v.UpdateDefaultValue())
@@ -966,11 +1027,13 @@ func (f *FormPanel) GroupField(sql ...func() *db.SQL) ([]FormFields, []string) {
 }
 
 func (f *FormPanel) FieldsWithValue(id string, columns []string, res map[string]interface{}, sql ...func() *db.SQL) FormFields {
-	formList := f.FieldList.Copy()
+	formList := f.FieldList.Visible().Copy()
 	for key, field := range formList {
 		rowValue := modules.AorB(modules.InArray(columns, field.Field) || len(columns) == 0,
 			db.GetValueFromDatabaseType(field.TypeName, res[field.Field], len(columns) == 0).String(), "")
-		if len(sql) > 0 {
+		if field.IsHasMany() {
+			formList[key] = field.updateHasManyValue(res, sql...)
+		} else if len(sql) > 0 {
 			formList[key] = field.UpdateValue(id, rowValue, res, sql[0]())
 		} else {
 			formList[key] = field.UpdateValue(id, rowValue, res)
@@ -988,7 +1051,9 @@ func (f *FormPanel) FieldsWithDefaultValue(sql ...func() *db.SQL) FormFields {
 	for _, v := range f.FieldList {
 		if !v.NotAllowAdd {
 			v.Editable = true
-			if len(sql) > 0 {
+			if v.IsHasMany() {
+				newForm = append(newForm, v.updateHasManyValue(nil, sql...))
+			} else if len(sql) > 0 {
 				newForm = append(newForm, v.UpdateDefaultValue(sql[0]()))
 			} else {
 				newForm = append(newForm, v.UpdateDefaultValue())
@@ -1002,6 +1067,10 @@ type FormPreProcessFn func(values form.Values) form.Values
 
 type FormPostFn func(values form.Values) error
 
+// FormTxPostFn is called within the transaction of the form writing, the
+// writing will be rolled back if it returns an error.
+type FormTxPostFn func(tx *sql.Tx, values form.Values) error
+
 type FormFields []FormField
 
 type GroupFormFields []FormFields
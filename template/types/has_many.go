@@ -0,0 +1,323 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	form2 "github.com/GoAdminGroup/go-admin/template/types/form"
+)
+
+// HasMany is a one-to-many relation of the form field. The child rows are
+// the rows of the table of Form whose ForeignKey is the primary key of the
+// form row, they are edited inline with the fields of Form and saved along
+// with the form row. The position of the rows is saved to OrderField if it
+// is set.
+type HasMany struct {
+	Form       *FormPanel
+	PrimaryKey string
+	ForeignKey string
+	OrderField string
+}
+
+// IsHasMany check the field is a one-to-many relation or not.
+func (f FormField) IsHasMany() bool {
+	return f.HasMany.Form != nil
+}
+
+// FieldHasMany make the field a one-to-many relation of the child form,
+// which renders as repeatable rows of the child fields. The field should
+// not be a column of the form table.
+func (f *FormPanel) FieldHasMany(child *FormPanel, primaryKey, foreignKey string, orderField ...string) *FormPanel {
+	f.FieldList[f.curFieldListIndex].FormType = form2.Custom
+	f.FieldList[f.curFieldListIndex].HasMany = HasMany{
+		Form:       child,
+		PrimaryKey: primaryKey,
+		ForeignKey: foreignKey,
+	}
+	if len(orderField) > 0 {
+		f.FieldList[f.curFieldListIndex].HasMany.OrderField = orderField[0]
+	}
+	return f
+}
+
+// updateHasManyValue render the child rows of the has many field, which
+// are the value of the field in res.
+func (f FormField) updateHasManyValue(res map[string]interface{}, sql ...func() *db.SQL) FormField {
+	rows, _ := res[f.Field].([]map[string]interface{})
+	f.CustomContent, f.CustomJs = f.HasMany.render(f.Field, rows, sql...)
+	return f
+}
+
+// Rows return the posted child rows of the has many field ordered by their
+// positions, and false if the field is not posted.
+func (h HasMany) Rows(field string, values form.Values) ([]form.Values, bool) {
+	if values.Get(field) == "" {
+		return nil, false
+	}
+
+	var (
+		prefix  = field + "["
+		rows    = make(map[int]form.Values)
+		indexes = make([]int, 0)
+	)
+
+	for key, value := range values {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		end := strings.Index(key[len(prefix):], "]")
+		if end < 0 {
+			continue
+		}
+		index, err := strconv.Atoi(key[len(prefix) : len(prefix)+end])
+		if err != nil {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key[len(prefix)+end+1:], "["), "]")
+		if strings.HasSuffix(name, "][") {
+			name = strings.TrimSuffix(name, "][") + "[]"
+		}
+		if _, ok := rows[index]; !ok {
+			rows[index] = make(form.Values)
+			indexes = append(indexes, index)
+		}
+		rows[index][name] = value
+	}
+
+	sort.Ints(indexes)
+
+	list := make([]form.Values, len(indexes))
+	for i, index := range indexes {
+		list[i] = rows[index]
+		// an unselected multiple select is not posted.
+		for _, child := range h.Form.FieldList {
+			if child.FormType.IsMultiSelect() {
+				if _, ok := list[i][child.Field+"[]"]; !ok {
+					list[i][child.Field+"[]"] = []string{""}
+				}
+			}
+		}
+	}
+	return list, true
+}
+
+// Validate check the must fields of the posted child rows and run the
+// validator of the child form. A value of a child field which the user can
+// not change is rejected.
+func (h HasMany) Validate(head string, rows []form.Values) error {
+	for i, row := range rows {
+		isNew := row.Get(h.PrimaryKey) == ""
+		for _, child := range h.Form.FieldList {
+			if child.Access == FieldEditable || child.Field == h.PrimaryKey {
+				continue
+			}
+			_, posted := row[child.Field]
+			_, postedMulti := row[child.Field+"[]"]
+			if posted || postedMulti {
+				return hasManyError(head, i, child.Head+" "+language.Get("permission denied"))
+			}
+		}
+		for _, child := range h.Form.FieldList {
+			if !child.Must || child.Field == h.PrimaryKey || (isNew && child.NotAllowAdd) || (!isNew && !child.Editable) {
+				continue
+			}
+			if strings.TrimSpace(row.Get(child.Field)+strings.Join(row[child.Field+"[]"], "")) == "" {
+				return hasManyError(head, i, child.Head+" "+language.Get("is required"))
+			}
+		}
+		if h.Form.Validator != nil {
+			if isNew {
+				row.Add(form.PostTypeKey, "1")
+			} else {
+				row.Add(form.PostTypeKey, "0")
+			}
+			err := h.Form.Validator(row)
+			row.Delete(form.PostTypeKey)
+			if err != nil {
+				return hasManyError(head, i, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+func hasManyError(head string, index int, msg string) error {
+	return errors.New(head + " #" + strconv.Itoa(index+1) + ": " + msg)
+}
+
+type hasManyRow struct {
+	Cells  []template.HTML
+	Hidden template.HTML
+}
+
+var hasManyTmpl = template.Must(template.New("has_many").Funcs(template.FuncMap{"lang": language.Get}).Parse(`{{define "row"}}<tr class="has-many-row">
+{{- range .Cells}}<td>{{.}}</td>{{end -}}
+<td style="white-space: nowrap;">{{.Hidden}}
+<button type="button" class="btn btn-xs btn-default has-many-up" title="{{lang "move up"}}"><i class="fa fa-arrow-up"></i></button>
+<button type="button" class="btn btn-xs btn-default has-many-down" title="{{lang "move down"}}"><i class="fa fa-arrow-down"></i></button>
+<button type="button" class="btn btn-xs btn-danger has-many-remove" title="{{lang "delete"}}"><i class="fa fa-trash"></i></button>
+</td></tr>{{end}}<div class="has-many" id="has-many-{{.Field}}" style="width: 100%;">
+<input type="hidden" name="{{.Field}}" value="1">
+<table class="table table-bordered" style="margin-bottom: 8px;">
+<thead><tr>{{range .Heads}}<th>{{.}}</th>{{end}}<th style="width: 100px;"></th></tr></thead>
+<tbody class="has-many-rows">{{range .Rows}}{{template "row" .}}{{end}}</tbody>
+</table>
+<template class="has-many-template">{{template "row" .Template}}</template>
+<button type="button" class="btn btn-sm btn-default has-many-add"><i class="fa fa-plus"></i> {{lang "new"}}</button>
+</div>`))
+
+const hasManyJS = `(function () {
+	let field = %s;
+	let box = $('#has-many-' + field);
+	let prefix = field + '[';
+	let renumber = function () {
+		box.find('.has-many-rows > .has-many-row').each(function (i) {
+			$(this).find('[name]').each(function () {
+				if (this.name.indexOf(prefix) === 0) {
+					this.name = prefix + i + this.name.substr(this.name.indexOf(']', prefix.length));
+				}
+			});
+		});
+	};
+	box.on('click', '.has-many-add', function () {
+		box.find('.has-many-rows').append(box.find('.has-many-template').html());
+		renumber();
+	});
+	box.on('click', '.has-many-remove', function () {
+		$(this).closest('.has-many-row').remove();
+		renumber();
+	});
+	box.on('click', '.has-many-up', function () {
+		let row = $(this).closest('.has-many-row');
+		row.prev('.has-many-row').before(row);
+		renumber();
+	});
+	box.on('click', '.has-many-down', function () {
+		let row = $(this).closest('.has-many-row');
+		row.next('.has-many-row').after(row);
+		renumber();
+	});
+})();`
+
+func (h HasMany) render(field string, rows []map[string]interface{}, sql ...func() *db.SQL) (template.HTML, template.JS) {
+
+	heads := make([]string, 0)
+	for _, child := range h.Form.FieldList {
+		if child.Field != h.PrimaryKey && !child.Hide && child.Access != FieldHidden {
+			heads = append(heads, child.Head)
+		}
+	}
+
+	list := make([]hasManyRow, len(rows))
+	for i, row := range rows {
+		columns := make([]string, 0, len(row))
+		for column := range row {
+			columns = append(columns, column)
+		}
+		id := fmt.Sprintf("%v", row[h.PrimaryKey])
+		list[i] = h.row(field, strconv.Itoa(i), h.Form.FieldsWithValue(id, columns, row, sql...), false)
+	}
+
+	buf := new(bytes.Buffer)
+	_ = hasManyTmpl.Execute(buf, map[string]interface{}{
+		"Field":    field,
+		"Heads":    heads,
+		"Rows":     list,
+		"Template": h.row(field, "", h.Form.FieldsWithDefaultValue(sql...), true),
+	})
+
+	name, _ := json.Marshal(field)
+
+	return template.HTML(buf.String()), template.JS(strings.Replace(hasManyJS, "%s", string(name), 1))
+}
+
+func (h HasMany) row(field, index string, fields FormFields, isNew bool) hasManyRow {
+	row := hasManyRow{Cells: make([]template.HTML, 0, len(h.Form.FieldList))}
+	for _, child := range h.Form.FieldList {
+		// the fields hidden from the user are never rendered, not even as
+		// the hidden inputs.
+		if child.Access == FieldHidden && child.Field != h.PrimaryKey {
+			continue
+		}
+		var value FormField
+		for _, f := range fields {
+			if f.Field == child.Field {
+				value = f
+				break
+			}
+		}
+		if value.Field == "" {
+			// the field not allowed to add is not in the default fields.
+			value = child
+		}
+		name := field + "[" + index + "][" + child.Field + "]"
+		disabled := (isNew && child.NotAllowAdd) || (!isNew && !child.Editable)
+		if child.Field == h.PrimaryKey || child.Hide {
+			row.Hidden += hasManyInput(name, value, "hidden", disabled)
+			continue
+		}
+		row.Cells = append(row.Cells, hasManyInput(name, value, hasManyInputType(child.FormType), disabled))
+	}
+	return row
+}
+
+var hasManyInputTmpl = template.Must(template.New("has_many_input").Parse(`
+{{- if eq .Type "static"}}<p class="form-control-static">{{.Value}}</p>
+{{- else if eq .Type "hidden"}}<input type="hidden" name="{{.Name}}" value="{{.Value}}"{{if .Disabled}} disabled{{end}}>
+{{- else if eq .Type "textarea"}}<textarea class="form-control" rows="2" name="{{.Name}}" placeholder="{{.Placeholder}}"{{if .Must}} required{{end}}{{if .Disabled}} disabled{{end}}>{{.Value}}</textarea>
+{{- else if eq .Type "select"}}<select class="form-control" name="{{.Name}}{{if .Multiple}}[]{{end}}"{{if .Multiple}} multiple{{end}}{{if .Must}} required{{end}}{{if .Disabled}} disabled{{end}}>
+{{- if not .Multiple}}<option value=""></option>{{end}}
+{{- range .Options}}<option value="{{.Value}}"{{if .Selected}} selected{{end}}>{{.Text}}</option>{{end -}}
+</select>
+{{- else}}<input type="{{.Type}}" class="form-control" name="{{.Name}}" value="{{.Value}}" placeholder="{{.Placeholder}}"{{if eq .Type "number"}} step="any"{{end}}{{if .Must}} required{{end}}{{if .Disabled}} disabled{{end}}>
+{{- end}}`))
+
+func hasManyInputType(formType form2.Type) string {
+	switch {
+	case formType == form2.Default:
+		return "static"
+	case formType == form2.TextArea || formType == form2.RichText:
+		return "textarea"
+	case formType.IsSelect():
+		return "select"
+	case formType == form2.Number || formType == form2.Currency:
+		return "number"
+	case formType == form2.Password:
+		return "password"
+	case formType == form2.Email:
+		return "email"
+	case formType == form2.Url:
+		return "url"
+	case formType == form2.Color:
+		return "color"
+	}
+	return "text"
+}
+
+func hasManyInput(name string, field FormField, typ string, disabled bool) template.HTML {
+	// the passwords are never sent back to the browser.
+	if field.FormType == form2.Password {
+		field.Value = ""
+	}
+	buf := new(bytes.Buffer)
+	_ = hasManyInputTmpl.Execute(buf, map[string]interface{}{
+		"Type":        typ,
+		"Name":        name,
+		"Value":       string(field.Value),
+		"Placeholder": field.Placeholder,
+		"Options":     field.Options,
+		"Multiple":    field.FormType.IsMultiSelect(),
+		"Must":        field.Must,
+		"Disabled":    disabled,
+	})
+	return template.HTML(buf.String())
+}
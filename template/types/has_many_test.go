@@ -0,0 +1,115 @@
+package types
+
+import (
+	"errors"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func testHasMany() HasMany {
+	child := NewFormPanel()
+	child.AddField("ID", "id", db.Int, form.Default)
+	child.AddField("Name", "name", db.Varchar, form.Text).FieldMust()
+	child.AddField("Tags", "tags", db.Varchar, form.Select).
+		FieldOptions(FieldOptions{{Text: "A", Value: "a"}, {Text: "B", Value: "b"}})
+	child.AddField("Secret", "secret", db.Varchar, form.Password)
+	child.AddField("Salary", "salary", db.Int, form.Number).FieldPermission(FieldEditable, "hr")
+	child.SetTable("items")
+
+	f := NewFormPanel()
+	f.AddField("Items", "items", db.Varchar, form.Custom).FieldHasMany(child, "id", "user_id", "sort")
+	return f.FieldList[0].HasMany
+}
+
+func TestHasMany_Rows(t *testing.T) {
+	h := testHasMany()
+
+	_, ok := h.Rows("items", form2.Values{"name": {"jack"}})
+	assert.False(t, ok)
+
+	rows, ok := h.Rows("items", form2.Values{
+		"items":             {"1"},
+		"items[10][name]":   {"b"},
+		"items[2][id]":      {"3"},
+		"items[2][name]":    {"a"},
+		"items[2][tags][]":  {"a", "b"},
+		"items[x][name]":    {"ignored"},
+		"others[0][name]":   {"ignored"},
+		"items[10][secret]": {"pass"},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, []form2.Values{
+		{"id": {"3"}, "name": {"a"}, "tags[]": {"a", "b"}},
+		{"name": {"b"}, "secret": {"pass"}, "tags[]": {""}},
+	}, rows)
+
+	rows, ok = h.Rows("items", form2.Values{"items": {"1"}})
+	assert.True(t, ok)
+	assert.Len(t, rows, 0)
+}
+
+func TestHasMany_Validate(t *testing.T) {
+	h := testHasMany()
+
+	assert.Nil(t, h.Validate("Items", []form2.Values{{"name": {"a"}}}))
+	assert.Equal(t, "Items #2: Name is required",
+		h.Validate("Items", []form2.Values{{"name": {"a"}}, {"name": {" "}}}).Error())
+
+	// the must fields not allowed to edit are not checked.
+	h.Form.FieldList[1].Editable = false
+	assert.Nil(t, h.Validate("Items", []form2.Values{{"id": {"1"}}}))
+	assert.Error(t, h.Validate("Items", []form2.Values{{"id": {""}}}))
+	h.Form.FieldList[1].Editable = true
+
+	var postType []string
+	h.Form.SetPostValidator(func(values form2.Values) error {
+		postType = append(postType, values.Get(form2.PostTypeKey))
+		if values.Get("name") == "bad" {
+			return errors.New("bad name")
+		}
+		return nil
+	})
+	assert.Equal(t, "Items #2: bad name",
+		h.Validate("Items", []form2.Values{{"id": {"1"}, "name": {"a"}}, {"name": {"bad"}}}).Error())
+	assert.Equal(t, []string{"0", "1"}, postType)
+
+	// the values of the fields which the user can not change are rejected.
+	h.Form = &FormPanel{FieldList: h.Form.FieldList.ForUser(models.UserModel{Id: 2})}
+	assert.Nil(t, h.Validate("Items", []form2.Values{{"name": {"a"}}}))
+	assert.Error(t, h.Validate("Items", []form2.Values{{"name": {"a"}, "salary": {"100"}}}))
+}
+
+func TestHasMany_Render(t *testing.T) {
+	h := testHasMany()
+
+	content, js := h.render("items", []map[string]interface{}{
+		{"id": int64(3), "name": "jack", "tags": "b", "secret": "pass", "salary": int64(100)},
+	})
+	html := string(content)
+
+	assert.Contains(t, html, `name="items[0][id]" value="3"`)
+	assert.Contains(t, html, `name="items[0][name]" value="jack"`)
+	assert.Contains(t, html, `<option value="b" selected>B</option>`)
+	assert.Contains(t, html, `name="items[][name]" value=""`)
+	assert.Contains(t, html, `name="items[0][salary]" value="100"`)
+	assert.Contains(t, string(js), `let field = "items";`)
+
+	// the passwords are never rendered.
+	assert.Contains(t, html, `name="items[0][secret]" value=""`)
+	assert.NotContains(t, html, `value="pass"`)
+
+	// the fields hidden from the user are not rendered at all.
+	fields := FormFields{{Field: "items", HasMany: h}}.ForUser(models.UserModel{Id: 2})
+	assert.Equal(t, FieldEditable, h.Form.FieldList[4].Access)
+	content, _ = fields[0].HasMany.render("items", []map[string]interface{}{
+		{"id": int64(3), "name": "jack", "salary": int64(100)},
+	})
+	html = string(content)
+	assert.Contains(t, html, `name="items[0][name]" value="jack"`)
+	assert.False(t, strings.Contains(html, "salary") || strings.Contains(html, "Salary"))
+}
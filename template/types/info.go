@@ -1,6 +1,8 @@
 package types
 
 import (
+	"bytes"
+	"database/sql"
 	"encoding/json"
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/db"
@@ -31,10 +33,12 @@ type FieldModel struct {
 }
 
 // PostFieldModel contains ID and value of the single query result and the current row data.
+// Tx is the transaction of the writing, it is nil when the form is handled by a custom function.
 type PostFieldModel struct {
 	ID    string
 	Value FieldModelValue
 	Row   map[string]interface{}
+	Tx    *sql.Tx
 }
 
 type InfoList []map[string]InfoItem
@@ -104,7 +108,8 @@ type Field struct {
 	Field    string
 	TypeName db.DatabaseType
 
-	Join Join
+	Join  Join
+	Joins Joins
 
 	Width      int
 	Sortable   bool
@@ -112,6 +117,12 @@ type Field struct {
 	Fixed      bool
 	Filterable bool
 	Hide       bool
+	Groupable  bool
+	Searchable bool
+
+	Aggregates []Aggregate
+
+	Permissions FieldPermissions
 
 	EditType    table.Type
 	EditOptions FieldOptions
@@ -223,25 +234,22 @@ func (f FieldList) GetTheadAndFilterForm(info TableInfo, params parameter.Parame
 		joins      = ""
 		joinTables = make([]string, 0)
 		filterForm = make([]FormField, 0)
+		group      = f.IsGroupBy()
 	)
 	for _, field := range f {
+		chain := field.JoinChain()
+
 		if field.Field != info.PrimaryKey && modules.InArray(columns, field.Field) &&
-			!field.Join.Valid() {
+			!chain.Valid() {
 			fields += info.Table + "." + modules.FilterField(field.Field, info.Delimiter) + ","
 		}
 
 		headField := field.Field
 
-		if field.Join.Valid() {
-			headField = field.Join.Table + parameter.FilterParamJoinInfix + field.Field
-			joinFields += db.GetAggregationExpression(info.Driver, field.Join.Table+"."+
-				modules.FilterField(field.Field, info.Delimiter), headField, JoinFieldValueDelimiter) + ","
-			if !modules.InArray(joinTables, field.Join.Table) {
-				joinTables = append(joinTables, field.Join.Table)
-				joins += " left join " + modules.FilterField(field.Join.Table, info.Delimiter) + " on " +
-					field.Join.Table + "." + modules.FilterField(field.Join.JoinField, info.Delimiter) + " = " +
-					info.Table + "." + modules.FilterField(field.Join.Field, info.Delimiter)
-			}
+		if chain.Valid() {
+			headField = field.HeadField()
+			joinFields += chain.selectField(info, field.Field, headField, group) + ","
+			joins, joinTables = chain.statement(info, joins, joinTables)
 		}
 
 		if field.Filterable {
@@ -272,25 +280,22 @@ func (f FieldList) GetThead(info TableInfo, params parameter.Parameters, columns
 		fields     = ""
 		joins      = ""
 		joinTables = make([]string, 0)
+		group      = f.IsGroupBy()
 	)
 	for _, field := range f {
+		chain := field.JoinChain()
+
 		if field.Field != info.PrimaryKey && modules.InArray(columns, field.Field) &&
-			!field.Join.Valid() {
+			!chain.Valid() {
 			fields += info.Table + "." + modules.FilterField(field.Field, info.Delimiter) + ","
 		}
 
 		headField := field.Field
 
-		if field.Join.Valid() {
-			headField = field.Join.Table + parameter.FilterParamJoinInfix + field.Field
-			fields += db.GetAggregationExpression(info.Driver, field.Join.Table+"."+
-				modules.FilterField(field.Field, info.Delimiter), headField, JoinFieldValueDelimiter) + ","
-			if !modules.InArray(joinTables, field.Join.Table) {
-				joinTables = append(joinTables, field.Join.Table)
-				joins += " left join " + modules.FilterField(field.Join.Table, info.Delimiter) + " on " +
-					field.Join.Table + "." + modules.FilterField(field.Join.JoinField, info.Delimiter) + " = " +
-					info.Table + "." + modules.FilterField(field.Join.Field, info.Delimiter)
-			}
+		if chain.Valid() {
+			headField = field.HeadField()
+			fields += chain.selectField(info, field.Field, headField, group) + ","
+			joins, joinTables = chain.statement(info, joins, joinTables)
 		}
 
 		if field.Hide {
@@ -323,10 +328,12 @@ func (f FieldList) GetFieldFilterProcessValue(key, value, keyIndex string) strin
 	return value
 }
 
+// GetFieldJoinTable return the name of the last joined table of the field,
+// the key is the field name or the head field of the join.
 func (f FieldList) GetFieldJoinTable(key string) string {
 	field := f.GetFieldByFieldName(key)
 	if field.Exist() {
-		return field.Join.Table
+		return field.JoinChain().Last().Name()
 	}
 	return ""
 }
@@ -336,17 +343,80 @@ func (f FieldList) GetFieldByFieldName(name string) Field {
 		if field.Field == name {
 			return field
 		}
-		if JoinField(field.Join.Table, field.Field) == name {
+		if field.JoinChain().Valid() && field.HeadField() == name {
 			return field
 		}
 	}
 	return Field{}
 }
 
+// IsGroupBy check the rows should be grouped by the primary key or not,
+// which is true when any join of the fields may match more than one row.
+func (f FieldList) IsGroupBy() bool {
+	for _, field := range f {
+		chain := field.JoinChain()
+		if !chain.Valid() {
+			continue
+		}
+		for _, join := range chain {
+			if !join.Single {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// JoinChain return the join chain of the field, which is the Join followed
+// by the Joins.
+func (f Field) JoinChain() Joins {
+	if f.Join.Table == "" && f.Join.Field == "" && f.Join.JoinField == "" {
+		return f.Joins
+	}
+	return append(Joins{f.Join}, f.Joins...)
+}
+
+// HeadField return the field name of the joined field in the result set.
+func (f Field) HeadField() string {
+	chain := f.JoinChain()
+	if !chain.Valid() {
+		return f.Field
+	}
+	return JoinField(chain.Last().Name(), f.Field)
+}
+
+const (
+	JoinLeft  = "left"
+	JoinInner = "inner"
+)
+
+// Join joins the JoinField of the Table with the Field of the BaseTable,
+// which is the table of the previous join or the info table by default.
+//
+// The Alias names the joined table, so that a table can be joined more
+// than once. JoinType is JoinLeft by default. Condition is a raw sql
+// constraint added to the join condition, such as "r.type = 'admin'".
+//
+// Single means the join matches at most one row, so the joined values are
+// not aggregated and the rows are not grouped by the primary key.
 type Join struct {
 	Table     string
 	Field     string
 	JoinField string
+
+	BaseTable string
+	Alias     string
+	JoinType  string
+	Condition string
+	Single    bool
+}
+
+// Name return the name of the joined table used in the statements.
+func (j Join) Name() string {
+	if j.Alias != "" {
+		return j.Alias
+	}
+	return j.Table
 }
 
 func JoinField(table, field string) string {
@@ -361,6 +431,77 @@ func (j Join) Valid() bool {
 	return j.Table != "" && j.Field != "" && j.JoinField != ""
 }
 
+// Joins is a chain of joins, the field is selected from the last one.
+type Joins []Join
+
+func (j Joins) Valid() bool {
+	for _, join := range j {
+		if !join.Valid() {
+			return false
+		}
+	}
+	return len(j) > 0
+}
+
+func (j Joins) Last() Join {
+	if len(j) == 0 {
+		return Join{}
+	}
+	return j[len(j)-1]
+}
+
+// statement add the join clauses of the chain which are not in the joined
+// tables to joins.
+func (j Joins) statement(info TableInfo, joins string, joinTables []string) (string, []string) {
+	base := info.Table
+	for _, join := range j {
+		if join.BaseTable != "" {
+			base = join.BaseTable
+		}
+		name := join.Name()
+		if !modules.InArray(joinTables, name) {
+			joinTables = append(joinTables, name)
+			joinType := JoinLeft
+			if join.JoinType == JoinInner {
+				joinType = JoinInner
+			}
+			joins += " " + joinType + " join " + modules.FilterField(join.Table, info.Delimiter)
+			if join.Alias != "" {
+				joins += " " + modules.FilterField(join.Alias, info.Delimiter)
+			}
+			joins += " on " + name + "." + modules.FilterField(join.JoinField, info.Delimiter) + " = " +
+				base + "." + modules.FilterField(join.Field, info.Delimiter)
+			if join.Condition != "" {
+				joins += " and " + join.Condition
+			}
+		}
+		base = name
+	}
+	return joins, joinTables
+}
+
+// selectField return the select expression of the joined field, which is
+// aggregated when the rows are grouped.
+func (j Joins) selectField(info TableInfo, field, headField string, group bool) string {
+	column := j.Last().Name() + "." + modules.FilterField(field, info.Delimiter)
+	if group {
+		return db.GetAggregationExpression(info.Driver, column, headField, JoinFieldValueDelimiter)
+	}
+	if info.Driver == db.DriverMssql {
+		return column + " as [" + headField + "]"
+	}
+	return column + " as " + headField
+}
+
+// SortField return the order by expression of the joined field.
+func (j Joins) SortField(info TableInfo, field string, group bool) string {
+	column := j.Last().Name() + "." + modules.FilterField(field, info.Delimiter)
+	if group {
+		return "max(" + column + ")"
+	}
+	return column
+}
+
 var JoinFieldValueDelimiter = utils.Uuid(8)
 
 type TabGroups [][]string
@@ -388,6 +529,10 @@ type GetDataFn func(param parameter.Parameters) ([]map[string]interface{}, int)
 
 type DeleteFn func(ids []string) error
 
+// TxDeleteFn is called within the transaction of the deletion, the
+// deletion will be rolled back if it returns an error.
+type TxDeleteFn func(tx *sql.Tx, ids []string) error
+
 type Sort uint8
 
 const (
@@ -411,10 +556,14 @@ type InfoPanel struct {
 	Sort      Sort
 	SortField string
 
-	PageSizeList    []int
-	DefaultPageSize int
+	PageSizeList       []int
+	DefaultPageSize    int
+	IsKeysetPagination bool
+	CountMode          CountMode
 
-	ExportType int
+	ExportType      int
+	ExportFormats   []string
+	ExportChunkSize int
 
 	IsHideNewButton    bool
 	IsHideExportButton bool
@@ -427,8 +576,9 @@ type InfoPanel struct {
 	IsHideFilterArea   bool
 	FilterFormLayout   form.Layout
 
-	Wheres    Wheres
-	WhereRaws WhereRaw
+	Wheres      Wheres
+	WhereRaws   WhereRaw
+	RowPolicies RowPolicies
 
 	Callbacks Callbacks
 
@@ -436,9 +586,10 @@ type InfoPanel struct {
 
 	TableLayout string
 
-	DeleteHook  DeleteFn
-	PreDeleteFn DeleteFn
-	DeleteFn    DeleteFn
+	DeleteHook   DeleteFn
+	TxDeleteHook TxDeleteFn
+	PreDeleteFn  DeleteFn
+	DeleteFn     DeleteFn
 
 	GetDataFn GetDataFn
 
@@ -677,6 +828,123 @@ func (i *InfoPanel) IsExportValue() bool {
 	return i.ExportType == 1
 }
 
+// The formats of the exported file.
+const (
+	ExportFormatXLSX = "xlsx"
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+)
+
+// DefaultExportChunkSize is the number of rows read each time when exporting.
+const DefaultExportChunkSize = 1000
+
+// SetExportFormats set the formats the table can be exported to, the first
+// one is the default format.
+func (i *InfoPanel) SetExportFormats(formats ...string) *InfoPanel {
+	i.ExportFormats = formats
+	return i
+}
+
+func (i *InfoPanel) GetExportFormats() []string {
+	if len(i.ExportFormats) == 0 {
+		return []string{ExportFormatXLSX}
+	}
+	return i.ExportFormats
+}
+
+// ExportFormat return the format of given name if the table can be exported
+// to it, otherwise the default format.
+func (i *InfoPanel) ExportFormat(name string) string {
+	formats := i.GetExportFormats()
+	if modules.InArray(formats, name) {
+		return name
+	}
+	return formats[0]
+}
+
+type exportFormatItem struct {
+	Format string
+	URL    string
+	Active bool
+}
+
+var exportFormatSelectorTmpl = template.Must(template.New("export_format").Funcs(template.FuncMap{"lang": language.Get}).Parse(`
+<div class="btn-group pull-right" style="margin-right: 10px;">
+<button type="button" class="btn btn-sm btn-default dropdown-toggle" data-toggle="dropdown">
+<i class="fa fa-download"></i> {{lang "export format"}}: {{.Current}} <span class="caret"></span>
+</button>
+<ul class="dropdown-menu" role="menu">
+{{- range .Items}}<li{{if .Active}} class="active"{{end}}><a href="{{.URL}}">{{.Format}}</a></li>{{end}}
+</ul>
+</div>`))
+
+// ExportFormatSelector render the dropdown to choose the format of the
+// export button, url return the link of the format. It returns empty when
+// the table can only be exported to one format.
+func (i *InfoPanel) ExportFormatSelector(current string, url func(format string) string) template.HTML {
+	formats := i.GetExportFormats()
+	if len(formats) < 2 {
+		return ""
+	}
+	items := make([]exportFormatItem, len(formats))
+	for k, format := range formats {
+		items[k] = exportFormatItem{Format: format, URL: url(format), Active: format == current}
+	}
+	buf := new(bytes.Buffer)
+	_ = exportFormatSelectorTmpl.Execute(buf, map[string]interface{}{
+		"Current": current,
+		"Items":   items,
+	})
+	return template.HTML(buf.String())
+}
+
+// SetExportChunkSize set the number of rows read each time when exporting
+// all rows of the table.
+func (i *InfoPanel) SetExportChunkSize(size int) *InfoPanel {
+	i.ExportChunkSize = size
+	return i
+}
+
+func (i *InfoPanel) GetExportChunkSize() int {
+	if i.ExportChunkSize <= 0 {
+		return DefaultExportChunkSize
+	}
+	return i.ExportChunkSize
+}
+
+// CountMode is the way to count the total rows of the table.
+type CountMode uint8
+
+const (
+	// CountExact counts the filtered rows.
+	CountExact CountMode = iota
+	// CountEstimate takes the row count estimated by the statistics of the
+	// database, the total is unknown when the rows are filtered.
+	CountEstimate
+	// CountSkip skips the counting, the total is unknown.
+	CountSkip
+)
+
+// UseKeysetPagination paginate the table by the values of the sort column
+// and the primary key instead of the offset, the pages are linked by the
+// previous and next cursors. The sort column should not be nullable.
+func (i *InfoPanel) UseKeysetPagination() *InfoPanel {
+	i.IsKeysetPagination = true
+	return i
+}
+
+// EstimateCount shows the estimated row count of the table as the total.
+func (i *InfoPanel) EstimateCount() *InfoPanel {
+	i.CountMode = CountEstimate
+	return i
+}
+
+// SkipCount skips counting the total of the rows.
+func (i *InfoPanel) SkipCount() *InfoPanel {
+	i.CountMode = CountSkip
+	return i
+}
+
 func (i *InfoPanel) btnUUID() string {
 	return "info-btn-" + utils.Uuid(10)
 }
@@ -773,11 +1041,18 @@ func (i *InfoPanel) AddXssJsFilter() *InfoPanel {
 	return i
 }
 
+// SetDeleteHook set the hook which is called in a goroutine after the deletion.
 func (i *InfoPanel) SetDeleteHook(fn DeleteFn) *InfoPanel {
 	i.DeleteHook = fn
 	return i
 }
 
+// SetTxDeleteHook set the hook which is called within the transaction of the deletion.
+func (i *InfoPanel) SetTxDeleteHook(fn TxDeleteFn) *InfoPanel {
+	i.TxDeleteHook = fn
+	return i
+}
+
 func (i *InfoPanel) SetPreDeleteFn(fn DeleteFn) *InfoPanel {
 	i.PreDeleteFn = fn
 	return i
@@ -979,6 +1254,13 @@ func (i *InfoPanel) FieldFilterOnChooseDisable(value string, field ...string) *I
 	return i
 }
 
+// FieldSearchable make the field searched by the global search, only the
+// columns of the table can be searched.
+func (i *InfoPanel) FieldSearchable() *InfoPanel {
+	i.FieldList[i.curFieldListIndex].Searchable = true
+	return i
+}
+
 func (i *InfoPanel) FieldHide() *InfoPanel {
 	i.FieldList[i.curFieldListIndex].Hide = true
 	return i
@@ -989,6 +1271,16 @@ func (i *InfoPanel) FieldJoin(join Join) *InfoPanel {
 	return i
 }
 
+// FieldJoins set the join chain of the field, the field is selected from
+// the last joined table.
+func (i *InfoPanel) FieldJoins(joins ...Join) *InfoPanel {
+	if len(joins) > 0 {
+		i.FieldList[i.curFieldListIndex].Join = joins[0]
+		i.FieldList[i.curFieldListIndex].Joins = joins[1:]
+	}
+	return i
+}
+
 func (i *InfoPanel) FieldLimit(limit int) *InfoPanel {
 	i.FieldList[i.curFieldListIndex].DisplayProcessChains = i.FieldList[i.curFieldListIndex].AddLimit(limit)
 	return i
@@ -1 +1,57 @@
 package types
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFieldList_GetTheadJoins(t *testing.T) {
+	info := NewInfoPanel("id")
+	info.AddField("ID", "id", db.Int)
+	info.AddField("Author", "name", db.Varchar).FieldJoin(Join{
+		Table: "users", Field: "author_id", JoinField: "id", Single: true,
+	})
+	info.AddField("Editor", "name", db.Varchar).FieldJoins(Join{
+		Table: "users", Alias: "editors", Field: "editor_id", JoinField: "id", Single: true,
+	}, Join{
+		Table: "profiles", Alias: "editor_profiles", Field: "id", JoinField: "user_id",
+		JoinType: JoinInner, Condition: "editor_profiles.active = 1", Single: true,
+	})
+
+	tableInfo := TableInfo{Table: "posts", PrimaryKey: "id", Delimiter: "`", Driver: db.DriverMysql}
+	columns := []string{"id", "author_id", "editor_id"}
+
+	assert.False(t, info.FieldList.IsGroupBy())
+	assert.Equal(t, "users"+parameter.FilterParamJoinInfix+"name", info.FieldList[1].HeadField())
+	assert.Equal(t, "editor_profiles"+parameter.FilterParamJoinInfix+"name", info.FieldList[2].HeadField())
+	assert.Equal(t, "editor_profiles", info.FieldList.GetFieldJoinTable(info.FieldList[2].HeadField()))
+
+	thead, fields, joins := info.FieldList.GetThead(tableInfo, parameter.BaseParam(), columns)
+	assert.Equal(t, 3, len(thead))
+	assert.Equal(t, "users.`name` as "+info.FieldList[1].HeadField()+","+
+		"editor_profiles.`name` as "+info.FieldList[2].HeadField()+",", fields)
+	assert.Equal(t, " left join `users` on users.`id` = posts.`author_id`"+
+		" left join `users` `editors` on editors.`id` = posts.`editor_id`"+
+		" inner join `profiles` `editor_profiles` on editor_profiles.`user_id` = editors.`id`"+
+		" and editor_profiles.active = 1", joins)
+
+	// the joined values are aggregated when any join may match more rows.
+	info.AddField("Tag", "name", db.Varchar).FieldJoins(Join{
+		Table: "post_tags", Field: "id", JoinField: "post_id",
+	}, Join{
+		Table: "tags", Field: "tag_id", JoinField: "id",
+	})
+
+	assert.True(t, info.FieldList.IsGroupBy())
+
+	_, fields, joins = info.FieldList.GetThead(tableInfo, parameter.BaseParam(), columns)
+	assert.Contains(t, fields, "group_concat(users.`name` separator '"+JoinFieldValueDelimiter+"') as "+
+		info.FieldList[1].HeadField())
+	assert.Contains(t, fields, "group_concat(tags.`name` separator '"+JoinFieldValueDelimiter+"') as "+
+		info.FieldList[3].HeadField())
+	assert.Contains(t, joins, " left join `post_tags` on post_tags.`post_id` = posts.`id`"+
+		" left join `tags` on tags.`id` = post_tags.`tag_id`")
+	assert.Equal(t, "max(tags.`name`)", info.FieldList[3].JoinChain().SortField(tableInfo, "name", true))
+}
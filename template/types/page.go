@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/modules/menu"
 	"github.com/GoAdminGroup/go-admin/modules/system"
 	"github.com/GoAdminGroup/go-admin/modules/utils"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"html/template"
 	"strconv"
 )
@@ -83,11 +85,61 @@ func NewPage(user models.UserModel, menu menu.Menu, panel Panel, cfg config.Conf
 		IndexUrl:       cfg.GetIndexURL(),
 		CdnUrl:         cfg.AssetUrl,
 		CustomHeadHtml: cfg.CustomHeadHtml,
-		CustomFootHtml: cfg.CustomFootHtml,
+		CustomFootHtml: cfg.CustomFootHtml + navbarItemsHtml(user) + csrfTokenHtml(user),
 		AssetsList:     assetsList,
 	}
 }
 
+// navbarItems are the items added to the navbar of the pages of the login
+// users, which are kept in the order of their names added.
+var (
+	navbarItems     = make(map[string]template.HTML)
+	navbarItemNames = make([]string, 0)
+)
+
+// AddNavbarItem add an item to the navbar of the pages, which is a list
+// item of html. The item of the same name is replaced.
+func AddNavbarItem(name string, item template.HTML) {
+	if _, ok := navbarItems[name]; !ok {
+		navbarItemNames = append(navbarItemNames, name)
+	}
+	navbarItems[name] = item
+}
+
+// The navbar is rendered by the theme, so the items are moved into it once
+// the page is loaded.
+const navbarItemsJS = `<script>
+(function () {
+	let move = function () {
+		let items = document.getElementById('goadmin-navbar-items');
+		let nav = document.querySelector('.navbar-custom-menu > .navbar-nav') || document.querySelector('.navbar .navbar-nav');
+		if (!items || !nav) {
+			return;
+		}
+		while (items.lastElementChild) {
+			nav.insertBefore(items.lastElementChild, nav.firstChild);
+		}
+		items.parentNode.removeChild(items);
+	};
+	if (document.readyState === 'loading') {
+		document.addEventListener('DOMContentLoaded', move);
+	} else {
+		move();
+	}
+})();
+</script>`
+
+func navbarItemsHtml(user models.UserModel) template.HTML {
+	if user.Id == 0 || len(navbarItemNames) == 0 {
+		return ""
+	}
+	items := template.HTML(`<ul id="goadmin-navbar-items" style="display: none;">`)
+	for _, name := range navbarItemNames {
+		items += navbarItems[name]
+	}
+	return items + "</ul>" + navbarItemsJS
+}
+
 func NewPagePanel(panel Panel) Page {
 	return Page{
 		Panel: panel,
@@ -175,3 +227,52 @@ window.setTimeout(function(){
 type GetPanelFn func(ctx interface{}) (Panel, error)
 
 type GetPanelInfoFn func(ctx *context.Context) (Panel, error)
+
+// The ajax requests and the forms posted by the scripts of the theme carry
+// the csrf token of the session, which is read from the cookie.
+const csrfTokenJS = `<script>
+(function () {
+	let safe = /^(get|head|options)$/i;
+	let token = function () {
+		let match = document.cookie.match(/(?:^|;\s*)%s=([^;]*)/);
+		return match ? decodeURIComponent(match[1]) : '';
+	};
+	let addToken = function (form) {
+		if (safe.test(form.method) || form.querySelector('input[name="%s"]')) {
+			return;
+		}
+		let input = document.createElement('input');
+		input.type = 'hidden';
+		input.name = '%s';
+		input.value = token();
+		form.appendChild(input);
+	};
+	let submit = HTMLFormElement.prototype.submit;
+	HTMLFormElement.prototype.submit = function () {
+		addToken(this);
+		return submit.call(this);
+	};
+	document.addEventListener('submit', function (event) {
+		addToken(event.target);
+	}, true);
+	let setup = function () {
+		if (window.jQuery && !window.jQuery.goAdminCSRF) {
+			window.jQuery.goAdminCSRF = true;
+			window.jQuery.ajaxPrefilter(function (options, original, xhr) {
+				if (!options.crossDomain && !safe.test(options.type)) {
+					xhr.setRequestHeader('%s', token());
+				}
+			});
+		}
+	};
+	setup();
+	document.addEventListener('DOMContentLoaded', setup);
+})();
+</script>`
+
+func csrfTokenHtml(user models.UserModel) template.HTML {
+	if user.Id == 0 {
+		return ""
+	}
+	return template.HTML(fmt.Sprintf(csrfTokenJS, constant.CSRFCookieKey, form.TokenKey, form.TokenKey, constant.CSRFHeader))
+}
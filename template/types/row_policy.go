@@ -0,0 +1,166 @@
+package types
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
+)
+
+// RowPolicyValue return the value of the user which the field of the rows
+// is compared with, a slice is expanded for the operator in and not in.
+type RowPolicyValue func(user models.UserModel) interface{}
+
+// CurrentUserId is the RowPolicyValue of the id of the user.
+func CurrentUserId(user models.UserModel) interface{} {
+	return user.Id
+}
+
+// RowPolicy restricts the rows of the table which the users of the role
+// can see and change to the ones whose field matches the value of the user.
+// The field is a column of the table.
+type RowPolicy struct {
+	Role     string
+	Field    string
+	Operator string
+	Value    RowPolicyValue
+}
+
+type RowPolicies []RowPolicy
+
+// EveryRole is the role of the policies which apply to every user but the
+// super admin, in addition to the policies of the roles of the user.
+const EveryRole = "*"
+
+var rowPolicyOperators = []string{"=", "!=", "<>", ">", ">=", "<", "<=", "like", "in", "not in"}
+
+// AddRowPolicy add a row-level policy of the role of given slug, the
+// policies of a role are all required. The scope of a user is the union of
+// the scopes of the roles, so the user is not restricted when any role of
+// the user has no policy, and so is the super admin. The policies of
+// EveryRole apply to all the users but the super admin.
+func (i *InfoPanel) AddRowPolicy(role, field, operator string, value RowPolicyValue) *InfoPanel {
+	i.RowPolicies = append(i.RowPolicies, RowPolicy{
+		Role:     role,
+		Field:    field,
+		Operator: strings.ToLower(strings.TrimSpace(operator)),
+		Value:    value,
+	})
+	return i
+}
+
+// Restricted check the rows are restricted for the user.
+func (r RowPolicies) Restricted(user models.UserModel) bool {
+	if len(r) == 0 || user.IsSuperAdmin() {
+		return false
+	}
+	return r.hasRole(EveryRole) || r.rolesRestricted(user)
+}
+
+// rolesRestricted check every role of the user has policies.
+func (r RowPolicies) rolesRestricted(user models.UserModel) bool {
+	if len(user.Roles) == 0 {
+		return false
+	}
+	for _, role := range user.Roles {
+		if !r.hasRole(role.Slug) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r RowPolicies) hasRole(slug string) bool {
+	for _, policy := range r {
+		if policy.Role == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// Statement add the condition of the policies of the user to the wheres,
+// the columns are of given table. The wheres are returned as they are if
+// the user is not restricted.
+func (r RowPolicies) Statement(wheres string, whereArgs []interface{}, user models.UserModel,
+	table, delimiter string) (string, []interface{}) {
+
+	if !r.Restricted(user) {
+		return wheres, whereArgs
+	}
+
+	scopes := make([]string, 0, 2)
+
+	if r.hasRole(EveryRole) {
+		scopes = append(scopes, "("+r.statement(EveryRole, user, table, delimiter, &whereArgs)+")")
+	}
+
+	if r.rolesRestricted(user) {
+		roleScopes := make([]string, 0, len(user.Roles))
+		for _, role := range user.Roles {
+			roleScopes = append(roleScopes, r.statement(role.Slug, user, table, delimiter, &whereArgs))
+		}
+		scopes = append(scopes, "("+strings.Join(roleScopes, " or ")+")")
+	}
+
+	scope := strings.Join(scopes, " and ")
+	if len(scopes) > 1 {
+		scope = "(" + scope + ")"
+	}
+
+	if strings.TrimSpace(wheres) == "" {
+		return scope, whereArgs
+	}
+	return "(" + wheres + ") and " + scope, whereArgs
+}
+
+// statement return the condition of the policies of the role, which are
+// all required, and append the arguments of them to the args.
+func (r RowPolicies) statement(role string, user models.UserModel, table, delimiter string, whereArgs *[]interface{}) string {
+	conds := make([]string, 0)
+	for _, policy := range r {
+		if policy.Role != role {
+			continue
+		}
+		cond, args := policy.statement(user, table, delimiter)
+		conds = append(conds, cond)
+		*whereArgs = append(*whereArgs, args...)
+	}
+	return "(" + strings.Join(conds, " and ") + ")"
+}
+
+// statement return the condition of the policy, which matches no row if
+// the operator is not supported or there is no value to be in.
+func (p RowPolicy) statement(user models.UserModel, table, delimiter string) (string, []interface{}) {
+
+	if !modules.InArray(rowPolicyOperators, p.Operator) || p.Value == nil {
+		return "1 = 0", nil
+	}
+
+	column := table + "." + modules.FilterField(p.Field, delimiter)
+
+	value := p.Value(user)
+
+	if p.Operator != "in" && p.Operator != "not in" {
+		return column + " " + p.Operator + " ?", []interface{}{value}
+	}
+
+	args := make([]interface{}, 0)
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		for i := 0; i < v.Len(); i++ {
+			args = append(args, v.Index(i).Interface())
+		}
+	} else if value != nil {
+		args = append(args, value)
+	}
+
+	if len(args) == 0 {
+		if p.Operator == "in" {
+			return "1 = 0", nil
+		}
+		return "1 = 1", nil
+	}
+
+	return column + " " + p.Operator + " (" + strings.Repeat("?,", len(args)-1) + "?)", args
+}
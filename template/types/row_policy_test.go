@@ -0,0 +1,55 @@
+package types
+
+import (
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRowPolicies_Statement(t *testing.T) {
+	info := NewInfoPanel("id").
+		AddRowPolicy("writer", "owner_id", "=", CurrentUserId).
+		AddRowPolicy("sales", "region", "IN", func(user models.UserModel) interface{} {
+			return []string{"us", "eu"}
+		}).
+		AddRowPolicy("sales", "level", "drop", CurrentUserId)
+
+	writer := models.UserModel{Id: 3, Roles: []models.RoleModel{{Slug: "writer"}}}
+	wheres, args := info.RowPolicies.Statement("", nil, writer, "posts", "`")
+	assert.Equal(t, "((posts.`owner_id` = ?))", wheres)
+	assert.Equal(t, []interface{}{int64(3)}, args)
+
+	wheres, args = info.RowPolicies.Statement("a = ? or b = ?", []interface{}{1, 2}, writer, "posts", "`")
+	assert.Equal(t, "(a = ? or b = ?) and ((posts.`owner_id` = ?))", wheres)
+	assert.Equal(t, []interface{}{1, 2, int64(3)}, args)
+
+	both := models.UserModel{Id: 3, Roles: []models.RoleModel{{Slug: "writer"}, {Slug: "sales"}}}
+	wheres, args = info.RowPolicies.Statement("", nil, both, "posts", "`")
+	assert.Equal(t, "((posts.`owner_id` = ?) or (posts.`region` in (?,?) and 1 = 0))", wheres)
+	assert.Equal(t, []interface{}{int64(3), "us", "eu"}, args)
+
+	other := models.UserModel{Id: 3, Roles: []models.RoleModel{{Slug: "writer"}, {Slug: "other"}}}
+	assert.False(t, info.RowPolicies.Restricted(other))
+	wheres, _ = info.RowPolicies.Statement("a = 1", nil, other, "posts", "`")
+	assert.Equal(t, "a = 1", wheres)
+
+	assert.False(t, info.RowPolicies.Restricted(models.UserModel{Id: 3}))
+
+	admin := models.UserModel{Id: 1, Roles: []models.RoleModel{{Slug: "writer"}},
+		Permissions: []models.PermissionModel{{HttpPath: []string{"*"}}}}
+	assert.False(t, info.RowPolicies.Restricted(admin))
+
+	// the policies of every role apply to all the users but the super admin.
+	info.AddRowPolicy(EveryRole, "tenant_id", "=", CurrentUserId)
+
+	wheres, args = info.RowPolicies.Statement("", nil, writer, "posts", "`")
+	assert.Equal(t, "(((posts.`tenant_id` = ?)) and ((posts.`owner_id` = ?)))", wheres)
+	assert.Equal(t, []interface{}{int64(3), int64(3)}, args)
+
+	wheres, args = info.RowPolicies.Statement("", nil, other, "posts", "`")
+	assert.Equal(t, "((posts.`tenant_id` = ?))", wheres)
+	assert.Equal(t, []interface{}{int64(3)}, args)
+
+	assert.True(t, info.RowPolicies.Restricted(models.UserModel{Id: 3}))
+	assert.False(t, info.RowPolicies.Restricted(admin))
+}
@@ -17,19 +17,18 @@ func authTest(e *httpexpect.Expect) *http.Cookie {
 
 	printlnWithColor("login: show", "green")
 	e.GET(config.Get().Url("/login")).Expect().Status(200)
-	printlnWithColor("login: empty password", "green")
-	e.POST(config.Get().Url("/signin")).WithJSON(map[string]string{
+	printlnWithColor("login: without csrf token", "green")
+	e.POST(config.Get().Url("/signin")).WithForm(map[string]string{
 		"username": "admin",
-		"password": "",
-	}).Expect().Status(400)
+		"password": "admin",
+	}).Expect().Status(403)
+	printlnWithColor("login: empty password", "green")
+	signin(e, "admin", "").Status(400)
 
 	// login
 
 	printlnWithColor("login", "green")
-	sesID := e.POST(config.Get().Url("/signin")).WithForm(map[string]string{
-		"username": "admin",
-		"password": "admin",
-	}).Expect().Status(200).Cookie(auth.DefaultCookieKey).Raw()
+	sesID := signin(e, "admin", "admin").Status(200).Cookie(auth.DefaultCookieKey).Raw()
 
 	// logout: without login
 
@@ -46,16 +45,10 @@ func authTest(e *httpexpect.Expect) *http.Cookie {
 	// login again
 
 	printlnWithColor("login again", "green")
-	cookie1 := e.POST(config.Get().Url("/signin")).WithForm(map[string]string{
-		"username": "admin",
-		"password": "admin",
-	}).Expect().Status(200).Cookie(auth.DefaultCookieKey).Raw()
+	cookie1 := signin(e, "admin", "admin").Status(200).Cookie(auth.DefaultCookieKey).Raw()
 
 	printlnWithColor("login again：restrict users from logging in at the same time", "green")
-	cookie2 := e.POST(config.Get().Url("/signin")).WithForm(map[string]string{
-		"username": "admin",
-		"password": "admin",
-	}).Expect().Status(200).Cookie(auth.DefaultCookieKey).Raw()
+	cookie2 := signin(e, "admin", "admin").Status(200).Cookie(auth.DefaultCookieKey).Raw()
 
 	// login success
 
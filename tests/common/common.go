@@ -2,9 +2,12 @@ package common
 
 import (
 	"fmt"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/gavv/httpexpect"
 	"github.com/mgutz/ansi"
+	"net/http"
 	"regexp"
 )
 
@@ -37,6 +40,28 @@ func Test(e *httpexpect.Expect) {
 	normalTest(e, cookie)
 }
 
+// signin post the login form with the csrf token of the login page.
+func signin(e *httpexpect.Expect, username, password string) *httpexpect.Response {
+	token := e.GET(config.Get().Url("/login")).Expect().Status(200).
+		Cookie(constant.CSRFLoginCookieKey).Value().Raw()
+	return e.POST(config.Get().Url("/signin")).
+		WithCookie(constant.CSRFLoginCookieKey, token).
+		WithForm(map[string]string{
+			"username":    username,
+			"password":    password,
+			form.TokenKey: token,
+		}).Expect()
+}
+
+// csrfToken return the csrf token of the session, which is set in the
+// cookie by the pages.
+func csrfToken(e *httpexpect.Expect, sesID *http.Cookie) string {
+	return e.GET(config.Get().Url("/info/manager")).
+		WithCookie(sesID.Name, sesID.Value).
+		Expect().Status(200).
+		Cookie(constant.CSRFCookieKey).Value().Raw()
+}
+
 func printlnWithColor(msg string, color string) {
 	fmt.Println(ansi.Color(msg, color))
 }
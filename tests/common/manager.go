@@ -40,7 +40,8 @@ func managerTest(e *httpexpect.Expect, sesID *http.Cookie) {
 			form.PreviousKey:  config.Get().Url("/info/manager?__page=1&__pageSize=10&__sort=id&__sort_type=desc"),
 			"id":              "1",
 			form.TokenKey:     "123",
-		}).Expect().Status(200).Body().Contains("edit fail, wrong token")
+		}).Expect().Status(403).JSON().Object().
+		ValueEqual("code", 403)
 
 	// show form: without id
 
@@ -113,18 +114,12 @@ func managerTest(e *httpexpect.Expect, sesID *http.Cookie) {
 	// tester login: wrong password
 
 	printlnWithColor("tester login: wrong password", "green")
-	e.POST(config.Get().Url("/signin")).WithForm(map[string]string{
-		"username": "tester",
-		"password": "admin",
-	}).Expect().Status(400)
+	signin(e, "tester", "admin").Status(400)
 
 	// tester login success
 
 	printlnWithColor("tester login success", "green")
-	e.POST(config.Get().Url("/signin")).WithForm(map[string]string{
-		"username": "tester",
-		"password": "tester",
-	}).Expect().Status(200).JSON().Equal(map[string]interface{}{
+	signin(e, "tester", "tester").Status(200).JSON().Equal(map[string]interface{}{
 		"code": 200,
 		"data": map[string]interface{}{
 			"url": "/" + config.Get().UrlPrefix,
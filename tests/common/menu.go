@@ -107,6 +107,7 @@ func menuTest(e *httpexpect.Expect, sesID *http.Cookie) {
 		WithQuery("id", "9").
 		WithCookie(sesID.Name, sesID.Value).
 		WithMultipart().
+		WithFormField(form.TokenKey, token[1]).
 		Expect().Status(200).JSON().Object().
 		ValueEqual("code", 200).
 		ValueEqual("msg", "ok")
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/gavv/httpexpect"
 	"net/http"
 )
@@ -30,6 +31,7 @@ func normalTest(e *httpexpect.Expect, sesID *http.Cookie) {
 		WithCookie(sesID.Name, sesID.Value).
 		WithMultipart().
 		WithFormField("id", "1").
+		WithFormField(form.TokenKey, csrfToken(e, sesID)).
 		Expect().Status(200)
 
 	// show form: without id
@@ -120,6 +120,7 @@ func permissionTest(e *httpexpect.Expect, sesID *http.Cookie) {
 		WithCookie(sesID.Name, sesID.Value).
 		WithMultipart().
 		WithFormField("id", "4").
+		WithFormField(form.TokenKey, token[1]).
 		Expect().Status(200).JSON().Object().
 		ValueEqual("code", 200).
 		ValueEqual("msg", "ok")
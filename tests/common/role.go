@@ -114,6 +114,7 @@ func roleTest(e *httpexpect.Expect, sesID *http.Cookie) {
 		WithCookie(sesID.Name, sesID.Value).
 		WithMultipart().
 		WithFormField("id", "3").
+		WithFormField(form.TokenKey, token[1]).
 		Expect().Status(200).JSON().Object().
 		ValueEqual("code", 200).
 		ValueEqual("msg", "ok")